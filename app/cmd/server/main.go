@@ -1,7 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 
@@ -9,14 +17,22 @@ import (
 	"es-api/app/internal/handler"
 	clerkRepo "es-api/app/internal/repository/clerk"
 	dbRepo "es-api/app/internal/repository/db"
+	embeddingRepo "es-api/app/internal/repository/embedding"
 	gbizRepo "es-api/app/internal/repository/gbiz"
 	geminiRepo "es-api/app/internal/repository/gemini"
-	tavilyRepo "es-api/app/internal/repository/tavily"
+	llmRepo "es-api/app/internal/repository/llm"
+	openaiRepo "es-api/app/internal/repository/openai"
+	"es-api/app/internal/repository/search"
 	"es-api/app/internal/router"
 	"es-api/app/internal/usecase"
+	"es-api/app/middleware/admin"
 	"es-api/app/middleware/auth"
+	"es-api/app/middleware/quota"
 )
 
+// shutdownTimeout - 停止時に、処理中のリクエストと実行中の生成ジョブの終了を待つ最大時間
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	err := godotenv.Load()
 	if err != nil {
@@ -24,23 +40,100 @@ func main() {
 	}
 	dbConnManager := db.NewDBConnectionManager()
 	experienceRepository := dbRepo.NewExperienceRepositoryWithDBManager(dbConnManager)
+	experienceEntryRepository := dbRepo.NewExperienceEntryRepositoryWithDBManager(dbConnManager)
 	companyResearchRepository := dbRepo.NewCompanyResearchRepositoryWithDBManager(dbConnManager)
+	companyDetailRepository := dbRepo.NewCompanyDetailRepositoryWithDBManager(dbConnManager)
+	companyRepository := dbRepo.NewCompanyRepositoryWithDBManager(dbConnManager)
+	generationRepository := dbRepo.NewGenerationRepositoryWithDBManager(dbConnManager)
+	usageRepository := dbRepo.NewUsageRepositoryWithDBManager(dbConnManager)
+	generationJobRepository := dbRepo.NewGenerationJobRepositoryWithDBManager(dbConnManager)
+	questionCacheRepository := dbRepo.NewQuestionCacheRepositoryWithDBManager(dbConnManager)
+	promptTemplateRepository := dbRepo.NewPromptTemplateRepositoryWithDBManager(dbConnManager)
 	clerkAuthRepository := clerkRepo.NewClerkAuthRepository()
 	geminiRepository := geminiRepo.NewGeminiRepository()
-	tavilyRepository := tavilyRepo.NewTavilyRepository()
+	llmProviders := []llmRepo.LLMProvider{llmRepo.NewGeminiProvider(geminiRepository)}
+	// OpenAI互換API(llama.cpp、Ollamaなど)はベースURLが設定されている場合のみ有効にする
+	if baseURL := os.Getenv("OPENAI_COMPATIBLE_BASE_URL"); baseURL != "" {
+		openAIRepository := openaiRepo.NewOpenAICompatibleRepository(baseURL, os.Getenv("OPENAI_COMPATIBLE_API_KEY"))
+		models := strings.Split(os.Getenv("OPENAI_COMPATIBLE_MODELS"), ",")
+		llmProviders = append(llmProviders, llmRepo.NewOpenAICompatibleProvider(openAIRepository, models))
+	}
+	llmProviderRegistry := llmRepo.NewLLMProviderRegistry(llmProviders...)
+	// 経験情報の関連度の計算は、EMBEDDING_PROVIDER=geminiの場合のみGeminiを使い、それ以外はローカルで行う
+	embeddingRepository := embeddingRepo.NewHashedEmbeddingRepository(0)
+	if os.Getenv("EMBEDDING_PROVIDER") == "gemini" {
+		embeddingRepository = embeddingRepo.NewGeminiEmbeddingRepository(geminiRepository, os.Getenv("GEMINI_EMBEDDING_MODEL"))
+	}
+	// 企業情報の調査に使うWeb検索。SEARCH_PROVIDER=fixtureの場合はSEARCH_FIXTURE_DIRに記録した検索結果を返し、
+	// SEARCH_PROVIDER=recordの場合はTavilyの検索結果をSEARCH_FIXTURE_DIRに記録する
+	var searchProvider search.SearchProvider = search.NewTavilySearchProvider(os.Getenv("TAVILY_BASE_URL"), os.Getenv("TAVILY_API_KEY"))
+	switch os.Getenv("SEARCH_PROVIDER") {
+	case "fixture":
+		searchProvider = search.NewFixtureSearchProvider(os.Getenv("SEARCH_FIXTURE_DIR"))
+	case "record":
+		searchProvider = search.NewRecordingSearchProvider(searchProvider, os.Getenv("SEARCH_FIXTURE_DIR"))
+	}
 	gbizRepository := gbizRepo.NewGBizInfoRepository()
 	experienceUsecase := usecase.NewExperienceUsecase(experienceRepository)
-	companyUsecase := usecase.NewCompanyUsecase(gbizRepository)
+	experienceEntryUsecase := usecase.NewExperienceEntryUsecase(experienceEntryRepository)
+	companyUsecase := usecase.NewCompanyUsecase(gbizRepository, companyDetailRepository, companyRepository)
+	companyResearcher := usecase.NewCompanyResearcher(searchProvider, companyResearchRepository)
+	companyResearchUsecase := usecase.NewCompanyResearchUsecase(companyResearcher)
+	generationUsecase := usecase.NewGenerationUsecase(generationRepository)
+	usageUsecase := usecase.NewUsageUsecase(usageRepository, usecase.UsageQuotaFromEnv())
 	llmGenerateUsecase := usecase.NewLLMGenerateUsecase(
-		geminiRepository,
-		tavilyRepository,
+		llmProviderRegistry,
+		companyResearcher,
 		experienceRepository,
-		companyResearchRepository,
+		gbizRepository,
+		companyDetailRepository,
+		generationRepository,
+		usageRepository,
+		embeddingRepository,
+		questionCacheRepository,
+		promptTemplateRepository,
 	)
+	generationJobUsecase := usecase.NewGenerationJobUsecase(generationJobRepository)
+	promptTemplateUsecase := usecase.NewPromptTemplateUsecase(promptTemplateRepository)
+	// 生成ジョブはサーバー内のワーカーが実行する。停止中に実行していたジョブは再起動後に実行し直す
+	generationJobWorker := usecase.NewGenerationJobWorker(generationJobRepository, llmGenerateUsecase, usecase.GenerationJobIDPsFromEnv(dbConnManager.IDPs()))
+	// SIGTERM/SIGINTを受け取ったらワーカーを止める。中断したジョブは再起動後に実行し直す
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		generationJobWorker.Run(ctx)
+	}()
 	experienceHandler := handler.NewExperienceHandler(experienceUsecase)
+	experienceEntryHandler := handler.NewExperienceEntryHandler(experienceEntryUsecase)
 	llmGenerateHandler := handler.NewLLMGenerateHandler(llmGenerateUsecase)
 	companyHandler := handler.NewCompanyHandler(companyUsecase)
+	generationHandler := handler.NewGenerationHandler(generationUsecase)
+	usageHandler := handler.NewUsageHandler(usageUsecase)
+	companyResearchHandler := handler.NewCompanyResearchHandler(companyResearchUsecase)
+	generationJobHandler := handler.NewGenerationJobHandler(generationJobUsecase)
+	promptTemplateHandler := handler.NewPromptTemplateHandler(promptTemplateUsecase)
 	authMiddleware := auth.IDPAuthMiddleware(clerkAuthRepository, dbConnManager)
-	e := router.NewRouter(experienceHandler, llmGenerateHandler, companyHandler, authMiddleware)
-	e.Logger.Fatal(e.Start(":8080"))
+	quotaMiddleware := quota.QuotaMiddleware(usageUsecase)
+	adminMiddleware := admin.AdminMiddleware()
+	e := router.NewRouter(experienceHandler, experienceEntryHandler, llmGenerateHandler, companyHandler, generationHandler, usageHandler, companyResearchHandler, generationJobHandler, promptTemplateHandler, authMiddleware, quotaMiddleware, adminMiddleware)
+	go func() {
+		if err := e.Start(":8080"); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("サーバーを停止します")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("サーバーの停止中にエラーが発生しました: %v", err)
+	}
+	select {
+	case <-workerDone:
+	case <-shutdownCtx.Done():
+		log.Println("生成ジョブのワーカーの停止を待たずに終了します")
+	}
 }
@@ -14,6 +14,7 @@ import (
 
 type DBConnectionManager interface {
 	GetConnection(idp string) *gorm.DB
+	IDPs() []string
 }
 
 type dbConnectionManager struct {
@@ -44,6 +45,15 @@ func (m *dbConnectionManager) GetConnection(idp string) *gorm.DB {
 	}
 }
 
+// IDPs - 接続先のDBごとに、そのDBを使うidpを1つずつ返す。swaggerとtestは同じDBを使うのでswaggerのみ返す
+func (m *dbConnectionManager) IDPs() []string {
+	idps := []string{"clerk"}
+	if m.swaggerDB != nil {
+		idps = append(idps, "swagger")
+	}
+	return idps
+}
+
 func NewDB() *gorm.DB {
 	url := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		os.Getenv("DB_HOST"),
@@ -120,7 +130,19 @@ func NewTestDB() *gorm.DB {
 }
 
 func CleanupTestDB(db *gorm.DB) {
+	db.Exec("DELETE FROM llm_usages")
+	db.Exec("DELETE FROM generations")
+	db.Exec("DELETE FROM generation_jobs")
+	db.Exec("DELETE FROM question_caches")
+	db.Exec("DELETE FROM experience_episodes")
+	db.Exec("DELETE FROM experience_skills")
+	db.Exec("DELETE FROM experience_achievements")
 	db.Exec("DELETE FROM users")
 	db.Exec("DELETE FROM experiences")
+	db.Exec("DELETE FROM company_research_citations")
 	db.Exec("DELETE FROM company_researches")
+	db.Exec("DELETE FROM company_details")
+	db.Exec("DELETE FROM companies")
+	db.Exec("DELETE FROM company_search_queries")
+	db.Exec("DELETE FROM prompt_templates")
 }
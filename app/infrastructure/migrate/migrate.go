@@ -17,9 +17,122 @@ func RunMigrations(db *gorm.DB) {
 	if err != nil {
 		log.Fatalf("🔴 Error migrating Experience model: %s", err)
 	}
+	err = migrateExperienceProfiles(db)
+	if err != nil {
+		log.Fatalf("🔴 Error migrating experience profiles: %s", err)
+	}
+	err = db.AutoMigrate(&model.ExperienceEpisodes{}, &model.ExperienceSkills{}, &model.ExperienceAchievements{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating experience entry models: %s", err)
+	}
 	err = db.AutoMigrate(&model.CompanyResearch{})
 	if err != nil {
 		log.Fatalf("🔴 Error migrating CompanyResearch model: %s", err)
 	}
+	err = migrateCompanyResearchContents(db)
+	if err != nil {
+		log.Fatalf("🔴 Error migrating company research contents: %s", err)
+	}
+	err = db.AutoMigrate(&model.CompanyResearchCitations{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating CompanyResearchCitation model: %s", err)
+	}
+	err = db.AutoMigrate(&model.CompanyDetails{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating CompanyDetail model: %s", err)
+	}
+	err = db.AutoMigrate(&model.Companies{}, &model.CompanySearchQueries{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating company search models: %s", err)
+	}
+	err = migrateCompanySearchIndex(db)
+	if err != nil {
+		// インデックスがなくても検索はできるので、起動は止めない
+		log.Printf("🟡 Error creating company search index: %s", err)
+	}
+	err = db.AutoMigrate(&model.Generations{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating Generation model: %s", err)
+	}
+	err = db.AutoMigrate(&model.GenerationJobs{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating GenerationJob model: %s", err)
+	}
+	err = db.AutoMigrate(&model.QuestionCaches{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating QuestionCache model: %s", err)
+	}
+	err = db.AutoMigrate(&model.PromptTemplates{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating PromptTemplate model: %s", err)
+	}
+	err = db.AutoMigrate(&model.LLMUsages{})
+	if err != nil {
+		log.Fatalf("🔴 Error migrating LLMUsage model: %s", err)
+	}
 	log.Println("🟢 Migrations completed")
 }
+
+// migrateExperienceProfiles - 1ユーザー1件だった経験情報を、複数プロフィールに対応させる
+func migrateExperienceProfiles(db *gorm.DB) error {
+	// 旧スキーマのuser_idのユニーク制約を削除
+	if db.Migrator().HasIndex(&model.Experiences{}, "idx_experiences_user_id") {
+		if err := db.Migrator().DropIndex(&model.Experiences{}, "idx_experiences_user_id"); err != nil {
+			return err
+		}
+	}
+
+	// デフォルトのプロフィールがないユーザーは、最も古いプロフィールをデフォルトにする
+	err := db.Exec(`
+		UPDATE experiences e SET is_default = true
+		WHERE e.id = (
+			SELECT e2.id FROM experiences e2 WHERE e2.user_id = e.user_id ORDER BY e2.created_at LIMIT 1
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM experiences e3 WHERE e3.user_id = e.user_id AND e3.is_default
+		)`).Error
+	if err != nil {
+		return err
+	}
+
+	// デフォルトのプロフィールはユーザーごとに1件まで
+	return db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_experiences_user_default
+		ON experiences (user_id) WHERE is_default`).Error
+}
+
+// migrateCompanyResearchContents - 企業理念・キャリアパス・求める人材像のカラムを、調査項目ごとのcontentsに移す
+func migrateCompanyResearchContents(db *gorm.DB) error {
+	columns := []string{"philosophy", "career_path", "talent_needs"}
+	if !db.Migrator().HasColumn(&model.CompanyResearch{}, columns[0]) {
+		return nil
+	}
+
+	err := db.Exec(`
+		UPDATE company_researches SET contents = jsonb_strip_nulls(jsonb_build_object(
+			'philosophy', NULLIF(philosophy, ''),
+			'career_path', NULLIF(career_path, ''),
+			'talent_needs', NULLIF(talent_needs, '')
+		))
+		WHERE contents IS NULL OR contents = '{}'::jsonb`).Error
+	if err != nil {
+		return err
+	}
+
+	for _, column := range columns {
+		if err := db.Migrator().DropColumn(&model.CompanyResearch{}, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateCompanySearchIndex - 法人名の部分一致検索用にpg_trgmのGINインデックスを作成する
+func migrateCompanySearchIndex(db *gorm.DB) error {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return err
+	}
+	return db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_companies_normalized_name_trgm
+		ON companies USING gin (normalized_name gin_trgm_ops)`).Error
+}
@@ -4,14 +4,78 @@ import "time"
 
 // CompanyResearch - 企業情報のキャッシュ用モデル
 type CompanyResearch struct {
-	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
-	CompanyID   string    `json:"company_id" gorm:"unique;not null"` // gBizINFOの法人番号
-	CompanyName string    `json:"company_name" gorm:"not null"`      // 企業名
-	Philosophy  string    `json:"philosophy" gorm:"not null"`        // 企業理念
-	CareerPath  string    `json:"career_path" gorm:"not null"`       // キャリアパス
-	TalentNeeds string    `json:"talent_needs" gorm:"not null"`      // 求める人材像
-	CreatedAt   time.Time `json:"created_at" gorm:"not null"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"not null"`
+	ID          uint                       `json:"id" gorm:"primaryKey;autoIncrement"`
+	CompanyID   string                     `json:"company_id" gorm:"unique;not null"`                                // gBizINFOの法人番号
+	CompanyName string                     `json:"company_name" gorm:"not null"`                                     // 企業名
+	Contents    map[ResearchSection]string `json:"contents" gorm:"type:jsonb;serializer:json;not null;default:'{}'"` // 調査項目ごとの検索結果
+	SearchedAt  time.Time                  `json:"searched_at" gorm:"not null;default:CURRENT_TIMESTAMP"`            // 最後に検索した日時。検索に失敗した場合も更新する
+	CreatedAt   time.Time                  `json:"created_at" gorm:"not null"`
+	UpdatedAt   time.Time                  `json:"updated_at" gorm:"not null"`
+
+	Citations []CompanyResearchCitations `json:"citations,omitempty" gorm:"foreignKey:CompanyResearchID;constraint:OnDelete:CASCADE"`
+}
+
+// ResearchSection - 企業情報の調査項目の名前。調査項目は設定ファイルで定義する
+type ResearchSection string
+
+// デフォルトの調査項目
+const (
+	ResearchSectionPhilosophy  ResearchSection = "philosophy"   // 企業理念
+	ResearchSectionCareerPath  ResearchSection = "career_path"  // キャリアパス
+	ResearchSectionTalentNeeds ResearchSection = "talent_needs" // 求める人材像
+)
+
+// CompanyResearchCitations - 企業情報の項目ごとの出典(Tavilyの検索結果)
+type CompanyResearchCitations struct {
+	ID                uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	CompanyResearchID uint            `json:"-" gorm:"not null;index"`
+	Section           ResearchSection `json:"section" gorm:"not null"`
+	Title             string          `json:"title" gorm:"not null"`
+	URL               string          `json:"url" gorm:"not null"`
+	Content           string          `json:"content" gorm:"type:text;not null"` // 検索結果の抜粋
+	CreatedAt         time.Time       `json:"created_at" gorm:"not null"`
+}
+
+// IsComplete - 指定した調査項目が全て揃っているかどうか
+// 検索に一部失敗した場合や調査項目を追加した場合は空の項目が残るので、揃っていなければ再取得する
+func (r *CompanyResearch) IsComplete(sections []ResearchSection) bool {
+	for _, section := range sections {
+		if r.Contents[section] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ToCompanyInfo - プロンプトに含める企業情報に変換する
+func (r *CompanyResearch) ToCompanyInfo() *CompanyInfo {
+	return &CompanyInfo{
+		Name:      r.CompanyName,
+		Contents:  r.Contents,
+		Citations: r.Citations,
+	}
+}
+
+// SectionCitations - 指定した項目の出典
+func (r *CompanyResearch) SectionCitations(section ResearchSection) []CompanyResearchCitations {
+	var citations []CompanyResearchCitations
+	for _, c := range r.Citations {
+		if c.Section == section {
+			citations = append(citations, c)
+		}
+	}
+	return citations
+}
+
+// SearchedWithin - 最後の検索からinterval以内かどうか
+// 空の項目がある企業情報を、検索に失敗し続けている間に毎回検索し直さないようにする
+func (r *CompanyResearch) SearchedWithin(interval time.Duration, now time.Time) bool {
+	return now.Sub(r.SearchedAt) < interval
+}
+
+// IsStale - 最終更新からttl以上経過しているかどうか
+func (r *CompanyResearch) IsStale(ttl time.Duration, now time.Time) bool {
+	return now.Sub(r.UpdatedAt) >= ttl
 }
 
 // CompanyBasicInfo - 企業検索結果用の基本情報
@@ -20,6 +84,54 @@ type CompanyBasicInfo struct {
 	CompanyName string `json:"companyName"`
 }
 
+// Companies - 企業検索用のローカルインデックス。gBizINFOの検索結果で見つかった法人を保存する
+type Companies struct {
+	CorporateNumber string    `json:"corporateNumber" gorm:"primaryKey"` // 法人番号
+	Name            string    `json:"name" gorm:"not null"`              // 法人名
+	NormalizedName  string    `json:"-" gorm:"not null"`                 // 検索用に正規化した法人名(全角半角・カタカナを統一し、法人格を除く)
+	CreatedAt       time.Time `json:"createdAt" gorm:"not null"`
+	UpdatedAt       time.Time `json:"updatedAt" gorm:"not null"`
+}
+
+// ToBasicInfo - 企業検索結果に変換する
+func (c *Companies) ToBasicInfo() CompanyBasicInfo {
+	return CompanyBasicInfo{
+		CompanyID:   c.CorporateNumber,
+		CompanyName: c.Name,
+	}
+}
+
+// CompanySearchQueries - gBizINFOで検索済みのキーワード(正規化済み)
+// 同じキーワードでgBizINFOに何度も問い合わせないようにする
+type CompanySearchQueries struct {
+	Query      string    `gorm:"primaryKey"`
+	SearchedAt time.Time `gorm:"not null"`
+}
+
+// IsStale - 最終検索からttl以上経過しているかどうか
+func (q *CompanySearchQueries) IsStale(ttl time.Duration, now time.Time) bool {
+	return now.Sub(q.SearchedAt) >= ttl
+}
+
+// CompanyDetails - gBizINFOの法人情報のキャッシュ用モデル
+type CompanyDetails struct {
+	CorporateNumber string    `json:"corporateNumber" gorm:"primaryKey"` // 法人番号
+	Name            string    `json:"name" gorm:"not null"`
+	Address         string    `json:"address"`                          // 本社所在地
+	Capital         int64     `json:"capital"`                          // 資本金(円)。不明な場合は0
+	EmployeeCount   int       `json:"employeeCount"`                    // 従業員数。不明な場合は0
+	FoundedAt       string    `json:"foundedAt"`                        // 設立年月日(YYYY-MM-DD)。年しか分からない場合はYYYY
+	BusinessSummary string    `json:"businessSummary" gorm:"type:text"` // 事業概要
+	URL             string    `json:"url"`                              // 企業ホームページ
+	CreatedAt       time.Time `json:"createdAt" gorm:"not null"`
+	UpdatedAt       time.Time `json:"updatedAt" gorm:"not null"`
+}
+
+// IsStale - 最終更新からttl以上経過しているかどうか
+func (d *CompanyDetails) IsStale(ttl time.Duration, now time.Time) bool {
+	return now.Sub(d.UpdatedAt) >= ttl
+}
+
 // GBizInfoResponse - gBizINFO APIのレスポンス
 type GBizInfoResponse struct {
 	Response []struct {
@@ -27,3 +139,18 @@ type GBizInfoResponse struct {
 		Name            string `json:"name"`
 	} `json:"hojin-infos"`
 }
+
+// GBizInfoDetailResponse - gBizINFO APIの法人番号指定でのレスポンス
+type GBizInfoDetailResponse struct {
+	Response []struct {
+		CorporateNumber     string `json:"corporate_number"`
+		Name                string `json:"name"`
+		Location            string `json:"location"`
+		CapitalStock        int64  `json:"capital_stock"`
+		EmployeeNumber      int    `json:"employee_number"`
+		DateOfEstablishment string `json:"date_of_establishment"`
+		FoundingYear        int    `json:"founding_year"`
+		BusinessSummary     string `json:"business_summary"`
+		CompanyURL          string `json:"company_url"`
+	} `json:"hojin-infos"`
+}
@@ -4,9 +4,15 @@ import (
 	"time"
 )
 
+// DefaultExperienceProfileName - 名前を指定せずに作成したプロフィールの名前
+const DefaultExperienceProfileName = "デフォルト"
+
+// Experiences - ユーザーの経験情報。1人のユーザーが名前付きのプロフィールを複数持てる
 type Experiences struct {
 	ID          string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	UserID      string    `json:"userId" gorm:"uniqueIndex;not null;references:id"`
+	UserID      string    `json:"userId" gorm:"index:idx_experiences_user_profile;not null;references:id"`
+	Name        string    `json:"name" gorm:"not null;default:'デフォルト'"`
+	IsDefault   bool      `json:"isDefault" gorm:"not null;default:false"` // ユーザーごとに1件のみtrue
 	Work        string    `json:"work" gorm:"not null;"`
 	Skills      string    `json:"skills" gorm:"not null;"`
 	SelfPR      string    `json:"selfPR" gorm:"not null;"`
@@ -14,9 +20,16 @@ type Experiences struct {
 	CreatedAt   time.Time `json:"createdAt" gorm:"not null"`
 	UpdatedAt   time.Time `json:"updatedAt" gorm:"not null"`
 	User        Users     `json:"-" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	// 構造化された経験情報。プロフィール単体を取得した場合のみ読み込む
+	Episodes     []ExperienceEpisodes     `json:"episodes,omitempty" gorm:"foreignKey:ExperienceID"`
+	SkillEntries []ExperienceSkills       `json:"skillEntries,omitempty" gorm:"foreignKey:ExperienceID"`
+	Achievements []ExperienceAchievements `json:"achievements,omitempty" gorm:"foreignKey:ExperienceID"`
 }
 
 type InputExperience struct {
+	Name        string
+	IsDefault   bool
 	Work        string
 	Skills      string
 	SelfPR      string
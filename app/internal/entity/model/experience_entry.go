@@ -0,0 +1,102 @@
+package model
+
+import "time"
+
+// SkillLevel - スキルの習熟度
+type SkillLevel string
+
+const (
+	SkillLevelBeginner     SkillLevel = "beginner"
+	SkillLevelIntermediate SkillLevel = "intermediate"
+	SkillLevelAdvanced     SkillLevel = "advanced"
+	SkillLevelExpert       SkillLevel = "expert"
+)
+
+// IsValid - 定義済みの習熟度かどうか
+func (l SkillLevel) IsValid() bool {
+	switch l {
+	case SkillLevelBeginner, SkillLevelIntermediate, SkillLevelAdvanced, SkillLevelExpert:
+		return true
+	}
+	return false
+}
+
+// Label - プロンプトに埋め込む日本語の表記
+func (l SkillLevel) Label() string {
+	switch l {
+	case SkillLevelBeginner:
+		return "初級"
+	case SkillLevelIntermediate:
+		return "中級"
+	case SkillLevelAdvanced:
+		return "上級"
+	case SkillLevelExpert:
+		return "エキスパート"
+	}
+	return string(l)
+}
+
+// ExperienceEpisodes - 経験プロフィールに紐づくエピソード(STAR形式)
+type ExperienceEpisodes struct {
+	ID           string      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ExperienceID string      `json:"experienceId" gorm:"index;not null"`
+	UserID       string      `json:"-" gorm:"index;not null"`
+	Title        string      `json:"title" gorm:"not null"`
+	Period       string      `json:"period" gorm:"not null"` // 例: 2023年4月〜2024年3月
+	Role         string      `json:"role" gorm:"not null"`
+	Situation    string      `json:"situation" gorm:"not null"`
+	Action       string      `json:"action" gorm:"not null"`
+	Result       string      `json:"result" gorm:"not null"`
+	Metrics      string      `json:"metrics" gorm:"not null"` // 定量的な成果
+	CreatedAt    time.Time   `json:"createdAt" gorm:"not null"`
+	UpdatedAt    time.Time   `json:"updatedAt" gorm:"not null"`
+	Experience   Experiences `json:"-" gorm:"foreignKey:ExperienceID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// ExperienceSkills - 経験プロフィールに紐づくスキル
+type ExperienceSkills struct {
+	ID           string      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ExperienceID string      `json:"experienceId" gorm:"index;not null"`
+	UserID       string      `json:"-" gorm:"index;not null"`
+	Name         string      `json:"name" gorm:"not null"`
+	Level        SkillLevel  `json:"level" gorm:"not null"`
+	CreatedAt    time.Time   `json:"createdAt" gorm:"not null"`
+	UpdatedAt    time.Time   `json:"updatedAt" gorm:"not null"`
+	Experience   Experiences `json:"-" gorm:"foreignKey:ExperienceID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// ExperienceAchievements - 経験プロフィールに紐づく実績(受賞・資格など)
+type ExperienceAchievements struct {
+	ID           string      `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	ExperienceID string      `json:"experienceId" gorm:"index;not null"`
+	UserID       string      `json:"-" gorm:"index;not null"`
+	Title        string      `json:"title" gorm:"not null"`
+	Description  string      `json:"description" gorm:"not null"`
+	Tags         []string    `json:"tags" gorm:"type:jsonb;serializer:json"`
+	AchievedAt   string      `json:"achievedAt" gorm:"not null"` // 例: 2024年3月
+	CreatedAt    time.Time   `json:"createdAt" gorm:"not null"`
+	UpdatedAt    time.Time   `json:"updatedAt" gorm:"not null"`
+	Experience   Experiences `json:"-" gorm:"foreignKey:ExperienceID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+type InputEpisode struct {
+	Title     string
+	Period    string
+	Role      string
+	Situation string
+	Action    string
+	Result    string
+	Metrics   string
+}
+
+type InputSkill struct {
+	Name  string
+	Level SkillLevel
+}
+
+type InputAchievement struct {
+	Title       string
+	Description string
+	Tags        []string
+	AchievedAt  string
+}
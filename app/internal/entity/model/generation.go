@@ -0,0 +1,79 @@
+package model
+
+import "time"
+
+// Generations - LLMGenerateによる生成結果の履歴
+type Generations struct {
+	ID          string                 `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      string                 `json:"userId" gorm:"index;not null"`
+	CompanyID   string                 `json:"companyId" gorm:"not null"` // gBizINFOの法人番号
+	CompanyName string                 `json:"companyName" gorm:"not null"`
+	Model       string                 `json:"model" gorm:"not null"`
+	HTML        string                 `json:"html,omitempty" gorm:"not null"`              // リクエストされたESのHTML
+	Questions   []string               `json:"questions" gorm:"type:jsonb;serializer:json"` // 抽出された質問
+	Answers     []LLMGeneratedResponse `json:"answers" gorm:"type:jsonb;serializer:json"`   // 質問ごとの生成結果
+	// PromptVersionID - 回答生成に使ったプロンプトテンプレートのバージョン。ファイルのプロンプトを使った場合はnil
+	PromptVersionID *string `json:"promptVersionId,omitempty" gorm:"type:uuid;index"`
+	// SystemPromptVersionID - 回答生成に使ったシステムプロンプトのバージョン。ファイルのプロンプトを使った場合はnil
+	SystemPromptVersionID *string `json:"systemPromptVersionId,omitempty" gorm:"type:uuid;index"`
+	// ExtractionPromptVersionID - 質問抽出に使ったプロンプトテンプレートのバージョン。LLMを使わずに抽出した場合もnil
+	ExtractionPromptVersionID *string   `json:"extractionPromptVersionId,omitempty" gorm:"type:uuid;index"`
+	CreatedAt                 time.Time `json:"createdAt" gorm:"index;not null"`
+	UpdatedAt                 time.Time `json:"updatedAt" gorm:"not null"`
+	User                      Users     `json:"-" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// GenerationJobStatus - 非同期生成ジョブの状態
+type GenerationJobStatus string
+
+const (
+	GenerationJobStatusQueued    GenerationJobStatus = "queued"    // ワーカーの実行待ち
+	GenerationJobStatusRunning   GenerationJobStatus = "running"   // 実行中
+	GenerationJobStatusSucceeded GenerationJobStatus = "succeeded" // 1問以上の回答を生成できた
+	GenerationJobStatusFailed    GenerationJobStatus = "failed"    // 全ての質問で失敗した
+	GenerationJobStatusCanceled  GenerationJobStatus = "canceled"  // ユーザーがキャンセルした
+)
+
+// IsFinished - ジョブが終了しているかどうか
+func (s GenerationJobStatus) IsFinished() bool {
+	return s == GenerationJobStatusSucceeded || s == GenerationJobStatusFailed || s == GenerationJobStatusCanceled
+}
+
+// GenerationJobs - LLMGenerateをバックグラウンドで実行するジョブ
+// サーバーが再起動しても、DBに残ったジョブを別のワーカーが引き継いで実行する
+type GenerationJobs struct {
+	ID          string                  `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      string                  `json:"userId" gorm:"index;not null"`
+	Status      GenerationJobStatus     `json:"status" gorm:"index;not null"`
+	Request     LLMGenerateRequest      `json:"-" gorm:"type:jsonb;serializer:json;not null"` // 生成のリクエスト
+	CompanyID   string                  `json:"companyId" gorm:"not null"`
+	CompanyName string                  `json:"companyName" gorm:"not null"`
+	Progress    []GenerationJobQuestion `json:"progress" gorm:"type:jsonb;serializer:json"`          // 質問ごとの進捗。質問の抽出が終わるまでは空
+	Answers     []LLMGeneratedResponse  `json:"answers,omitempty" gorm:"type:jsonb;serializer:json"` // 終了時の生成結果
+	Error       string                  `json:"error,omitempty"`
+	// CancelRequested - キャンセルが要求された。実行中のジョブはワーカーが次のハートビートで中断する
+	CancelRequested bool       `json:"cancelRequested" gorm:"not null;default:false"`
+	Attempts        int        `json:"-" gorm:"not null;default:0"` // ワーカーが実行を始めた回数
+	HeartbeatAt     *time.Time `json:"-"`                           // 実行中のワーカーが最後に生存を通知した日時
+	StartedAt       *time.Time `json:"startedAt,omitempty"`
+	FinishedAt      *time.Time `json:"finishedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt" gorm:"index;not null"`
+	UpdatedAt       time.Time  `json:"updatedAt" gorm:"not null"`
+	User            Users      `json:"-" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// GenerationJobQuestionStatus - ジョブの質問ごとの進捗
+type GenerationJobQuestionStatus string
+
+const (
+	GenerationJobQuestionPending   GenerationJobQuestionStatus = "pending"
+	GenerationJobQuestionSucceeded GenerationJobQuestionStatus = "succeeded"
+	GenerationJobQuestionFailed    GenerationJobQuestionStatus = "failed"
+)
+
+// GenerationJobQuestion - ジョブの質問ごとの進捗
+type GenerationJobQuestion struct {
+	Question  string                      `json:"question"`
+	Status    GenerationJobQuestionStatus `json:"status"`
+	ErrorCode LLMErrorCode                `json:"errorCode,omitempty"`
+}
@@ -8,9 +8,105 @@ const (
 	GeminiFlashThinking LLMModel = "gemini-2.0-flash-thinking-exp"
 )
 
-type GeminiInput struct {
+// LLMInput - プロバイダーに依存しないLLMへの入力
+type LLMInput struct {
 	Model LLMModel `json:"model"`
 	Text  string   `json:"text"`
+	// ResponseSchema - 指定した場合は、このスキーマに沿ったJSONで応答させる
+	ResponseSchema *LLMSchema `json:"responseSchema,omitempty"`
+	// History - Textより前の会話(古い順)。指定した場合は会話の続きとしてTextに応答させる
+	History []LLMMessage `json:"history,omitempty"`
+	// SystemInstruction - 役割や出力の方針などの指示。Textとは別にシステムプロンプトとして渡す
+	SystemInstruction string              `json:"systemInstruction,omitempty"`
+	Params            LLMGenerationParams `json:"params"`
+}
+
+// LLMGenerationParams - サンプリングなどの生成パラメータ。指定しない値はモデルの既定値を使う
+type LLMGenerationParams struct {
+	Temperature     *float32           `json:"temperature,omitempty"`
+	TopP            *float32           `json:"topP,omitempty"`
+	MaxOutputTokens *int32             `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string           `json:"stopSequences,omitempty"`  // いずれかを出力した時点で生成を止める
+	SafetySettings  []LLMSafetySetting `json:"safetySettings,omitempty"` // 対応していないプロバイダーでは無視する
+}
+
+// LLMSafetyCategory - 安全性フィルタの対象
+type LLMSafetyCategory string
+
+const (
+	LLMSafetyCategoryHarassment       LLMSafetyCategory = "harassment"
+	LLMSafetyCategoryHateSpeech       LLMSafetyCategory = "hate_speech"
+	LLMSafetyCategorySexuallyExplicit LLMSafetyCategory = "sexually_explicit"
+	LLMSafetyCategoryDangerousContent LLMSafetyCategory = "dangerous_content"
+)
+
+// LLMSafetyThreshold - 安全性フィルタでブロックする基準
+type LLMSafetyThreshold string
+
+const (
+	LLMSafetyThresholdBlockNone           LLMSafetyThreshold = "block_none"
+	LLMSafetyThresholdBlockOnlyHigh       LLMSafetyThreshold = "block_only_high"
+	LLMSafetyThresholdBlockMediumAndAbove LLMSafetyThreshold = "block_medium_and_above"
+	LLMSafetyThresholdBlockLowAndAbove    LLMSafetyThreshold = "block_low_and_above"
+)
+
+// LLMSafetySetting - 安全性フィルタの設定
+type LLMSafetySetting struct {
+	Category  LLMSafetyCategory  `json:"category"`
+	Threshold LLMSafetyThreshold `json:"threshold"`
+}
+
+// LLMMessageRole - 会話の発言者
+type LLMMessageRole string
+
+const (
+	LLMMessageRoleUser  LLMMessageRole = "user"
+	LLMMessageRoleModel LLMMessageRole = "model"
+)
+
+// LLMMessage - 会話の1発言
+type LLMMessage struct {
+	Role LLMMessageRole `json:"role"`
+	Text string         `json:"text"`
+}
+
+// LLMSchemaType - LLMSchemaの型
+type LLMSchemaType string
+
+const (
+	LLMSchemaTypeObject  LLMSchemaType = "object"
+	LLMSchemaTypeArray   LLMSchemaType = "array"
+	LLMSchemaTypeString  LLMSchemaType = "string"
+	LLMSchemaTypeInteger LLMSchemaType = "integer"
+	LLMSchemaTypeNumber  LLMSchemaType = "number"
+	LLMSchemaTypeBoolean LLMSchemaType = "boolean"
+)
+
+// LLMSchema - JSONで応答させる場合の出力スキーマ。各プロバイダーが対応しているJSON Schemaのサブセット
+type LLMSchema struct {
+	Type        LLMSchemaType         `json:"type"`
+	Description string                `json:"description,omitempty"`
+	Properties  map[string]*LLMSchema `json:"properties,omitempty"`
+	Items       *LLMSchema            `json:"items,omitempty"`
+	Required    []string              `json:"required,omitempty"`
+	Enum        []string              `json:"enum,omitempty"`
+}
+
+// LLMResponse - プロバイダーに依存しないLLMの応答
+type LLMResponse struct {
+	Text         string `json:"text"`
+	InputTokens  int32  `json:"input_tokens"`
+	OutputTokens int32  `json:"output_tokens"`
+}
+
+type GeminiInput struct {
+	Model          LLMModel     `json:"model"`
+	Text           string       `json:"text"`
+	ResponseSchema *LLMSchema   `json:"responseSchema,omitempty"`
+	History        []LLMMessage `json:"history,omitempty"` // 指定した場合はチャットセッションとして送信する
+	// SystemInstruction - 指定した場合はシステムプロンプトとして送信する
+	SystemInstruction string              `json:"systemInstruction,omitempty"`
+	Params            LLMGenerationParams `json:"params"`
 }
 
 type GeminiResponse struct {
@@ -18,3 +114,42 @@ type GeminiResponse struct {
 	InputTokens  int32  `json:"input_tokens"`
 	OutputTokens int32  `json:"output_tokens"`
 }
+
+// OpenAIChatMessage - OpenAI互換APIのチャットメッセージ
+type OpenAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// OpenAIChatRequest - OpenAI互換APIの /chat/completions へのリクエスト
+type OpenAIChatRequest struct {
+	Model          string                `json:"model"`
+	Messages       []OpenAIChatMessage   `json:"messages"`
+	ResponseFormat *OpenAIResponseFormat `json:"response_format,omitempty"`
+	Temperature    *float32              `json:"temperature,omitempty"`
+	TopP           *float32              `json:"top_p,omitempty"`
+	MaxTokens      *int32                `json:"max_tokens,omitempty"`
+	Stop           []string              `json:"stop,omitempty"`
+}
+
+// OpenAIResponseFormat - Structured Outputsで応答の形式を指定する
+type OpenAIResponseFormat struct {
+	Type       string            `json:"type"` // "json_schema"
+	JSONSchema *OpenAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type OpenAIJSONSchema struct {
+	Name   string     `json:"name"`
+	Schema *LLMSchema `json:"schema"`
+}
+
+// OpenAIChatResponse - OpenAI互換APIの /chat/completions のレスポンス
+type OpenAIChatResponse struct {
+	Choices []struct {
+		Message OpenAIChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int32 `json:"prompt_tokens"`
+		CompletionTokens int32 `json:"completion_tokens"`
+	} `json:"usage"`
+}
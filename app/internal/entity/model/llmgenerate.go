@@ -1,8 +1,54 @@
 package model
 
+// LLMAnswerStatus - 質問ごとの回答生成結果
+type LLMAnswerStatus string
+
+const (
+	LLMAnswerStatusSucceeded LLMAnswerStatus = "succeeded"
+	LLMAnswerStatusFailed    LLMAnswerStatus = "failed"
+)
+
+// LLMErrorCode - 回答生成に失敗した理由
+type LLMErrorCode string
+
+const (
+	LLMErrorCodeGenerationFailed LLMErrorCode = "generation_failed" // LLMの呼び出しに失敗
+	LLMErrorCodeTimeout          LLMErrorCode = "timeout"           // 制限時間内に生成が終わらなかった
+	LLMErrorCodeCanceled         LLMErrorCode = "canceled"          // リクエストがキャンセルされた
+	LLMErrorCodeInternal         LLMErrorCode = "internal_error"    // サーバー内部のエラー
+)
+
 type LLMGeneratedResponse struct {
-	Question string `json:"question"`
-	Answer   string `json:"answer"`
+	Question  string          `json:"question"`
+	Answer    string          `json:"answer"`
+	Status    LLMAnswerStatus `json:"status"`
+	ErrorCode LLMErrorCode    `json:"errorCode,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	CharLimit int             `json:"charLimit"` // 質問文から読み取った文字数制限。制限がない場合は0
+	CharCount int             `json:"charCount"` // 回答の文字数(改行を除く)
+	// 回答を入力する欄。クライアントはname/id属性で入力欄を特定する
+	FieldType QuestionFieldType `json:"fieldType,omitempty"`
+	FieldName string            `json:"fieldName,omitempty"`
+	FieldID   string            `json:"fieldId,omitempty"`
+	// Candidates - 複数の回答候補を生成した場合のみ、採点の高い順に含める。Answerは先頭の候補
+	Candidates []LLMAnswerCandidate `json:"candidates,omitempty"`
+}
+
+// LLMAnswerCandidate - 1つの質問に対して生成した回答候補
+type LLMAnswerCandidate struct {
+	Answer    string          `json:"answer"`
+	CharCount int             `json:"charCount"`
+	Angle     string          `json:"angle"`           // 回答の切り口
+	Score     *LLMAnswerScore `json:"score,omitempty"` // 採点に失敗した場合はnil
+}
+
+// LLMAnswerScore - LLMによる回答候補の採点。各項目は1〜5点
+type LLMAnswerScore struct {
+	Specificity int    `json:"specificity"` // エピソードや数値の具体性
+	CompanyFit  int    `json:"companyFit"`  // 企業の理念・求める人材像との合致
+	CharLimit   int    `json:"charLimit"`   // 文字数制限の遵守
+	Total       int    `json:"total"`       // 各項目の合計
+	Comment     string `json:"comment,omitempty"`
 }
 
 type LLMGenerateRequest struct {
@@ -10,5 +56,166 @@ type LLMGenerateRequest struct {
 	CompanyName string   `json:"companyName"`
 	CompanyID   string   `json:"companyId"`
 	HTML        string   `json:"html"`
-	Model       string   `json:"model"`
+	// PageURL - ESのページのURL。抽出した設問と合わせて保存する
+	PageURL string `json:"pageUrl"`
+	Model   string `json:"model"`
+	// ProfileID - 回答生成に使う経験プロフィール。未指定の場合はデフォルトのプロフィールを使う
+	ProfileID string `json:"profileId"`
+	// Candidates - 1つの質問に対して生成する回答候補の数。0または1の場合は候補を生成しない
+	Candidates int `json:"candidates"`
+	// Params - 回答生成の生成パラメータ。指定した値だけ設定の既定値を上書きする
+	Params *LLMRequestParams `json:"params,omitempty"`
+}
+
+// LLMRequestParams - リクエストで指定できる生成パラメータ。安全性フィルタなどは設定でのみ変更できる
+type LLMRequestParams struct {
+	Temperature     *float32 `json:"temperature,omitempty"`
+	TopP            *float32 `json:"topP,omitempty"`
+	MaxOutputTokens *int32   `json:"maxOutputTokens,omitempty"`
+}
+
+// LLMRefineRequest - 生成済みの回答をユーザーの指示で修正するリクエスト
+type LLMRefineRequest struct {
+	Question    string `json:"question"`
+	Answer      string `json:"answer"`      // 最初に生成された回答
+	Instruction string `json:"instruction"` // 今回の修正指示
+	// History - これまでの修正指示と修正後の回答(古い順)。Answerから続けて修正する場合に指定する
+	History     []LLMRefineTurn `json:"history"`
+	CompanyName string          `json:"companyName"`
+	CompanyID   string          `json:"companyId"`
+	Model       string          `json:"model"`
+	ProfileID   string          `json:"profileId"`
+	// CharLimit - 文字数制限。0の場合は質問文から読み取る
+	CharLimit int `json:"charLimit"`
+}
+
+// LLMRefineTurn - 1回分の修正指示と修正後の回答
+type LLMRefineTurn struct {
+	Instruction string `json:"instruction"`
+	Answer      string `json:"answer"`
+}
+
+// LLMRefineResponse - 修正後の回答
+type LLMRefineResponse struct {
+	Question  string `json:"question"`
+	Answer    string `json:"answer"`
+	CharLimit int    `json:"charLimit,omitempty"`
+	CharCount int    `json:"charCount"`
+}
+
+// LLMReviewRequest - ユーザーが書いた回答の添削リクエスト
+type LLMReviewRequest struct {
+	Question    string `json:"question"`
+	Draft       string `json:"draft"` // ユーザーが書いた回答
+	CompanyName string `json:"companyName"`
+	CompanyID   string `json:"companyId"`
+	Model       string `json:"model"`
+	ProfileID   string `json:"profileId"`
+	// CharLimit - 文字数制限。0の場合は質問文から読み取る
+	CharLimit int `json:"charLimit"`
+}
+
+// LLMReviewResponse - 添削結果
+type LLMReviewResponse struct {
+	Question      string            `json:"question"`
+	Scores        []LLMReviewScore  `json:"scores"`        // 評価軸ごとの点数。評価軸の定義順
+	Total         int               `json:"total"`         // 各評価軸の点数の合計
+	Summary       string            `json:"summary"`       // 総評
+	Suggestions   []string          `json:"suggestions"`   // 具体的な改善案
+	FillerPhrases []LLMFillerPhrase `json:"fillerPhrases"` // 削れる冗長な表現
+	CharLimit     LLMCharLimitCheck `json:"charLimit"`
+}
+
+// LLMReviewAxis - 添削の評価軸
+type LLMReviewAxis string
+
+const (
+	LLMReviewAxisSpecificity LLMReviewAxis = "specificity" // エピソードや数値の具体性
+	LLMReviewAxisCompanyFit  LLMReviewAxis = "company_fit" // 企業の理念・求める人材像との合致
+	LLMReviewAxisStructure   LLMReviewAxis = "structure"   // 結論から述べているか、論理の一貫性
+	LLMReviewAxisExperience  LLMReviewAxis = "experience"  // 応募者の経験との整合性・強みの活かし方
+	LLMReviewAxisExpression  LLMReviewAxis = "expression"  // 簡潔で読みやすい表現か
+)
+
+// LLMReviewScore - 評価軸ごとの点数(1〜5点)と講評
+type LLMReviewScore struct {
+	Axis    LLMReviewAxis `json:"axis"`
+	Label   string        `json:"label"`
+	Score   int           `json:"score"`
+	Comment string        `json:"comment"`
+}
+
+// LLMFillerPhrase - 回答に含まれる冗長な表現と出現回数
+type LLMFillerPhrase struct {
+	Phrase string `json:"phrase"`
+	Count  int    `json:"count"`
+}
+
+// LLMCharLimitStatus - 文字数制限の判定結果
+type LLMCharLimitStatus string
+
+const (
+	LLMCharLimitStatusNone     LLMCharLimitStatus = "none"      // 文字数制限がない
+	LLMCharLimitStatusOK       LLMCharLimitStatus = "ok"        // 制限の80%以上、制限以内
+	LLMCharLimitStatusTooShort LLMCharLimitStatus = "too_short" // 制限の80%未満
+	LLMCharLimitStatusTooLong  LLMCharLimitStatus = "too_long"  // 制限を超えている
+)
+
+// LLMCharLimitCheck - 文字数制限の判定。文字数は改行を除いて数える
+type LLMCharLimitCheck struct {
+	Limit  int                `json:"limit"` // 制限がない場合は0
+	Min    int                `json:"min"`   // 少なすぎると判断する下限。制限がない場合は0
+	Count  int                `json:"count"`
+	Status LLMCharLimitStatus `json:"status"`
+}
+
+// LLMGenerateEventType - ストリーミング生成で送出するイベントの種類
+type LLMGenerateEventType string
+
+const (
+	LLMGenerateEventQuestions LLMGenerateEventType = "questions" // 質問抽出完了
+	LLMGenerateEventAnswer    LLMGenerateEventType = "answer"    // 1問分の回答生成完了
+	LLMGenerateEventError     LLMGenerateEventType = "error"     // 1問分の回答生成失敗、または処理全体の失敗
+	LLMGenerateEventDone      LLMGenerateEventType = "done"      // 全ての回答生成が終了
+)
+
+// LLMGenerateEvent - ストリーミング生成の途中経過
+type LLMGenerateEvent struct {
+	Type LLMGenerateEventType `json:"-"`
+	Data interface{}          `json:"data"`
+}
+
+// LLMQuestionsEventData - 抽出された質問の一覧
+type LLMQuestionsEventData struct {
+	Questions []string            `json:"questions"`
+	Fields    []ExtractedQuestion `json:"fields"` // Questionsと同じ順番の、設問ごとの入力欄の情報
+}
+
+// LLMAnswerEventData - 質問ごとの回答。Indexは抽出時の質問の順番
+type LLMAnswerEventData struct {
+	Index     int               `json:"index"`
+	Question  string            `json:"question"`
+	Answer    string            `json:"answer"`
+	CharLimit int               `json:"charLimit"`
+	CharCount int               `json:"charCount"`
+	FieldType QuestionFieldType `json:"fieldType,omitempty"`
+	FieldName string            `json:"fieldName,omitempty"`
+	FieldID   string            `json:"fieldId,omitempty"`
+	// Candidates - 複数の回答候補を生成した場合のみ含める
+	Candidates []LLMAnswerCandidate `json:"candidates,omitempty"`
+}
+
+// LLMErrorEventData - 回答生成のエラー。Indexが-1の場合は処理全体のエラー
+type LLMErrorEventData struct {
+	Index     int          `json:"index"`
+	Question  string       `json:"question,omitempty"`
+	ErrorCode LLMErrorCode `json:"errorCode,omitempty"`
+	Error     string       `json:"error"`
+}
+
+// LLMDoneEventData - 生成結果のサマリー
+type LLMDoneEventData struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
 }
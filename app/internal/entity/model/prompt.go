@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// PromptTemplateName - バージョン管理するプロンプトテンプレートの種類
+type PromptTemplateName string
+
+const (
+	PromptTemplateESGeneration       PromptTemplateName = "es_generation"        // 回答生成。質問ごとの指示
+	PromptTemplateESGenerationSystem PromptTemplateName = "es_generation_system" // 回答生成と修正のシステムプロンプト
+	PromptTemplateExtractQuestions   PromptTemplateName = "extract_questions"    // HTMLからの質問抽出
+	PromptTemplateExtractFormFields  PromptTemplateName = "extract_form_fields"  // 入力欄の情報からの質問抽出
+	PromptTemplateRepairQuestions    PromptTemplateName = "repair_questions"     // 質問抽出で壊れたJSONの修復
+	PromptTemplateReviewAnswer       PromptTemplateName = "review_answer"        // ユーザーが書いた回答の添削
+	PromptTemplateJudgeAnswers       PromptTemplateName = "judge_answers"        // 回答候補の採点
+	PromptTemplateRewriteAnswer      PromptTemplateName = "rewrite_answer"       // 文字数制限に合わせた書き直し
+	PromptTemplateRefineAnswer       PromptTemplateName = "refine_answer"        // ユーザーの指示での回答の修正
+)
+
+// PromptTemplates - プロンプトテンプレートのバージョン。text/templateの書式で変数を埋め込む
+// 種類ごとに有効なバージョンは1つだけで、有効なバージョンがない場合はprompts/のファイルを使う
+type PromptTemplates struct {
+	ID      string             `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name    PromptTemplateName `json:"name" gorm:"uniqueIndex:idx_prompt_templates_name_version;uniqueIndex:idx_prompt_templates_active_name,where:active;not null"`
+	Version int                `json:"version" gorm:"uniqueIndex:idx_prompt_templates_name_version;not null"` // 種類ごとに1から連番
+	Content string             `json:"content" gorm:"type:text;not null"`
+	// Description - 変更内容のメモ
+	Description string     `json:"description"`
+	Active      bool       `json:"active" gorm:"not null;default:false"`
+	CreatedBy   string     `json:"createdBy" gorm:"not null"` // 作成した管理者のユーザーID
+	ActivatedAt *time.Time `json:"activatedAt,omitempty"`     // 最後に有効にした日時
+	CreatedAt   time.Time  `json:"createdAt" gorm:"not null"`
+	UpdatedAt   time.Time  `json:"updatedAt" gorm:"not null"`
+}
+
+// CreatePromptTemplateRequest - プロンプトテンプレートの新しいバージョンを作成するリクエスト
+type CreatePromptTemplateRequest struct {
+	Content     string `json:"content"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// QuestionFieldType - 設問に対応する入力欄の種類
+type QuestionFieldType string
+
+const (
+	QuestionFieldTypeTextarea QuestionFieldType = "textarea" // 複数行テキスト
+	QuestionFieldTypeText     QuestionFieldType = "text"     // 単一行テキスト
+)
+
+// ExtractedQuestion - ESのHTMLから抽出した設問
+type ExtractedQuestion struct {
+	Question  string            `json:"question"`
+	CharLimit int               `json:"charLimit"` // 文字数制限。制限がない場合は0
+	FieldType QuestionFieldType `json:"fieldType"`
+	Name      string            `json:"name"` // 入力欄のname属性
+	ID        string            `json:"id"`   // 入力欄のid属性
+}
+
+// QuestionCaches - ユーザーごとに、ESのHTMLから抽出した設問を保存する
+// 同じページで回答を生成し直すときに、質問抽出のLLM呼び出しを省略する
+type QuestionCaches struct {
+	UserID string `json:"-" gorm:"primaryKey"`
+	// HTMLHash - 正規化したHTMLのSHA-256。入力済みの回答や属性の違いでは変わらない
+	HTMLHash  string              `json:"htmlHash" gorm:"primaryKey"`
+	PageURL   string              `json:"pageUrl" gorm:"index"` // クライアントが送ったESのページのURL。不明な場合は空
+	Questions []ExtractedQuestion `json:"questions" gorm:"type:jsonb;serializer:json;not null"`
+	// PromptVersionID - 抽出に使ったプロンプトテンプレートのバージョン。ファイルのプロンプトを使った場合やLLMを使わなかった場合はnil
+	PromptVersionID *string   `json:"-" gorm:"type:uuid"`
+	CreatedAt       time.Time `json:"createdAt" gorm:"not null"`
+	UpdatedAt       time.Time `json:"updatedAt" gorm:"not null"`
+	User            Users     `json:"-" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// LLMCachedQuestionsRequest - 保存済みの設問を取得するリクエスト。HTMLを指定した場合はHTMLで、それ以外はURLで検索する
+type LLMCachedQuestionsRequest struct {
+	HTML    string `json:"html"`
+	PageURL string `json:"pageUrl"`
+}
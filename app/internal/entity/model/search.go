@@ -0,0 +1,14 @@
+package model
+
+// SearchResult - Web検索の結果。Tavily APIのレスポンスと同じ形式
+type SearchResult struct {
+	Results []SearchResultItem `json:"results"`
+	Answer  string             `json:"answer,omitempty"` // AIによる要約
+}
+
+// SearchResultItem - Web検索の結果1件
+type SearchResultItem struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Content string `json:"content"`
+}
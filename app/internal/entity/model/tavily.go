@@ -2,10 +2,11 @@ package model
 
 // 企業情報をまとめた構造体
 type CompanyInfo struct {
-	Name        string `json:"name"`
-	Philosophy  string `json:"philosophy"`   // 企業理念
-	CareerPath  string `json:"career_path"`  // キャリアパス
-	TalentNeeds string `json:"talent_needs"` // 求める人材像
+	Name     string                     `json:"name"`
+	Contents map[ResearchSection]string `json:"contents"` // 調査項目ごとの検索結果
+
+	Citations []CompanyResearchCitations `json:"citations,omitempty"` // 項目ごとの出典
+	Detail    *CompanyDetails            `json:"detail,omitempty"`    // gBizINFOの法人情報
 }
 
 // Tavily APIへのリクエストパラメータ
@@ -16,13 +17,3 @@ type TavilySearchParams struct {
 	ApiKey        string `json:"api_key"`
 	IncludeAnswer bool   `json:"include_answer"` // AIによる要約を含めるかのフラグ
 }
-
-// Tavily APIからの検索結果
-type TavilySearchResult struct {
-	Results []struct {
-		Title   string `json:"title"`
-		URL     string `json:"url"`
-		Content string `json:"content"`
-	} `json:"results"`
-	Answer string `json:"answer,omitempty"` // AIによる要約
-}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// LLMUsagePurpose - LLM呼び出しの用途
+type LLMUsagePurpose string
+
+const (
+	LLMUsagePurposeExtraction LLMUsagePurpose = "extraction" // HTMLからの質問抽出
+	LLMUsagePurposeGeneration LLMUsagePurpose = "generation" // 回答生成
+	LLMUsagePurposeRewrite    LLMUsagePurpose = "rewrite"    // 文字数制限に合わせた回答の書き直し
+	LLMUsagePurposeRefine     LLMUsagePurpose = "refine"     // ユーザーの指示による回答の修正
+	LLMUsagePurposeJudge      LLMUsagePurpose = "judge"      // 回答候補の採点
+	LLMUsagePurposeReview     LLMUsagePurpose = "review"     // ユーザーが書いた回答の添削
+)
+
+// LLMUsages - LLM呼び出しごとのトークン使用量の台帳
+type LLMUsages struct {
+	ID           uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       string          `json:"userId" gorm:"index:idx_llm_usages_user_created;not null"`
+	RequestID    string          `json:"requestId" gorm:"type:uuid;index;not null"` // 1回の生成リクエストの識別子。生成履歴のIDと同じ
+	Model        string          `json:"model" gorm:"not null"`
+	Purpose      LLMUsagePurpose `json:"purpose" gorm:"not null"`
+	InputTokens  int32           `json:"inputTokens" gorm:"not null"`
+	OutputTokens int32           `json:"outputTokens" gorm:"not null"`
+	CreatedAt    time.Time       `json:"createdAt" gorm:"index:idx_llm_usages_user_created;not null"`
+	User         Users           `json:"-" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// LLMUsageByModel - モデルごとの使用量の集計
+type LLMUsageByModel struct {
+	Model        string `json:"model"`
+	InputTokens  int64  `json:"inputTokens"`
+	OutputTokens int64  `json:"outputTokens"`
+	Calls        int64  `json:"calls"`
+}
+
+// UsageQuota - ユーザーごとの月間上限。0は無制限
+type UsageQuota struct {
+	MonthlyTokens   int64
+	MonthlyRequests int64
+}
+
+// UsageSummary - 当月の使用量と残りの上限
+type UsageSummary struct {
+	PeriodStart       time.Time         `json:"periodStart"`
+	PeriodEnd         time.Time         `json:"periodEnd"`
+	UsedTokens        int64             `json:"usedTokens"`
+	UsedRequests      int64             `json:"usedRequests"`
+	TokenQuota        int64             `json:"tokenQuota"`        // 0は無制限
+	RequestQuota      int64             `json:"requestQuota"`      // 0は無制限
+	RemainingTokens   *int64            `json:"remainingTokens"`   // 無制限の場合はnull
+	RemainingRequests *int64            `json:"remainingRequests"` // 無制限の場合はnull
+	ByModel           []LLMUsageByModel `json:"byModel"`
+}
+
+// Exceeded - いずれかの上限に達しているかどうか
+func (s *UsageSummary) Exceeded() bool {
+	return (s.RemainingTokens != nil && *s.RemainingTokens <= 0) ||
+		(s.RemainingRequests != nil && *s.RemainingRequests <= 0)
+}
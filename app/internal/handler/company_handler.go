@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -13,6 +14,7 @@ import (
 
 type CompanyHandler interface {
 	SearchCompanies(c echo.Context) error
+	GetCompany(c echo.Context) error
 }
 
 type companyHandler struct {
@@ -32,10 +34,24 @@ func (h *companyHandler) SearchCompanies(c echo.Context) error {
 			"error": "keyword is required",
 		})
 	}
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "limit must be an integer",
+		})
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "offset must be an integer",
+		})
+	}
 
 	ctx := c.Request().Context()
+	idp := c.Request().Header.Get("idp")
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
 	ctx = context.WithValue(ctx, contextKey.KeywordKey, keyword)
-	companies, err := h.companyUsecase.SearchCompanies(ctx, keyword)
+	companies, err := h.companyUsecase.SearchCompanies(ctx, keyword, limit, offset)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": fmt.Sprintf("failed to search companies: %v", err),
@@ -44,3 +60,31 @@ func (h *companyHandler) SearchCompanies(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, companies)
 }
+
+// GetCompany - 法人番号で法人情報を取得する
+func (h *companyHandler) GetCompany(c echo.Context) error {
+	ctx := c.Request().Context()
+	idp := c.Request().Header.Get("idp")
+	userID := c.Get("userID")
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, userID)
+
+	details, err := h.companyUsecase.GetCompany(ctx, c.Param("corporateNumber"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCorporateNumber):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrCompanyNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to get company: %v", err),
+		})
+	}
+
+	return c.JSON(http.StatusOK, details)
+}
@@ -13,17 +13,26 @@ import (
 
 	"es-api/app/internal/entity/model"
 	"es-api/app/internal/handler"
+	"es-api/app/internal/usecase"
 )
 
 type mockCompanyUsecase struct {
 	testifymock.Mock
 }
 
-func (m *mockCompanyUsecase) SearchCompanies(ctx context.Context, keyword string) ([]model.CompanyBasicInfo, error) {
-	args := m.Called(ctx, keyword)
+func (m *mockCompanyUsecase) SearchCompanies(ctx context.Context, keyword string, limit int, offset int) ([]model.CompanyBasicInfo, error) {
+	args := m.Called(ctx, keyword, limit, offset)
 	return args.Get(0).([]model.CompanyBasicInfo), args.Error(1)
 }
 
+func (m *mockCompanyUsecase) GetCompany(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error) {
+	args := m.Called(ctx, corporateNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CompanyDetails), args.Error(1)
+}
+
 func TestCompanyHandler_SearchCompanies(t *testing.T) {
 	mockUsecase := new(mockCompanyUsecase)
 	h := handler.NewCompanyHandler(mockUsecase)
@@ -37,7 +46,7 @@ func TestCompanyHandler_SearchCompanies(t *testing.T) {
 		}
 		keyword := "株式会社テスト"
 
-		mockUsecase.On("SearchCompanies", testifymock.Anything, keyword).Return(expectedResponse, nil)
+		mockUsecase.On("SearchCompanies", testifymock.Anything, keyword, 0, 0).Return(expectedResponse, nil)
 
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodGet, "/api/companies/search", nil)
@@ -62,12 +71,44 @@ func TestCompanyHandler_SearchCompanies(t *testing.T) {
 		expectedResponse := []model.CompanyBasicInfo{}
 		keyword := "存在しない会社"
 
-		mockUsecase.On("SearchCompanies", testifymock.Anything, keyword).Return(expectedResponse, nil)
+		mockUsecase.On("SearchCompanies", testifymock.Anything, keyword, 0, 0).Return(expectedResponse, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/companies/search", nil)
+		q := req.URL.Query()
+		q.Add("keyword", keyword)
+		req.URL.RawQuery = q.Encode()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.SearchCompanies(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response []model.CompanyBasicInfo
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResponse, response)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("正常系:limitとoffsetを指定した場合", func(t *testing.T) {
+		expectedResponse := []model.CompanyBasicInfo{
+			{
+				CompanyID:   "1234567890124",
+				CompanyName: "テスト商事株式会社",
+			},
+		}
+		keyword := "テスト"
+
+		mockUsecase.On("SearchCompanies", testifymock.Anything, keyword, 10, 20).Return(expectedResponse, nil)
 
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodGet, "/api/companies/search", nil)
 		q := req.URL.Query()
 		q.Add("keyword", keyword)
+		q.Add("limit", "10")
+		q.Add("offset", "20")
 		req.URL.RawQuery = q.Encode()
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
@@ -83,6 +124,18 @@ func TestCompanyHandler_SearchCompanies(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 
+	t.Run("異常系:limitが整数でない", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/companies/search?keyword=test&limit=abc", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.SearchCompanies(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":"limit must be an integer"}`, rec.Body.String())
+	})
+
 	t.Run("異常系:検索キーワード未指定", func(t *testing.T) {
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodGet, "/api/companies/search", nil)
@@ -95,3 +148,61 @@ func TestCompanyHandler_SearchCompanies(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 }
+
+func TestCompanyHandler_GetCompany(t *testing.T) {
+	newRequest := func(corporateNumber string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/companies/"+corporateNumber, nil), rec)
+		c.SetParamNames("corporateNumber")
+		c.SetParamValues(corporateNumber)
+		c.Set("userID", "test-user")
+		return c, rec
+	}
+
+	t.Run("正常系:法人情報を返す", func(t *testing.T) {
+		mockUsecase := new(mockCompanyUsecase)
+		h := handler.NewCompanyHandler(mockUsecase)
+		expected := &model.CompanyDetails{
+			CorporateNumber: "1234567890123",
+			Name:            "株式会社テスト",
+			Address:         "東京都千代田区",
+			Capital:         100000000,
+			EmployeeCount:   500,
+			FoundedAt:       "2000-04-01",
+		}
+		mockUsecase.On("GetCompany", testifymock.Anything, "1234567890123").Return(expected, nil)
+
+		c, rec := newRequest("1234567890123")
+		err := h.GetCompany(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response model.CompanyDetails
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, *expected, response)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:法人番号の形式が不正な場合は400を返す", func(t *testing.T) {
+		mockUsecase := new(mockCompanyUsecase)
+		h := handler.NewCompanyHandler(mockUsecase)
+		mockUsecase.On("GetCompany", testifymock.Anything, "abc").Return(nil, usecase.ErrInvalidCorporateNumber)
+
+		c, rec := newRequest("abc")
+		err := h.GetCompany(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("異常系:法人が存在しない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(mockCompanyUsecase)
+		h := handler.NewCompanyHandler(mockUsecase)
+		mockUsecase.On("GetCompany", testifymock.Anything, "9999999999999").Return(nil, usecase.ErrCompanyNotFound)
+
+		c, rec := newRequest("9999999999999")
+		err := h.GetCompany(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
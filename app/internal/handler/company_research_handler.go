@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/usecase"
+)
+
+type CompanyResearchHandler interface {
+	GetResearch(c echo.Context) error
+	RefreshResearch(c echo.Context) error
+	DeleteResearch(c echo.Context) error
+}
+
+type companyResearchHandler struct {
+	cru usecase.CompanyResearchUsecase
+}
+
+func NewCompanyResearchHandler(cru usecase.CompanyResearchUsecase) CompanyResearchHandler {
+	return &companyResearchHandler{cru: cru}
+}
+
+type refreshResearchRequest struct {
+	CompanyName string `json:"companyName"`
+}
+
+func companyResearchContext(c echo.Context) context.Context {
+	ctx := c.Request().Context()
+	idp := c.Request().Header.Get("idp")
+	userID := c.Get("userID")
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+	return context.WithValue(ctx, contextKey.UserIDKey, userID)
+}
+
+// GetResearch - キャッシュされている企業情報を出典と合わせて返す
+func (h *companyResearchHandler) GetResearch(c echo.Context) error {
+	research, err := h.cru.GetResearch(companyResearchContext(c), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrCompanyResearchNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, research)
+}
+
+// RefreshResearch - 企業情報を検索し直してキャッシュを更新する(管理者用)
+func (h *companyResearchHandler) RefreshResearch(c echo.Context) error {
+	var req refreshResearchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	research, err := h.cru.RefreshResearch(companyResearchContext(c), c.Param("id"), req.CompanyName)
+	if err != nil {
+		if errors.Is(err, usecase.ErrCompanyNameRequired) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, research)
+}
+
+// DeleteResearch - 企業情報のキャッシュを削除する(管理者用)
+func (h *companyResearchHandler) DeleteResearch(c echo.Context) error {
+	if err := h.cru.DeleteResearch(companyResearchContext(c), c.Param("id")); err != nil {
+		if errors.Is(err, usecase.ErrCompanyResearchNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,123 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/handler"
+	"es-api/app/internal/usecase"
+	appmock "es-api/app/test/mock/usecase"
+)
+
+func TestCompanyResearchHandler_GetResearch(t *testing.T) {
+	t.Run("正常系:企業情報を出典と合わせて返す", func(t *testing.T) {
+		mockUsecase := new(appmock.CompanyResearchUsecaseMock)
+		h := handler.NewCompanyResearchHandler(mockUsecase)
+		mockUsecase.On("GetResearch", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+			CompanyID:   "1234567890123",
+			CompanyName: "株式会社テスト",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy: "テスト企業理念",
+			},
+			Citations: []model.CompanyResearchCitations{
+				{ID: 1, Section: model.ResearchSectionPhilosophy, Title: "企業情報", URL: "https://example.com/about", Content: "抜粋"},
+			},
+		}, nil)
+
+		c, rec := newEntryRequest(http.MethodGet, "/api/companies/1234567890123/research", nil, "1234567890123")
+		err := h.GetResearch(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var research model.CompanyResearch
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &research))
+		if assert.Len(t, research.Citations, 1) {
+			assert.Equal(t, model.ResearchSectionPhilosophy, research.Citations[0].Section)
+			assert.Equal(t, "https://example.com/about", research.Citations[0].URL)
+		}
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:キャッシュが存在しない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.CompanyResearchUsecaseMock)
+		h := handler.NewCompanyResearchHandler(mockUsecase)
+		mockUsecase.On("GetResearch", testifymock.Anything, "unknown").Return(nil, usecase.ErrCompanyResearchNotFound)
+
+		c, rec := newEntryRequest(http.MethodGet, "/api/companies/unknown/research", nil, "unknown")
+		err := h.GetResearch(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestCompanyResearchHandler_RefreshResearch(t *testing.T) {
+	t.Run("正常系:検索し直した企業情報を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.CompanyResearchUsecaseMock)
+		h := handler.NewCompanyResearchHandler(mockUsecase)
+		mockUsecase.On("RefreshResearch", testifymock.Anything, "1234567890123", "株式会社テスト").Return(&model.CompanyResearch{
+			CompanyID:   "1234567890123",
+			CompanyName: "株式会社テスト",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy: "テスト企業理念",
+			},
+		}, nil)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/admin/companies/1234567890123/research/refresh", map[string]string{"companyName": "株式会社テスト"}, "1234567890123")
+		err := h.RefreshResearch(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var research model.CompanyResearch
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &research))
+		assert.Equal(t, "テスト企業理念", research.Contents[model.ResearchSectionPhilosophy])
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:企業名が分からない場合は400を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.CompanyResearchUsecaseMock)
+		h := handler.NewCompanyResearchHandler(mockUsecase)
+		mockUsecase.On("RefreshResearch", testifymock.Anything, "1234567890123", "").Return(nil, usecase.ErrCompanyNameRequired)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/admin/companies/1234567890123/research/refresh", map[string]string{}, "1234567890123")
+		err := h.RefreshResearch(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestCompanyResearchHandler_DeleteResearch(t *testing.T) {
+	t.Run("正常系:キャッシュを削除できる", func(t *testing.T) {
+		mockUsecase := new(appmock.CompanyResearchUsecaseMock)
+		h := handler.NewCompanyResearchHandler(mockUsecase)
+		mockUsecase.On("DeleteResearch", testifymock.Anything, "1234567890123").Return(nil)
+
+		c, rec := newEntryRequest(http.MethodDelete, "/api/admin/companies/1234567890123/research", nil, "1234567890123")
+		err := h.DeleteResearch(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:キャッシュが存在しない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.CompanyResearchUsecaseMock)
+		h := handler.NewCompanyResearchHandler(mockUsecase)
+		mockUsecase.On("DeleteResearch", testifymock.Anything, "unknown").Return(usecase.ErrCompanyResearchNotFound)
+
+		c, rec := newEntryRequest(http.MethodDelete, "/api/admin/companies/unknown/research", nil, "unknown")
+		err := h.DeleteResearch(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+)
+
+type ExperienceEntryHandler interface {
+	ListEpisodes(c echo.Context) error
+	CreateEpisode(c echo.Context) error
+	UpdateEpisode(c echo.Context) error
+	DeleteEpisode(c echo.Context) error
+	ListSkills(c echo.Context) error
+	CreateSkill(c echo.Context) error
+	UpdateSkill(c echo.Context) error
+	DeleteSkill(c echo.Context) error
+	ListAchievements(c echo.Context) error
+	CreateAchievement(c echo.Context) error
+	UpdateAchievement(c echo.Context) error
+	DeleteAchievement(c echo.Context) error
+}
+
+type experienceEntryHandler struct {
+	eu usecase.ExperienceEntryUsecase
+}
+
+func NewExperienceEntryHandler(eu usecase.ExperienceEntryUsecase) ExperienceEntryHandler {
+	return &experienceEntryHandler{eu: eu}
+}
+
+func (h *experienceEntryHandler) ListEpisodes(c echo.Context) error {
+	episodes, err := h.eu.ListEpisodes(experienceContext(c), c.Param("id"))
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"episodes": episodes,
+	})
+}
+
+func (h *experienceEntryHandler) CreateEpisode(c echo.Context) error {
+	var input model.InputEpisode
+	if err := c.Bind(&input); err != nil {
+		return badRequest(c, err)
+	}
+	episode, err := h.eu.CreateEpisode(experienceContext(c), c.Param("id"), input)
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, episode)
+}
+
+func (h *experienceEntryHandler) UpdateEpisode(c echo.Context) error {
+	var input model.InputEpisode
+	if err := c.Bind(&input); err != nil {
+		return badRequest(c, err)
+	}
+	episode, err := h.eu.UpdateEpisode(experienceContext(c), c.Param("id"), input)
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, episode)
+}
+
+func (h *experienceEntryHandler) DeleteEpisode(c echo.Context) error {
+	if err := h.eu.DeleteEpisode(experienceContext(c), c.Param("id")); err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *experienceEntryHandler) ListSkills(c echo.Context) error {
+	skills, err := h.eu.ListSkills(experienceContext(c), c.Param("id"))
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"skills": skills,
+	})
+}
+
+func (h *experienceEntryHandler) CreateSkill(c echo.Context) error {
+	var input model.InputSkill
+	if err := c.Bind(&input); err != nil {
+		return badRequest(c, err)
+	}
+	skill, err := h.eu.CreateSkill(experienceContext(c), c.Param("id"), input)
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, skill)
+}
+
+func (h *experienceEntryHandler) UpdateSkill(c echo.Context) error {
+	var input model.InputSkill
+	if err := c.Bind(&input); err != nil {
+		return badRequest(c, err)
+	}
+	skill, err := h.eu.UpdateSkill(experienceContext(c), c.Param("id"), input)
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, skill)
+}
+
+func (h *experienceEntryHandler) DeleteSkill(c echo.Context) error {
+	if err := h.eu.DeleteSkill(experienceContext(c), c.Param("id")); err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *experienceEntryHandler) ListAchievements(c echo.Context) error {
+	achievements, err := h.eu.ListAchievements(experienceContext(c), c.Param("id"))
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"achievements": achievements,
+	})
+}
+
+func (h *experienceEntryHandler) CreateAchievement(c echo.Context) error {
+	var input model.InputAchievement
+	if err := c.Bind(&input); err != nil {
+		return badRequest(c, err)
+	}
+	achievement, err := h.eu.CreateAchievement(experienceContext(c), c.Param("id"), input)
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, achievement)
+}
+
+func (h *experienceEntryHandler) UpdateAchievement(c echo.Context) error {
+	var input model.InputAchievement
+	if err := c.Bind(&input); err != nil {
+		return badRequest(c, err)
+	}
+	achievement, err := h.eu.UpdateAchievement(experienceContext(c), c.Param("id"), input)
+	if err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, achievement)
+}
+
+func (h *experienceEntryHandler) DeleteAchievement(c echo.Context) error {
+	if err := h.eu.DeleteAchievement(experienceContext(c), c.Param("id")); err != nil {
+		return entryErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func badRequest(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": err.Error(),
+	})
+}
+
+func entryErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidExperienceEntry):
+		return badRequest(c, err)
+	case errors.Is(err, usecase.ErrExperienceEntryNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "experience entry not found",
+		})
+	}
+	return profileErrorResponse(c, err)
+}
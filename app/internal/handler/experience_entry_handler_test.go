@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/handler"
+	"es-api/app/internal/usecase"
+	appmock "es-api/app/test/mock/usecase"
+)
+
+func newEntryRequest(method, path string, body interface{}, id string) (echo.Context, *httptest.ResponseRecorder) {
+	reqBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("idp", "test-idp")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	c.Set("userID", "test-user-id")
+	return c, rec
+}
+
+func TestExperienceEntryHandler_CreateEpisode(t *testing.T) {
+	input := model.InputEpisode{Title: "学園祭実行委員", Result: "来場者数が前年比120%"}
+
+	t.Run("正常系:エピソードを作成できる", func(t *testing.T) {
+		mockUsecase := new(appmock.ExperienceEntryUsecaseMock)
+		h := handler.NewExperienceEntryHandler(mockUsecase)
+		mockUsecase.On("CreateEpisode", testifymock.Anything, "profile-id", input).Return(&model.ExperienceEpisodes{ID: "episode-id", Title: input.Title}, nil)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/experience/profiles/profile-id/episodes", input, "profile-id")
+		err := h.CreateEpisode(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		var episode model.ExperienceEpisodes
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &episode))
+		assert.Equal(t, "episode-id", episode.ID)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:入力値が不正な場合は400を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.ExperienceEntryUsecaseMock)
+		h := handler.NewExperienceEntryHandler(mockUsecase)
+		mockUsecase.On("CreateEpisode", testifymock.Anything, "profile-id", model.InputEpisode{}).Return(nil, fmt.Errorf("%w: title is required", usecase.ErrInvalidExperienceEntry))
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/experience/profiles/profile-id/episodes", model.InputEpisode{}, "profile-id")
+		err := h.CreateEpisode(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:プロフィールが存在しない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.ExperienceEntryUsecaseMock)
+		h := handler.NewExperienceEntryHandler(mockUsecase)
+		mockUsecase.On("CreateEpisode", testifymock.Anything, "unknown", input).Return(nil, usecase.ErrExperienceProfileNotFound)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/experience/profiles/unknown/episodes", input, "unknown")
+		err := h.CreateEpisode(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestExperienceEntryHandler_DeleteSkill(t *testing.T) {
+	t.Run("正常系:スキルを削除できる", func(t *testing.T) {
+		mockUsecase := new(appmock.ExperienceEntryUsecaseMock)
+		h := handler.NewExperienceEntryHandler(mockUsecase)
+		mockUsecase.On("DeleteSkill", testifymock.Anything, "skill-id").Return(nil)
+
+		c, rec := newEntryRequest(http.MethodDelete, "/api/experience/skills/skill-id", nil, "skill-id")
+		err := h.DeleteSkill(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:スキルが存在しない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.ExperienceEntryUsecaseMock)
+		h := handler.NewExperienceEntryHandler(mockUsecase)
+		mockUsecase.On("DeleteSkill", testifymock.Anything, "skill-id").Return(usecase.ErrExperienceEntryNotFound)
+
+		c, rec := newEntryRequest(http.MethodDelete, "/api/experience/skills/skill-id", nil, "skill-id")
+		err := h.DeleteSkill(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
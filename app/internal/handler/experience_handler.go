@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -14,6 +15,11 @@ import (
 type ExperienceHandler interface {
 	GetExperienceByUserID(c echo.Context) error
 	PostExperience(c echo.Context) error
+	ListProfiles(c echo.Context) error
+	CreateProfile(c echo.Context) error
+	UpdateProfile(c echo.Context) error
+	DeleteProfile(c echo.Context) error
+	SetDefaultProfile(c echo.Context) error
 }
 
 type experienceHandler struct {
@@ -54,3 +60,80 @@ func (h *experienceHandler) PostExperience(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, experience)
 }
+
+func (h *experienceHandler) ListProfiles(c echo.Context) error {
+	profiles, err := h.eu.ListProfiles(experienceContext(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"profiles": profiles,
+	})
+}
+
+func (h *experienceHandler) CreateProfile(c echo.Context) error {
+	var inputExperience model.InputExperience
+	if err := c.Bind(&inputExperience); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	profile, err := h.eu.CreateProfile(experienceContext(c), inputExperience)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusCreated, profile)
+}
+
+func (h *experienceHandler) UpdateProfile(c echo.Context) error {
+	var inputExperience model.InputExperience
+	if err := c.Bind(&inputExperience); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	profile, err := h.eu.UpdateProfile(experienceContext(c), c.Param("id"), inputExperience)
+	if err != nil {
+		return profileErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+func (h *experienceHandler) DeleteProfile(c echo.Context) error {
+	if err := h.eu.DeleteProfile(experienceContext(c), c.Param("id")); err != nil {
+		return profileErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *experienceHandler) SetDefaultProfile(c echo.Context) error {
+	profile, err := h.eu.SetDefaultProfile(experienceContext(c), c.Param("id"))
+	if err != nil {
+		return profileErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+func experienceContext(c echo.Context) context.Context {
+	ctx := c.Request().Context()
+	idp := c.Request().Header.Get("idp")
+	userID := c.Get("userID")
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, userID)
+	return ctx
+}
+
+func profileErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, usecase.ErrExperienceProfileNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "experience profile not found",
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -14,6 +14,7 @@ import (
 
 	"es-api/app/internal/entity/model"
 	"es-api/app/internal/handler"
+	"es-api/app/internal/usecase"
 	appmock "es-api/app/test/mock/usecase"
 )
 
@@ -99,3 +100,77 @@ func TestExperienceHandler_PostExperience(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 }
+
+func TestExperienceHandler_ListProfiles(t *testing.T) {
+	mockUsecase := new(appmock.ExperienceUsecaseMock)
+	h := handler.NewExperienceHandler(mockUsecase)
+
+	profiles := []model.Experiences{
+		{ID: "test-id-1", Name: "デフォルト", IsDefault: true},
+		{ID: "test-id-2", Name: "IT業界向け"},
+	}
+
+	t.Run("正常系:プロフィール一覧を取得できる", func(t *testing.T) {
+		mockUsecase.On("ListProfiles", testifymock.Anything).Return(profiles, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/experience/profiles", nil)
+		req.Header.Set("idp", "test-idp")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("userID", "test-user-id")
+
+		err := h.ListProfiles(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Profiles []model.Experiences `json:"profiles"`
+		}
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Profiles, 2)
+		assert.True(t, response.Profiles[0].IsDefault)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestExperienceHandler_SetDefaultProfile(t *testing.T) {
+	t.Run("正常系:デフォルトのプロフィールを変更できる", func(t *testing.T) {
+		mockUsecase := new(appmock.ExperienceUsecaseMock)
+		h := handler.NewExperienceHandler(mockUsecase)
+		mockUsecase.On("SetDefaultProfile", testifymock.Anything, "test-id-2").Return(&model.Experiences{ID: "test-id-2", IsDefault: true}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/experience/profiles/test-id-2/default", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("test-id-2")
+		c.Set("userID", "test-user-id")
+
+		err := h.SetDefaultProfile(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:プロフィールが存在しない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.ExperienceUsecaseMock)
+		h := handler.NewExperienceHandler(mockUsecase)
+		mockUsecase.On("SetDefaultProfile", testifymock.Anything, "unknown").Return(nil, usecase.ErrExperienceProfileNotFound)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/experience/profiles/unknown/default", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("unknown")
+		c.Set("userID", "test-user-id")
+
+		err := h.SetDefaultProfile(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/usecase"
+)
+
+type GenerationHandler interface {
+	ListGenerations(c echo.Context) error
+	GetGeneration(c echo.Context) error
+}
+
+type generationHandler struct {
+	gu usecase.GenerationUsecase
+}
+
+func NewGenerationHandler(gu usecase.GenerationUsecase) GenerationHandler {
+	return &generationHandler{gu: gu}
+}
+
+func (h *generationHandler) ListGenerations(c echo.Context) error {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "limit must be an integer",
+		})
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "offset must be an integer",
+		})
+	}
+
+	ctx := c.Request().Context()
+	idp := c.Request().Header.Get("idp")
+	userID := c.Get("userID")
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, userID)
+	generations, err := h.gu.ListGenerations(ctx, limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"generations": generations,
+	})
+}
+
+func (h *generationHandler) GetGeneration(c echo.Context) error {
+	ctx := c.Request().Context()
+	idp := c.Request().Header.Get("idp")
+	userID := c.Get("userID")
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, userID)
+	generation, err := h.gu.GetGeneration(ctx, c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if generation == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "generation not found",
+		})
+	}
+	return c.JSON(http.StatusOK, generation)
+}
+
+// queryInt はクエリパラメータを整数として取得する。未指定の場合は0を返す
+func queryInt(c echo.Context, name string) (int, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
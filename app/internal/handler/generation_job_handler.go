@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"es-api/app/internal/usecase"
+)
+
+type GenerationJobHandler interface {
+	CreateJob(c echo.Context) error
+	GetJob(c echo.Context) error
+	CancelJob(c echo.Context) error
+}
+
+type generationJobHandler struct {
+	gju usecase.GenerationJobUsecase
+}
+
+func NewGenerationJobHandler(gju usecase.GenerationJobUsecase) GenerationJobHandler {
+	return &generationJobHandler{gju: gju}
+}
+
+// CreateJob は生成ジョブを登録し、結果を待たずにジョブを返す
+func (h *generationJobHandler) CreateJob(c echo.Context) error {
+	req, err := bindGenerateRequest(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if err := validateCandidates(req); err != nil {
+		return badRequest(c, err)
+	}
+	if err := usecase.ValidateRequestParams(req.Params); err != nil {
+		return badRequest(c, err)
+	}
+
+	job, err := h.gju.CreateJob(generateContext(c), *req)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusAccepted, job)
+}
+
+// GetJob は生成ジョブの状態、質問ごとの進捗、生成結果を返す
+func (h *generationJobHandler) GetJob(c echo.Context) error {
+	job, err := h.gju.GetJob(generateContext(c), c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if job == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "generation job not found",
+		})
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+// CancelJob は生成ジョブのキャンセルを要求する
+func (h *generationJobHandler) CancelJob(c echo.Context) error {
+	job, err := h.gju.CancelJob(generateContext(c), c.Param("id"))
+	if errors.Is(err, usecase.ErrGenerationJobFinished) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if job == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "generation job not found",
+		})
+	}
+	return c.JSON(http.StatusOK, job)
+}
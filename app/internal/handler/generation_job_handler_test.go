@@ -0,0 +1,116 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/handler"
+	"es-api/app/internal/usecase"
+	appmock "es-api/app/test/mock/usecase"
+)
+
+func TestGenerationJobHandler_CreateJob(t *testing.T) {
+	body := model.LLMGenerateRequest{
+		CompanyName: "株式会社テスト",
+		CompanyID:   "1234567890123",
+		HTML:        "<textarea></textarea>",
+	}
+
+	t.Run("正常系:ジョブを登録して202を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.GenerationJobUsecaseMock)
+		h := handler.NewGenerationJobHandler(mockUsecase)
+		mockUsecase.On("CreateJob", testifymock.Anything, body).Return(&model.GenerationJobs{ID: "job-id", Status: model.GenerationJobStatusQueued}, nil)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/generate/jobs", body, "")
+		err := h.CreateJob(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		var job model.GenerationJobs
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		assert.Equal(t, "job-id", job.ID)
+		assert.Equal(t, model.GenerationJobStatusQueued, job.Status)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:必須パラメータが不足している場合は400を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.GenerationJobUsecaseMock)
+		h := handler.NewGenerationJobHandler(mockUsecase)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/generate/jobs", model.LLMGenerateRequest{CompanyName: "株式会社テスト"}, "")
+		err := h.CreateJob(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUsecase.AssertNotCalled(t, "CreateJob", testifymock.Anything, testifymock.Anything)
+	})
+}
+
+func TestGenerationJobHandler_GetJob(t *testing.T) {
+	t.Run("正常系:ジョブの進捗を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.GenerationJobUsecaseMock)
+		h := handler.NewGenerationJobHandler(mockUsecase)
+		mockUsecase.On("GetJob", testifymock.Anything, "job-id").Return(&model.GenerationJobs{
+			ID:     "job-id",
+			Status: model.GenerationJobStatusRunning,
+			Progress: []model.GenerationJobQuestion{
+				{Question: "志望動機", Status: model.GenerationJobQuestionSucceeded},
+				{Question: "自己PR", Status: model.GenerationJobQuestionPending},
+			},
+		}, nil)
+
+		c, rec := newEntryRequest(http.MethodGet, "/api/generate/jobs/job-id", nil, "job-id")
+		err := h.GetJob(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var job model.GenerationJobs
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		assert.Len(t, job.Progress, 2)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:ジョブが存在しない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.GenerationJobUsecaseMock)
+		h := handler.NewGenerationJobHandler(mockUsecase)
+		mockUsecase.On("GetJob", testifymock.Anything, "unknown").Return(nil, nil)
+
+		c, rec := newEntryRequest(http.MethodGet, "/api/generate/jobs/unknown", nil, "unknown")
+		err := h.GetJob(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestGenerationJobHandler_CancelJob(t *testing.T) {
+	t.Run("正常系:キャンセルを要求したジョブを返す", func(t *testing.T) {
+		mockUsecase := new(appmock.GenerationJobUsecaseMock)
+		h := handler.NewGenerationJobHandler(mockUsecase)
+		mockUsecase.On("CancelJob", testifymock.Anything, "job-id").Return(&model.GenerationJobs{ID: "job-id", Status: model.GenerationJobStatusCanceled, CancelRequested: true}, nil)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/generate/jobs/job-id/cancel", nil, "job-id")
+		err := h.CancelJob(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:終了済みのジョブの場合は409を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.GenerationJobUsecaseMock)
+		h := handler.NewGenerationJobHandler(mockUsecase)
+		mockUsecase.On("CancelJob", testifymock.Anything, "job-id").Return(nil, usecase.ErrGenerationJobFinished)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/generate/jobs/job-id/cancel", nil, "job-id")
+		err := h.CancelJob(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
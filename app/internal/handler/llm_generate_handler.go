@@ -2,6 +2,9 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 
@@ -15,6 +18,10 @@ import (
 
 type LLMGenerateHandler interface {
 	Generate(c echo.Context) error
+	GenerateStream(c echo.Context) error
+	Refine(c echo.Context) error
+	Review(c echo.Context) error
+	CachedQuestions(c echo.Context) error
 }
 
 type llmGenerateHandler struct {
@@ -28,30 +35,29 @@ func NewLLMGenerateHandler(llmu usecase.LLMGenerateUsecase) LLMGenerateHandler {
 }
 
 func (h *llmGenerateHandler) Generate(c echo.Context) error {
-	// リクエストをバインド
-	req := new(model.LLMGenerateRequest)
-	if err := c.Bind(req); err != nil {
-		log.Printf("リクエストバインドエラー: %v", err)
+	req, err := bindGenerateRequest(c)
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "リクエストの解析に失敗しました",
+			"error": err.Error(),
 		})
 	}
 
-	if req.CompanyName == "" || req.CompanyID == "" || req.HTML == "" {
-		log.Printf("必須パラメータ不足: companyName=%v, companyID=%v, html=%v", req.CompanyName != "", req.CompanyID != "", req.HTML != "")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "必要なパラメータが不足しています",
-		})
+	if err := validateCandidates(req); err != nil {
+		return badRequest(c, err)
+	}
+	if err := usecase.ValidateRequestParams(req.Params); err != nil {
+		return badRequest(c, err)
 	}
 
-	ctx := c.Request().Context()
-	idp := c.Request().Header.Get("idp")
-	userID := c.Get("userID")
-	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
-	ctx = context.WithValue(ctx, contextKey.UserIDKey, userID)
+	ctx := generateContext(c)
 
 	result, err := h.llmenerateUsecase.LLMGenerate(ctx, *req)
 
+	if errors.Is(err, usecase.ErrExperienceProfileNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -62,3 +68,180 @@ func (h *llmGenerateHandler) Generate(c echo.Context) error {
 		"answers": result,
 	})
 }
+
+// GenerateStream は生成の途中経過をServer-Sent Eventsで返す
+func (h *llmGenerateHandler) GenerateStream(c echo.Context) error {
+	req, err := bindGenerateRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := validateCandidates(req); err != nil {
+		return badRequest(c, err)
+	}
+	if err := usecase.ValidateRequestParams(req.Params); err != nil {
+		return badRequest(c, err)
+	}
+
+	ctx := generateContext(c)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	err = h.llmenerateUsecase.LLMGenerateStream(ctx, *req, func(event model.LLMGenerateEvent) {
+		if err := writeSSEEvent(res, event); err != nil {
+			log.Printf("SSEイベントの送信に失敗しました: %v", err)
+		}
+	})
+	if err != nil {
+		event := model.LLMGenerateEvent{
+			Type: model.LLMGenerateEventError,
+			Data: model.LLMErrorEventData{
+				Index: -1,
+				Error: err.Error(),
+			},
+		}
+		if err := writeSSEEvent(res, event); err != nil {
+			log.Printf("SSEイベントの送信に失敗しました: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Refine は生成済みの回答をユーザーの修正指示に従って書き直す
+func (h *llmGenerateHandler) Refine(c echo.Context) error {
+	req := new(model.LLMRefineRequest)
+	if err := c.Bind(req); err != nil {
+		log.Printf("リクエストバインドエラー: %v", err)
+		return badRequest(c, errors.New("リクエストの解析に失敗しました"))
+	}
+	if req.Question == "" || req.Answer == "" || req.Instruction == "" || req.CompanyName == "" || req.CompanyID == "" {
+		return badRequest(c, errors.New("必要なパラメータが不足しています"))
+	}
+
+	ctx := generateContext(c)
+
+	result, err := h.llmenerateUsecase.RefineAnswer(ctx, *req)
+
+	if errors.Is(err, usecase.ErrExperienceProfileNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Review はユーザーが書いた回答を添削する
+func (h *llmGenerateHandler) Review(c echo.Context) error {
+	req := new(model.LLMReviewRequest)
+	if err := c.Bind(req); err != nil {
+		log.Printf("リクエストバインドエラー: %v", err)
+		return badRequest(c, errors.New("リクエストの解析に失敗しました"))
+	}
+	if req.Question == "" || req.Draft == "" || req.CompanyName == "" || req.CompanyID == "" {
+		return badRequest(c, errors.New("必要なパラメータが不足しています"))
+	}
+
+	ctx := generateContext(c)
+
+	result, err := h.llmenerateUsecase.ReviewAnswer(ctx, *req)
+
+	if errors.Is(err, usecase.ErrExperienceProfileNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// CachedQuestions は同じページから抽出して保存した設問を返す。生成を始める前に設問を表示するために使う
+func (h *llmGenerateHandler) CachedQuestions(c echo.Context) error {
+	req := new(model.LLMCachedQuestionsRequest)
+	if err := c.Bind(req); err != nil {
+		log.Printf("リクエストバインドエラー: %v", err)
+		return badRequest(c, errors.New("リクエストの解析に失敗しました"))
+	}
+	if req.HTML == "" && req.PageURL == "" {
+		return badRequest(c, errors.New("htmlまたはpageUrlを指定してください"))
+	}
+
+	ctx := generateContext(c)
+
+	result, err := h.llmenerateUsecase.CachedQuestions(ctx, *req)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if result == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "cached questions not found",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// bindGenerateRequest はリクエストをバインドして必須パラメータを検証する
+func bindGenerateRequest(c echo.Context) (*model.LLMGenerateRequest, error) {
+	req := new(model.LLMGenerateRequest)
+	if err := c.Bind(req); err != nil {
+		log.Printf("リクエストバインドエラー: %v", err)
+		return nil, errors.New("リクエストの解析に失敗しました")
+	}
+
+	if req.CompanyName == "" || req.CompanyID == "" || req.HTML == "" {
+		log.Printf("必須パラメータ不足: companyName=%v, companyID=%v, html=%v", req.CompanyName != "", req.CompanyID != "", req.HTML != "")
+		return nil, errors.New("必要なパラメータが不足しています")
+	}
+
+	return req, nil
+}
+
+// validateCandidates は回答候補の数が範囲内かを検証する
+func validateCandidates(req *model.LLMGenerateRequest) error {
+	if req.Candidates < 0 || req.Candidates > usecase.MaxAnswerCandidates {
+		return fmt.Errorf("candidatesは0から%dの範囲で指定してください", usecase.MaxAnswerCandidates)
+	}
+	return nil
+}
+
+func generateContext(c echo.Context) context.Context {
+	ctx := c.Request().Context()
+	idp := c.Request().Header.Get("idp")
+	userID := c.Get("userID")
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, userID)
+	return ctx
+}
+
+func writeSSEEvent(res *echo.Response, event model.LLMGenerateEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
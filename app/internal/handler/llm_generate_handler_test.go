@@ -0,0 +1,426 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/handler"
+	"es-api/app/internal/usecase"
+	appmock "es-api/app/test/mock/usecase"
+)
+
+func newGenerateRequest(t *testing.T, path string, body model.LLMGenerateRequest) *http.Request {
+	jsonBody, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("idp", "test")
+	return req
+}
+
+func TestLLMGenerateHandler_Generate(t *testing.T) {
+	body := model.LLMGenerateRequest{
+		CompanyName: "株式会社テスト",
+		CompanyID:   "1234567890123",
+		HTML:        "<textarea></textarea>",
+	}
+
+	t.Run("正常系:一部の質問が失敗しても200で返す", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		answers := []model.LLMGeneratedResponse{
+			{Question: "志望動機", Answer: "回答1", Status: model.LLMAnswerStatusSucceeded},
+			{Question: "自己PR", Status: model.LLMAnswerStatusFailed, ErrorCode: model.LLMErrorCodeTimeout, Error: "timeout"},
+		}
+		mockUsecase.On("LLMGenerate", testifymock.Anything, body).Return(answers, nil)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newGenerateRequest(t, "/api/generate", body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Generate(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Answers []model.LLMGeneratedResponse `json:"answers"`
+		}
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, answers, response.Answers)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:回答候補の数が上限を超えている場合", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+		req := body
+		req.Candidates = usecase.MaxAnswerCandidates + 1
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newGenerateRequest(t, "/api/generate", req), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Generate(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUsecase.AssertNotCalled(t, "LLMGenerate", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:生成パラメータが範囲外の場合", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+		temperature := float32(usecase.MaxTemperature + 1)
+		req := body
+		req.Params = &model.LLMRequestParams{Temperature: &temperature}
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newGenerateRequest(t, "/api/generate", req), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Generate(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "temperature")
+		mockUsecase.AssertNotCalled(t, "LLMGenerate", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:全ての質問が失敗した場合", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		mockUsecase.On("LLMGenerate", testifymock.Anything, body).Return(nil, errors.New("回答を生成できませんでした"))
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newGenerateRequest(t, "/api/generate", body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Generate(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestLLMGenerateHandler_GenerateStream(t *testing.T) {
+	body := model.LLMGenerateRequest{
+		CompanyName: "株式会社テスト",
+		CompanyID:   "1234567890123",
+		HTML:        "<textarea></textarea>",
+	}
+
+	t.Run("正常系:イベントがSSE形式で送信される", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		events := []model.LLMGenerateEvent{
+			{
+				Type: model.LLMGenerateEventQuestions,
+				Data: model.LLMQuestionsEventData{
+					Questions: []string{"志望動機", "自己PR"},
+					Fields: []model.ExtractedQuestion{
+						{Question: "志望動機", FieldType: model.QuestionFieldTypeTextarea, Name: "motivation"},
+						{Question: "自己PR", CharLimit: 200, FieldType: model.QuestionFieldTypeTextarea, ID: "pr"},
+					},
+				},
+			},
+			{
+				Type: model.LLMGenerateEventAnswer,
+				Data: model.LLMAnswerEventData{Index: 1, Question: "自己PR", Answer: "回答2", CharLimit: 200, CharCount: 3, FieldType: model.QuestionFieldTypeTextarea, FieldID: "pr"},
+			},
+			{
+				Type: model.LLMGenerateEventAnswer,
+				Data: model.LLMAnswerEventData{Index: 0, Question: "志望動機", Answer: "回答1", CharCount: 3},
+			},
+			{
+				Type: model.LLMGenerateEventDone,
+				Data: model.LLMDoneEventData{Total: 2, Succeeded: 2},
+			},
+		}
+		mockUsecase.On("LLMGenerateStream", testifymock.Anything, body, testifymock.Anything).Return(events, nil)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newGenerateRequest(t, "/api/generate/stream", body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.GenerateStream(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+
+		expected := "event: questions\ndata: {\"questions\":[\"志望動機\",\"自己PR\"],\"fields\":[" +
+			"{\"question\":\"志望動機\",\"charLimit\":0,\"fieldType\":\"textarea\",\"name\":\"motivation\",\"id\":\"\"}," +
+			"{\"question\":\"自己PR\",\"charLimit\":200,\"fieldType\":\"textarea\",\"name\":\"\",\"id\":\"pr\"}]}\n\n" +
+			"event: answer\ndata: {\"index\":1,\"question\":\"自己PR\",\"answer\":\"回答2\",\"charLimit\":200,\"charCount\":3,\"fieldType\":\"textarea\",\"fieldId\":\"pr\"}\n\n" +
+			"event: answer\ndata: {\"index\":0,\"question\":\"志望動機\",\"answer\":\"回答1\",\"charLimit\":0,\"charCount\":3}\n\n" +
+			"event: done\ndata: {\"total\":2,\"succeeded\":2,\"failed\":0}\n\n"
+		assert.Equal(t, expected, rec.Body.String())
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:処理全体が失敗した場合はerrorイベントを送信する", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		mockUsecase.On("LLMGenerateStream", testifymock.Anything, body, testifymock.Anything).Return(nil, errors.New("質問が見つかりませんでした"))
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newGenerateRequest(t, "/api/generate/stream", body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.GenerateStream(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "event: error\ndata: {\"index\":-1,\"error\":\"質問が見つかりませんでした\"}\n\n", rec.Body.String())
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:必須パラメータが不足している場合はストリームを開始しない", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newGenerateRequest(t, "/api/generate/stream", model.LLMGenerateRequest{}), rec)
+
+		err := h.GenerateStream(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		mockUsecase.AssertNotCalled(t, "LLMGenerateStream", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	})
+}
+
+func newRefineRequest(t *testing.T, body model.LLMRefineRequest) *http.Request {
+	jsonBody, err := json.Marshal(body)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/generate/refine", bytes.NewBuffer(jsonBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("idp", "test")
+	return req
+}
+
+func TestLLMGenerateHandler_Refine(t *testing.T) {
+	body := model.LLMRefineRequest{
+		Question:    "志望動機を教えてください",
+		Answer:      "元の回答",
+		Instruction: "もっと具体的にしてください",
+		History:     []model.LLMRefineTurn{},
+		CompanyName: "株式会社テスト",
+		CompanyID:   "1234567890123",
+	}
+
+	t.Run("正常系:修正後の回答を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		result := &model.LLMRefineResponse{Question: body.Question, Answer: "修正後の回答", CharCount: 6}
+		mockUsecase.On("RefineAnswer", testifymock.Anything, body).Return(result, nil)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newRefineRequest(t, body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Refine(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response model.LLMRefineResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, *result, response)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:修正指示がない場合", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+		req := body
+		req.Instruction = ""
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newRefineRequest(t, req), rec)
+
+		err := h.Refine(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUsecase.AssertNotCalled(t, "RefineAnswer", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:指定したプロフィールが存在しない場合", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		mockUsecase.On("RefineAnswer", testifymock.Anything, body).Return(nil, usecase.ErrExperienceProfileNotFound)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newRefineRequest(t, body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Refine(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("異常系:修正に失敗した場合", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		mockUsecase.On("RefineAnswer", testifymock.Anything, body).Return(nil, errors.New("回答の修正に失敗しました"))
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newRefineRequest(t, body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Refine(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestLLMGenerateHandler_Review(t *testing.T) {
+	body := model.LLMReviewRequest{
+		Question:    "志望動機を教えてください",
+		Draft:       "御社の事業に惹かれました。",
+		CompanyName: "株式会社テスト",
+		CompanyID:   "1234567890123",
+	}
+	newReviewRequest := func(t *testing.T, body model.LLMReviewRequest) *http.Request {
+		jsonBody, err := json.Marshal(body)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/review", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("idp", "test")
+		return req
+	}
+
+	t.Run("正常系:添削結果を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		result := &model.LLMReviewResponse{
+			Question:  body.Question,
+			Scores:    []model.LLMReviewScore{{Axis: model.LLMReviewAxisSpecificity, Label: "具体性", Score: 2, Comment: "具体性がない"}},
+			Total:     2,
+			CharLimit: model.LLMCharLimitCheck{Count: 13, Status: model.LLMCharLimitStatusNone},
+		}
+		mockUsecase.On("ReviewAnswer", testifymock.Anything, body).Return(result, nil)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newReviewRequest(t, body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Review(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response model.LLMReviewResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, *result, response)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:回答がない場合", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+		req := body
+		req.Draft = ""
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newReviewRequest(t, req), rec)
+
+		err := h.Review(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUsecase.AssertNotCalled(t, "ReviewAnswer", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:添削に失敗した場合", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		mockUsecase.On("ReviewAnswer", testifymock.Anything, body).Return(nil, errors.New("回答の添削に失敗しました"))
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(newReviewRequest(t, body), rec)
+		c.Set("userID", "test-user")
+
+		err := h.Review(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestLLMGenerateHandler_CachedQuestions(t *testing.T) {
+	t.Run("正常系:保存済みの設問を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+		body := model.LLMCachedQuestionsRequest{PageURL: "https://example.com/entry"}
+		mockUsecase.On("CachedQuestions", testifymock.Anything, body).Return(&model.QuestionCaches{
+			HTMLHash:  "hash",
+			PageURL:   body.PageURL,
+			Questions: []model.ExtractedQuestion{{Question: "志望動機", FieldType: model.QuestionFieldTypeTextarea}},
+		}, nil)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/generate/questions", body, "")
+		err := h.CachedQuestions(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response model.QuestionCaches
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response.Questions, 1)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:保存されていない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+		body := model.LLMCachedQuestionsRequest{HTML: "<textarea></textarea>"}
+		mockUsecase.On("CachedQuestions", testifymock.Anything, body).Return(nil, nil)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/generate/questions", body, "")
+		err := h.CachedQuestions(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("異常系:HTMLとURLのどちらもない場合は400を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.LLMGenerateUsecaseMock)
+		h := handler.NewLLMGenerateHandler(mockUsecase)
+
+		c, rec := newEntryRequest(http.MethodPost, "/api/generate/questions", model.LLMCachedQuestionsRequest{}, "")
+		err := h.CachedQuestions(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUsecase.AssertNotCalled(t, "CachedQuestions", testifymock.Anything, testifymock.Anything)
+	})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+)
+
+type PromptTemplateHandler interface {
+	ListVersions(c echo.Context) error
+	CreateVersion(c echo.Context) error
+	ActivateVersion(c echo.Context) error
+	Rollback(c echo.Context) error
+}
+
+type promptTemplateHandler struct {
+	ptu usecase.PromptTemplateUsecase
+}
+
+func NewPromptTemplateHandler(ptu usecase.PromptTemplateUsecase) PromptTemplateHandler {
+	return &promptTemplateHandler{ptu: ptu}
+}
+
+func promptTemplateName(c echo.Context) model.PromptTemplateName {
+	return model.PromptTemplateName(c.Param("name"))
+}
+
+// ListVersions - プロンプトテンプレートの全てのバージョンを新しい順に返す(管理者用)
+func (h *promptTemplateHandler) ListVersions(c echo.Context) error {
+	templates, err := h.ptu.ListVersions(generateContext(c), promptTemplateName(c))
+	if err != nil {
+		return promptTemplateErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"versions": templates,
+	})
+}
+
+// CreateVersion - プロンプトテンプレートの新しいバージョンを作成する。有効にするまで生成には使われない(管理者用)
+func (h *promptTemplateHandler) CreateVersion(c echo.Context) error {
+	var req model.CreatePromptTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	template, err := h.ptu.CreateVersion(generateContext(c), promptTemplateName(c), req)
+	if err != nil {
+		return promptTemplateErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, template)
+}
+
+// ActivateVersion - 指定したバージョンを有効にする(管理者用)
+func (h *promptTemplateHandler) ActivateVersion(c echo.Context) error {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid version",
+		})
+	}
+
+	template, err := h.ptu.ActivateVersion(generateContext(c), promptTemplateName(c), version)
+	if err != nil {
+		return promptTemplateErrorResponse(c, err)
+	}
+	if template == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "prompt template version not found",
+		})
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// Rollback - 有効なバージョンの直前に有効だったバージョンを有効にする(管理者用)
+func (h *promptTemplateHandler) Rollback(c echo.Context) error {
+	template, err := h.ptu.Rollback(generateContext(c), promptTemplateName(c))
+	if err != nil {
+		return promptTemplateErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+func promptTemplateErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrPromptTemplateNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrInvalidPromptTemplate):
+		return badRequest(c, err)
+	case errors.Is(err, usecase.ErrNoPreviousPromptVersion):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": err.Error(),
+	})
+}
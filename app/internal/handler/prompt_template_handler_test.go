@@ -0,0 +1,123 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/handler"
+	"es-api/app/internal/usecase"
+	appmock "es-api/app/test/mock/usecase"
+)
+
+// newPromptRequest はプロンプトテンプレートの種類とバージョンをパスパラメータに設定したリクエストを作成する
+func newPromptRequest(method, path string, body interface{}, name, version string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := newEntryRequest(method, path, body, "")
+	c.SetParamNames("name", "version")
+	c.SetParamValues(name, version)
+	return c, rec
+}
+
+func TestPromptTemplateHandler_CreateVersion(t *testing.T) {
+	req := model.CreatePromptTemplateRequest{Content: "「{{.Question}}」に回答してください"}
+
+	t.Run("正常系:新しいバージョンを作成して201を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.PromptTemplateUsecaseMock)
+		h := handler.NewPromptTemplateHandler(mockUsecase)
+		mockUsecase.On("CreateVersion", testifymock.Anything, model.PromptTemplateESGeneration, req).Return(&model.PromptTemplates{
+			ID: "template-id", Name: model.PromptTemplateESGeneration, Version: 2, Content: req.Content,
+		}, nil)
+
+		c, rec := newPromptRequest(http.MethodPost, "/api/admin/prompts/es_generation", req, "es_generation", "")
+		err := h.CreateVersion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:テンプレートが不正な場合は400を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.PromptTemplateUsecaseMock)
+		h := handler.NewPromptTemplateHandler(mockUsecase)
+		mockUsecase.On("CreateVersion", testifymock.Anything, model.PromptTemplateESGeneration, req).Return(nil, fmt.Errorf("%w: unknown variable", usecase.ErrInvalidPromptTemplate))
+
+		c, rec := newPromptRequest(http.MethodPost, "/api/admin/prompts/es_generation", req, "es_generation", "")
+		err := h.CreateVersion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("異常系:バージョン管理していない種類の場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.PromptTemplateUsecaseMock)
+		h := handler.NewPromptTemplateHandler(mockUsecase)
+		mockUsecase.On("CreateVersion", testifymock.Anything, model.PromptTemplateName("unknown"), req).Return(nil, usecase.ErrPromptTemplateNotFound)
+
+		c, rec := newPromptRequest(http.MethodPost, "/api/admin/prompts/unknown", req, "unknown", "")
+		err := h.CreateVersion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestPromptTemplateHandler_ActivateVersion(t *testing.T) {
+	t.Run("正常系:指定したバージョンを有効にする", func(t *testing.T) {
+		mockUsecase := new(appmock.PromptTemplateUsecaseMock)
+		h := handler.NewPromptTemplateHandler(mockUsecase)
+		mockUsecase.On("ActivateVersion", testifymock.Anything, model.PromptTemplateESGeneration, 2).Return(&model.PromptTemplates{
+			Name: model.PromptTemplateESGeneration, Version: 2, Active: true,
+		}, nil)
+
+		c, rec := newPromptRequest(http.MethodPost, "/api/admin/prompts/es_generation/versions/2/activate", nil, "es_generation", "2")
+		err := h.ActivateVersion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("異常系:バージョンが数値でない場合は400を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.PromptTemplateUsecaseMock)
+		h := handler.NewPromptTemplateHandler(mockUsecase)
+
+		c, rec := newPromptRequest(http.MethodPost, "/api/admin/prompts/es_generation/versions/latest/activate", nil, "es_generation", "latest")
+		err := h.ActivateVersion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockUsecase.AssertNotCalled(t, "ActivateVersion", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:バージョンが存在しない場合は404を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.PromptTemplateUsecaseMock)
+		h := handler.NewPromptTemplateHandler(mockUsecase)
+		mockUsecase.On("ActivateVersion", testifymock.Anything, model.PromptTemplateESGeneration, 9).Return(nil, nil)
+
+		c, rec := newPromptRequest(http.MethodPost, "/api/admin/prompts/es_generation/versions/9/activate", nil, "es_generation", "9")
+		err := h.ActivateVersion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestPromptTemplateHandler_Rollback(t *testing.T) {
+	t.Run("異常系:1つ前のバージョンがない場合は409を返す", func(t *testing.T) {
+		mockUsecase := new(appmock.PromptTemplateUsecaseMock)
+		h := handler.NewPromptTemplateHandler(mockUsecase)
+		mockUsecase.On("Rollback", testifymock.Anything, model.PromptTemplateESGeneration).Return(nil, usecase.ErrNoPreviousPromptVersion)
+
+		c, rec := newPromptRequest(http.MethodPost, "/api/admin/prompts/es_generation/rollback", nil, "es_generation", "")
+		err := h.Rollback(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
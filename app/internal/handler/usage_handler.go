@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/usecase"
+)
+
+type UsageHandler interface {
+	GetUsage(c echo.Context) error
+}
+
+type usageHandler struct {
+	uu usecase.UsageUsecase
+}
+
+func NewUsageHandler(uu usecase.UsageUsecase) UsageHandler {
+	return &usageHandler{uu: uu}
+}
+
+func (h *usageHandler) GetUsage(c echo.Context) error {
+	ctx := c.Request().Context()
+	idp := c.Request().Header.Get("idp")
+	userID := c.Get("userID")
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, userID)
+	summary, err := h.uu.GetUsage(ctx)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, summary)
+}
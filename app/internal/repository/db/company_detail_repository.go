@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"es-api/app/infrastructure/db"
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CompanyDetailRepository interface {
+	FindByCorporateNumber(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error)
+	Upsert(ctx context.Context, details *model.CompanyDetails) error
+}
+
+type companyDetailRepository struct {
+	dbManager db.DBConnectionManager
+	defaultDB *gorm.DB
+}
+
+func NewCompanyDetailRepository(defaultDB *gorm.DB) CompanyDetailRepository {
+	return &companyDetailRepository{
+		defaultDB: defaultDB,
+	}
+}
+
+func NewCompanyDetailRepositoryWithDBManager(dbManager db.DBConnectionManager) CompanyDetailRepository {
+	return &companyDetailRepository{
+		dbManager: dbManager,
+		defaultDB: dbManager.GetConnection("clerk"),
+	}
+}
+
+func (r *companyDetailRepository) conn(ctx context.Context) *gorm.DB {
+	idp := ctx.Value(contextKey.IDPKey).(string)
+	if r.dbManager != nil && idp != "" {
+		return r.dbManager.GetConnection(idp)
+	}
+	return r.defaultDB
+}
+
+// FindByCorporateNumber - 法人番号で法人情報を検索。存在しない場合はnilを返す
+func (r *companyDetailRepository) FindByCorporateNumber(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error) {
+	var details model.CompanyDetails
+	result := r.conn(ctx).Where("corporate_number = ?", corporateNumber).Find(&details)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &details, nil
+}
+
+// Upsert - 法人情報を保存。同じ法人番号の法人情報がある場合は内容を更新する
+func (r *companyDetailRepository) Upsert(ctx context.Context, details *model.CompanyDetails) error {
+	return r.conn(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "corporate_number"}},
+		UpdateAll: true,
+	}).Create(details).Error
+}
@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+	"es-api/app/test"
+)
+
+func TestCompanyDetailRepository_FindByCorporateNumber(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewCompanyDetailRepository(db)
+
+	t.Run("異常系:法人情報が存在しない場合", func(t *testing.T) {
+		ctx := test.SetupContextContext("test-user-id")
+		details, err := repo.FindByCorporateNumber(ctx, "non-existent-id")
+
+		assert.NoError(t, err)
+		assert.Nil(t, details)
+	})
+}
+
+func TestCompanyDetailRepository_Upsert(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewCompanyDetailRepository(db)
+
+	t.Run("正常系:新規作成と更新", func(t *testing.T) {
+		ctx := test.SetupContextContext("test-user-id")
+		details := &model.CompanyDetails{
+			CorporateNumber: "1234567890123",
+			Name:            "テスト株式会社",
+			Address:         "東京都千代田区",
+			EmployeeCount:   100,
+		}
+		assert.NoError(t, repo.Upsert(ctx, details))
+
+		err := repo.Upsert(ctx, &model.CompanyDetails{
+			CorporateNumber: "1234567890123",
+			Name:            "テスト株式会社",
+			Address:         "東京都港区",
+			EmployeeCount:   120,
+		})
+		assert.NoError(t, err)
+
+		found, err := repo.FindByCorporateNumber(ctx, "1234567890123")
+		assert.NoError(t, err)
+		assert.Equal(t, "東京都港区", found.Address)
+		assert.Equal(t, 120, found.EmployeeCount)
+		assert.Equal(t, details.CreatedAt.Unix(), found.CreatedAt.Unix())
+	})
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"es-api/app/infrastructure/db"
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CompanyRepository interface {
+	Search(ctx context.Context, normalizedQuery string, limit int, offset int) ([]model.Companies, error)
+	UpsertMany(ctx context.Context, companies []model.Companies) error
+	FindSearchQuery(ctx context.Context, normalizedQuery string) (*model.CompanySearchQueries, error)
+	SaveSearchQuery(ctx context.Context, normalizedQuery string) error
+}
+
+type companyRepository struct {
+	dbManager db.DBConnectionManager
+	defaultDB *gorm.DB
+}
+
+func NewCompanyRepository(defaultDB *gorm.DB) CompanyRepository {
+	return &companyRepository{
+		defaultDB: defaultDB,
+	}
+}
+
+func NewCompanyRepositoryWithDBManager(dbManager db.DBConnectionManager) CompanyRepository {
+	return &companyRepository{
+		dbManager: dbManager,
+		defaultDB: dbManager.GetConnection("clerk"),
+	}
+}
+
+func (r *companyRepository) conn(ctx context.Context) *gorm.DB {
+	idp := ctx.Value(contextKey.IDPKey).(string)
+	if r.dbManager != nil && idp != "" {
+		return r.dbManager.GetConnection(idp)
+	}
+	return r.defaultDB
+}
+
+// Search - 正規化した法人名の部分一致で検索する
+// 完全一致・前方一致・部分一致の順に、一致した位置が前で名前が短いものを上位にする
+// 部分一致の絞り込みはpg_trgmのGINインデックスで高速化する
+func (r *companyRepository) Search(ctx context.Context, normalizedQuery string, limit int, offset int) ([]model.Companies, error) {
+	escaped := escapeLike(normalizedQuery)
+	var companies []model.Companies
+	err := r.conn(ctx).
+		Where("normalized_name LIKE ?", "%"+escaped+"%").
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL: `CASE WHEN normalized_name = ? THEN 0 WHEN normalized_name LIKE ? THEN 1 ELSE 2 END,
+				strpos(normalized_name, ?), char_length(normalized_name), corporate_number`,
+			Vars:               []interface{}{normalizedQuery, escaped + "%", normalizedQuery},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Offset(offset).
+		Find(&companies).Error
+	if err != nil {
+		return nil, err
+	}
+	return companies, nil
+}
+
+// UpsertMany - 法人をまとめて保存。同じ法人番号の法人がある場合は名前を更新する
+func (r *companyRepository) UpsertMany(ctx context.Context, companies []model.Companies) error {
+	if len(companies) == 0 {
+		return nil
+	}
+	return r.conn(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "corporate_number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "normalized_name", "updated_at"}),
+	}).Create(&companies).Error
+}
+
+// FindSearchQuery - gBizINFOで検索済みのキーワードを検索。存在しない場合はnilを返す
+func (r *companyRepository) FindSearchQuery(ctx context.Context, normalizedQuery string) (*model.CompanySearchQueries, error) {
+	var query model.CompanySearchQueries
+	result := r.conn(ctx).Where("query = ?", normalizedQuery).Find(&query)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &query, nil
+}
+
+// SaveSearchQuery - gBizINFOで検索したキーワードと検索日時を保存する
+func (r *companyRepository) SaveSearchQuery(ctx context.Context, normalizedQuery string) error {
+	return r.conn(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "query"}},
+		DoUpdates: clause.AssignmentColumns([]string{"searched_at"}),
+	}).Create(&model.CompanySearchQueries{Query: normalizedQuery, SearchedAt: time.Now()}).Error
+}
+
+// escapeLike - LIKEのワイルドカードをエスケープする
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
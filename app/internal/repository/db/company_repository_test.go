@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+	"es-api/app/test"
+)
+
+func TestCompanyRepository_Search(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewCompanyRepository(db)
+	ctx := test.SetupContextContext("test-user-id")
+	err := repo.UpsertMany(ctx, []model.Companies{
+		{CorporateNumber: "1000000000001", Name: "テスト商事株式会社", NormalizedName: "てすと商事"},
+		{CorporateNumber: "1000000000002", Name: "株式会社テスト", NormalizedName: "てすと"},
+		{CorporateNumber: "1000000000003", Name: "株式会社ベストテスト", NormalizedName: "べすとてすと"},
+		{CorporateNumber: "1000000000004", Name: "株式会社サンプル", NormalizedName: "さんぷる"},
+	})
+	assert.NoError(t, err)
+
+	t.Run("正常系:完全一致・前方一致・部分一致の順に返す", func(t *testing.T) {
+		companies, err := repo.Search(ctx, "てすと", 10, 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, companies, 3)
+		assert.Equal(t, "1000000000002", companies[0].CorporateNumber)
+		assert.Equal(t, "1000000000001", companies[1].CorporateNumber)
+		assert.Equal(t, "1000000000003", companies[2].CorporateNumber)
+	})
+
+	t.Run("正常系:limitとoffsetでページングする", func(t *testing.T) {
+		companies, err := repo.Search(ctx, "てすと", 1, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, companies, 1)
+		assert.Equal(t, "1000000000001", companies[0].CorporateNumber)
+	})
+
+	t.Run("正常系:ワイルドカードはエスケープする", func(t *testing.T) {
+		companies, err := repo.Search(ctx, "%", 10, 0)
+
+		assert.NoError(t, err)
+		assert.Empty(t, companies)
+	})
+}
+
+func TestCompanyRepository_SearchQuery(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewCompanyRepository(db)
+	ctx := test.SetupContextContext("test-user-id")
+
+	t.Run("異常系:検索済みでない場合", func(t *testing.T) {
+		query, err := repo.FindSearchQuery(ctx, "てすと")
+
+		assert.NoError(t, err)
+		assert.Nil(t, query)
+	})
+
+	t.Run("正常系:検索済みのキーワードを保存する", func(t *testing.T) {
+		assert.NoError(t, repo.SaveSearchQuery(ctx, "てすと"))
+		assert.NoError(t, repo.SaveSearchQuery(ctx, "てすと"))
+
+		query, err := repo.FindSearchQuery(ctx, "てすと")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "てすと", query.Query)
+		assert.False(t, query.SearchedAt.IsZero())
+	})
+}
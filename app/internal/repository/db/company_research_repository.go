@@ -2,17 +2,22 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"es-api/app/infrastructure/db"
 	"es-api/app/internal/contextKey"
 	"es-api/app/internal/entity/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CompanyResearchRepository interface {
 	FindByCompanyID(ctx context.Context, companyID string) (*model.CompanyResearch, error)
 	Create(ctx context.Context, research *model.CompanyResearch) error
+	Upsert(ctx context.Context, research *model.CompanyResearch) error
+	MarkSearched(ctx context.Context, companyID string, searchedAt time.Time) error
+	DeleteByCompanyID(ctx context.Context, companyID string) error
 }
 
 type companyResearchRepository struct {
@@ -33,18 +38,20 @@ func NewCompanyResearchRepositoryWithDBManager(dbManager db.DBConnectionManager)
 	}
 }
 
-// FindByCompanyID - 法人番号で企業情報を検索
-func (r *companyResearchRepository) FindByCompanyID(ctx context.Context, companyID string) (*model.CompanyResearch, error) {
+func (r *companyResearchRepository) conn(ctx context.Context) *gorm.DB {
 	idp := ctx.Value(contextKey.IDPKey).(string)
-	var dbConn *gorm.DB
 	if r.dbManager != nil && idp != "" {
-		dbConn = r.dbManager.GetConnection(idp)
-	} else {
-		dbConn = r.defaultDB
+		return r.dbManager.GetConnection(idp)
 	}
+	return r.defaultDB
+}
 
+// FindByCompanyID - 法人番号で企業情報を検索
+func (r *companyResearchRepository) FindByCompanyID(ctx context.Context, companyID string) (*model.CompanyResearch, error) {
 	var research model.CompanyResearch
-	result := dbConn.Where("company_id = ?", companyID).Find(&research)
+	result := r.conn(ctx).Preload("Citations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("company_id = ?", companyID).Find(&research)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -56,12 +63,49 @@ func (r *companyResearchRepository) FindByCompanyID(ctx context.Context, company
 
 // Create - 企業情報を新規作成
 func (r *companyResearchRepository) Create(ctx context.Context, research *model.CompanyResearch) error {
-	idp := ctx.Value(contextKey.IDPKey).(string)
-	var dbConn *gorm.DB
-	if r.dbManager != nil && idp != "" {
-		dbConn = r.dbManager.GetConnection(idp)
-	} else {
-		dbConn = r.defaultDB
+	return r.conn(ctx).Create(research).Error
+}
+
+// Upsert - 企業情報を保存。同じ法人番号の企業情報がある場合は内容を更新し、出典はresearch.Citationsで置き換える
+func (r *companyResearchRepository) Upsert(ctx context.Context, research *model.CompanyResearch) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "company_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"company_name", "contents", "searched_at", "updated_at"}),
+		}).Create(research).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("company_research_id = ?", research.ID).Delete(&model.CompanyResearchCitations{}).Error; err != nil {
+			return err
+		}
+		if len(research.Citations) == 0 {
+			return nil
+		}
+		for i := range research.Citations {
+			research.Citations[i].ID = 0
+			research.Citations[i].CompanyResearchID = research.ID
+		}
+		return tx.Create(&research.Citations).Error
+	})
+}
+
+// MarkSearched - 検索に失敗した場合に、企業情報の内容はそのままで最後に検索した日時だけを記録する
+func (r *companyResearchRepository) MarkSearched(ctx context.Context, companyID string, searchedAt time.Time) error {
+	return r.conn(ctx).Model(&model.CompanyResearch{}).
+		Where("company_id = ?", companyID).
+		UpdateColumn("searched_at", searchedAt).Error
+}
+
+// DeleteByCompanyID - 法人番号で企業情報を削除。存在しない場合はgorm.ErrRecordNotFoundを返す
+func (r *companyResearchRepository) DeleteByCompanyID(ctx context.Context, companyID string) error {
+	result := r.conn(ctx).Where("company_id = ?", companyID).Delete(&model.CompanyResearch{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
 	}
-	return dbConn.Create(research).Error
+	return nil
 }
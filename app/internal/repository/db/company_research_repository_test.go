@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 
 	"es-api/app/internal/entity/model"
 	repository "es-api/app/internal/repository/db"
@@ -35,9 +36,7 @@ func TestCompanyResearchRepository_FindByCompanyID(t *testing.T) {
 		assert.NotNil(t, research)
 		assert.Equal(t, dummyResearch.CompanyID, research.CompanyID)
 		assert.Equal(t, dummyResearch.CompanyName, research.CompanyName)
-		assert.Equal(t, dummyResearch.Philosophy, research.Philosophy)
-		assert.Equal(t, dummyResearch.CareerPath, research.CareerPath)
-		assert.Equal(t, dummyResearch.TalentNeeds, research.TalentNeeds)
+		assert.Equal(t, dummyResearch.Contents, research.Contents)
 	})
 }
 
@@ -51,9 +50,11 @@ func TestCompanyResearchRepository_Create(t *testing.T) {
 		newResearch := &model.CompanyResearch{
 			CompanyID:   "9999999999999",
 			CompanyName: "テスト株式会社2",
-			Philosophy:  "テスト企業理念2",
-			CareerPath:  "テストキャリアパス2",
-			TalentNeeds: "テスト求める人材像2",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy:  "テスト企業理念2",
+				model.ResearchSectionCareerPath:  "テストキャリアパス2",
+				model.ResearchSectionTalentNeeds: "テスト求める人材像2",
+			},
 		}
 
 		ctx := test.SetupContextContext("test-user-id")
@@ -63,3 +64,86 @@ func TestCompanyResearchRepository_Create(t *testing.T) {
 		assert.NotZero(t, newResearch.ID)
 	})
 }
+
+func TestCompanyResearchRepository_Upsert(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewCompanyResearchRepository(db)
+
+	t.Run("正常系:同じ法人番号の企業情報がある場合は更新する", func(t *testing.T) {
+		dummyResearch := factory.CreateCompanyResearch(t, db)
+
+		ctx := test.SetupContextContext("test-user-id")
+		err := repo.Upsert(ctx, &model.CompanyResearch{
+			CompanyID:   dummyResearch.CompanyID,
+			CompanyName: dummyResearch.CompanyName,
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy:  "更新した企業理念",
+				model.ResearchSectionCareerPath:  dummyResearch.Contents[model.ResearchSectionCareerPath],
+				model.ResearchSectionTalentNeeds: dummyResearch.Contents[model.ResearchSectionTalentNeeds],
+			},
+		})
+		assert.NoError(t, err)
+
+		research, err := repo.FindByCompanyID(ctx, dummyResearch.CompanyID)
+		assert.NoError(t, err)
+		assert.Equal(t, dummyResearch.ID, research.ID)
+		assert.Equal(t, "更新した企業理念", research.Contents[model.ResearchSectionPhilosophy])
+		assert.True(t, research.UpdatedAt.After(dummyResearch.UpdatedAt))
+	})
+
+	t.Run("正常系:出典を置き換える", func(t *testing.T) {
+		ctx := test.SetupContextContext("test-user-id")
+		research := &model.CompanyResearch{
+			CompanyID:   "8888888888888",
+			CompanyName: "テスト株式会社3",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy: "テスト企業理念3",
+			},
+			Citations: []model.CompanyResearchCitations{
+				{Section: model.ResearchSectionPhilosophy, Title: "古い記事", URL: "https://example.com/old"},
+			},
+		}
+		assert.NoError(t, repo.Upsert(ctx, research))
+
+		research.Citations = []model.CompanyResearchCitations{
+			{Section: model.ResearchSectionPhilosophy, Title: "企業情報", URL: "https://example.com/about"},
+			{Section: model.ResearchSectionCareerPath, Title: "社員インタビュー", URL: "https://example.com/interview"},
+		}
+		assert.NoError(t, repo.Upsert(ctx, research))
+
+		found, err := repo.FindByCompanyID(ctx, "8888888888888")
+		assert.NoError(t, err)
+		if assert.Len(t, found.Citations, 2) {
+			assert.Equal(t, "https://example.com/about", found.Citations[0].URL)
+			assert.Equal(t, model.ResearchSectionCareerPath, found.Citations[1].Section)
+		}
+	})
+}
+
+func TestCompanyResearchRepository_DeleteByCompanyID(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewCompanyResearchRepository(db)
+
+	t.Run("正常系:企業情報を削除できる", func(t *testing.T) {
+		dummyResearch := factory.CreateCompanyResearch(t, db)
+
+		ctx := test.SetupContextContext("test-user-id")
+		err := repo.DeleteByCompanyID(ctx, dummyResearch.CompanyID)
+		assert.NoError(t, err)
+
+		research, err := repo.FindByCompanyID(ctx, dummyResearch.CompanyID)
+		assert.NoError(t, err)
+		assert.Nil(t, research)
+	})
+
+	t.Run("異常系:企業情報が存在しない場合", func(t *testing.T) {
+		ctx := test.SetupContextContext("test-user-id")
+		err := repo.DeleteByCompanyID(ctx, "non-existent-id")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"es-api/app/infrastructure/db"
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+)
+
+// ExperienceEntryRepository - 経験プロフィールに紐づくエピソード・スキル・実績を扱う
+// 他のユーザーのプロフィールやエントリーを指定した場合はgorm.ErrRecordNotFoundを返す
+type ExperienceEntryRepository interface {
+	ListEpisodes(ctx context.Context, profileID string) ([]model.ExperienceEpisodes, error)
+	CreateEpisode(ctx context.Context, profileID string, input model.InputEpisode) (model.ExperienceEpisodes, error)
+	UpdateEpisode(ctx context.Context, episodeID string, input model.InputEpisode) (model.ExperienceEpisodes, error)
+	DeleteEpisode(ctx context.Context, episodeID string) error
+	ListSkills(ctx context.Context, profileID string) ([]model.ExperienceSkills, error)
+	CreateSkill(ctx context.Context, profileID string, input model.InputSkill) (model.ExperienceSkills, error)
+	UpdateSkill(ctx context.Context, skillID string, input model.InputSkill) (model.ExperienceSkills, error)
+	DeleteSkill(ctx context.Context, skillID string) error
+	ListAchievements(ctx context.Context, profileID string) ([]model.ExperienceAchievements, error)
+	CreateAchievement(ctx context.Context, profileID string, input model.InputAchievement) (model.ExperienceAchievements, error)
+	UpdateAchievement(ctx context.Context, achievementID string, input model.InputAchievement) (model.ExperienceAchievements, error)
+	DeleteAchievement(ctx context.Context, achievementID string) error
+}
+
+type experienceEntryRepository struct {
+	dbManager db.DBConnectionManager
+	defaultDB *gorm.DB
+}
+
+func NewExperienceEntryRepository(defaultDB *gorm.DB) ExperienceEntryRepository {
+	return &experienceEntryRepository{
+		defaultDB: defaultDB,
+	}
+}
+
+func NewExperienceEntryRepositoryWithDBManager(dbManager db.DBConnectionManager) ExperienceEntryRepository {
+	return &experienceEntryRepository{
+		dbManager: dbManager,
+		defaultDB: dbManager.GetConnection("clerk"),
+	}
+}
+
+func (r *experienceEntryRepository) conn(ctx context.Context) *gorm.DB {
+	idp := ctx.Value(contextKey.IDPKey).(string)
+	if r.dbManager != nil && idp != "" {
+		return r.dbManager.GetConnection(idp)
+	}
+	return r.defaultDB
+}
+
+// ownedProfile - プロフィールがユーザーのものかを確認する
+func (r *experienceEntryRepository) ownedProfile(ctx context.Context, profileID string) error {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var experience model.Experiences
+	return r.conn(ctx).Select("id").Where("id = ? AND user_id = ?", profileID, userID).First(&experience).Error
+}
+
+// listEntries - プロフィールに紐づくエントリーを作成順に取得する
+func (r *experienceEntryRepository) listEntries(ctx context.Context, profileID string, dest interface{}) error {
+	if err := r.ownedProfile(ctx, profileID); err != nil {
+		return err
+	}
+	return r.conn(ctx).Where("experience_id = ?", profileID).Order("created_at").Find(dest).Error
+}
+
+// findEntry - ユーザーのエントリーをIDで取得する
+func (r *experienceEntryRepository) findEntry(ctx context.Context, entryID string, dest interface{}) error {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+	return r.conn(ctx).Where("id = ? AND user_id = ?", entryID, userID).First(dest).Error
+}
+
+// deleteEntry - ユーザーのエントリーを削除する。対象がない場合はgorm.ErrRecordNotFound
+func (r *experienceEntryRepository) deleteEntry(ctx context.Context, entryID string, entry interface{}) error {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+	result := r.conn(ctx).Where("id = ? AND user_id = ?", entryID, userID).Delete(entry)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *experienceEntryRepository) ListEpisodes(ctx context.Context, profileID string) ([]model.ExperienceEpisodes, error) {
+	episodes := []model.ExperienceEpisodes{}
+	if err := r.listEntries(ctx, profileID, &episodes); err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
+
+func (r *experienceEntryRepository) CreateEpisode(ctx context.Context, profileID string, input model.InputEpisode) (model.ExperienceEpisodes, error) {
+	if err := r.ownedProfile(ctx, profileID); err != nil {
+		return model.ExperienceEpisodes{}, err
+	}
+
+	episode := model.ExperienceEpisodes{
+		ExperienceID: profileID,
+		UserID:       ctx.Value(contextKey.UserIDKey).(string),
+	}
+	applyEpisodeInput(&episode, input)
+	if err := r.conn(ctx).Omit("Experience").Create(&episode).Error; err != nil {
+		return model.ExperienceEpisodes{}, err
+	}
+	return episode, nil
+}
+
+func (r *experienceEntryRepository) UpdateEpisode(ctx context.Context, episodeID string, input model.InputEpisode) (model.ExperienceEpisodes, error) {
+	var episode model.ExperienceEpisodes
+	if err := r.findEntry(ctx, episodeID, &episode); err != nil {
+		return model.ExperienceEpisodes{}, err
+	}
+
+	applyEpisodeInput(&episode, input)
+	if err := r.conn(ctx).Omit("Experience").Save(&episode).Error; err != nil {
+		return model.ExperienceEpisodes{}, err
+	}
+	return episode, nil
+}
+
+func (r *experienceEntryRepository) DeleteEpisode(ctx context.Context, episodeID string) error {
+	return r.deleteEntry(ctx, episodeID, &model.ExperienceEpisodes{})
+}
+
+func applyEpisodeInput(episode *model.ExperienceEpisodes, input model.InputEpisode) {
+	episode.Title = input.Title
+	episode.Period = input.Period
+	episode.Role = input.Role
+	episode.Situation = input.Situation
+	episode.Action = input.Action
+	episode.Result = input.Result
+	episode.Metrics = input.Metrics
+}
+
+func (r *experienceEntryRepository) ListSkills(ctx context.Context, profileID string) ([]model.ExperienceSkills, error) {
+	skills := []model.ExperienceSkills{}
+	if err := r.listEntries(ctx, profileID, &skills); err != nil {
+		return nil, err
+	}
+	return skills, nil
+}
+
+func (r *experienceEntryRepository) CreateSkill(ctx context.Context, profileID string, input model.InputSkill) (model.ExperienceSkills, error) {
+	if err := r.ownedProfile(ctx, profileID); err != nil {
+		return model.ExperienceSkills{}, err
+	}
+
+	skill := model.ExperienceSkills{
+		ExperienceID: profileID,
+		UserID:       ctx.Value(contextKey.UserIDKey).(string),
+		Name:         input.Name,
+		Level:        input.Level,
+	}
+	if err := r.conn(ctx).Omit("Experience").Create(&skill).Error; err != nil {
+		return model.ExperienceSkills{}, err
+	}
+	return skill, nil
+}
+
+func (r *experienceEntryRepository) UpdateSkill(ctx context.Context, skillID string, input model.InputSkill) (model.ExperienceSkills, error) {
+	var skill model.ExperienceSkills
+	if err := r.findEntry(ctx, skillID, &skill); err != nil {
+		return model.ExperienceSkills{}, err
+	}
+
+	skill.Name = input.Name
+	skill.Level = input.Level
+	if err := r.conn(ctx).Omit("Experience").Save(&skill).Error; err != nil {
+		return model.ExperienceSkills{}, err
+	}
+	return skill, nil
+}
+
+func (r *experienceEntryRepository) DeleteSkill(ctx context.Context, skillID string) error {
+	return r.deleteEntry(ctx, skillID, &model.ExperienceSkills{})
+}
+
+func (r *experienceEntryRepository) ListAchievements(ctx context.Context, profileID string) ([]model.ExperienceAchievements, error) {
+	achievements := []model.ExperienceAchievements{}
+	if err := r.listEntries(ctx, profileID, &achievements); err != nil {
+		return nil, err
+	}
+	return achievements, nil
+}
+
+func (r *experienceEntryRepository) CreateAchievement(ctx context.Context, profileID string, input model.InputAchievement) (model.ExperienceAchievements, error) {
+	if err := r.ownedProfile(ctx, profileID); err != nil {
+		return model.ExperienceAchievements{}, err
+	}
+
+	achievement := model.ExperienceAchievements{
+		ExperienceID: profileID,
+		UserID:       ctx.Value(contextKey.UserIDKey).(string),
+	}
+	applyAchievementInput(&achievement, input)
+	if err := r.conn(ctx).Omit("Experience").Create(&achievement).Error; err != nil {
+		return model.ExperienceAchievements{}, err
+	}
+	return achievement, nil
+}
+
+func (r *experienceEntryRepository) UpdateAchievement(ctx context.Context, achievementID string, input model.InputAchievement) (model.ExperienceAchievements, error) {
+	var achievement model.ExperienceAchievements
+	if err := r.findEntry(ctx, achievementID, &achievement); err != nil {
+		return model.ExperienceAchievements{}, err
+	}
+
+	applyAchievementInput(&achievement, input)
+	if err := r.conn(ctx).Omit("Experience").Save(&achievement).Error; err != nil {
+		return model.ExperienceAchievements{}, err
+	}
+	return achievement, nil
+}
+
+func (r *experienceEntryRepository) DeleteAchievement(ctx context.Context, achievementID string) error {
+	return r.deleteEntry(ctx, achievementID, &model.ExperienceAchievements{})
+}
+
+func applyAchievementInput(achievement *model.ExperienceAchievements, input model.InputAchievement) {
+	achievement.Title = input.Title
+	achievement.Description = input.Description
+	achievement.Tags = input.Tags
+	if achievement.Tags == nil {
+		achievement.Tags = []string{}
+	}
+	achievement.AchievedAt = input.AchievedAt
+}
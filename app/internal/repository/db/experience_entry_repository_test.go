@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+	"es-api/app/test"
+	"es-api/app/test/factory"
+)
+
+func TestExperienceEntryRepository_Episodes(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewExperienceEntryRepository(db)
+	experienceRepo := repository.NewExperienceRepository(db)
+	dummyUser := factory.CreateUser1(t, db)
+	dummyExperience := factory.CreateExperience1(t, db)
+
+	ctx := test.SetupContextContext("test-user-id")
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, dummyUser.ID)
+
+	var created model.ExperienceEpisodes
+
+	t.Run("正常系:エピソードを作成するとプロフィールと一緒に取得できる", func(t *testing.T) {
+		var err error
+		created, err = repo.CreateEpisode(ctx, dummyExperience.ID, model.InputEpisode{Title: "学園祭実行委員", Result: "来場者数が前年比120%"})
+		assert.NoError(t, err)
+		assert.Equal(t, dummyExperience.ID, created.ExperienceID)
+
+		experience, err := experienceRepo.GetExperienceByUserID(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, experience.Episodes, 1)
+		assert.Equal(t, "学園祭実行委員", experience.Episodes[0].Title)
+	})
+
+	t.Run("正常系:エピソードを更新できる", func(t *testing.T) {
+		res, err := repo.UpdateEpisode(ctx, created.ID, model.InputEpisode{Title: "学園祭実行委員長"})
+		assert.NoError(t, err)
+		assert.Equal(t, "学園祭実行委員長", res.Title)
+
+		episodes, err := repo.ListEpisodes(ctx, dummyExperience.ID)
+		assert.NoError(t, err)
+		assert.Len(t, episodes, 1)
+		assert.Equal(t, "学園祭実行委員長", episodes[0].Title)
+	})
+
+	t.Run("異常系:他のユーザーのプロフィールには作成できない", func(t *testing.T) {
+		_ = factory.CreateUser2(t, db)
+		other := factory.CreateExperience2(t, db)
+
+		_, err := repo.CreateEpisode(ctx, other.ID, model.InputEpisode{Title: "title"})
+		assert.Error(t, err)
+	})
+
+	t.Run("正常系:プロフィールを削除するとエピソードも削除される", func(t *testing.T) {
+		err := experienceRepo.DeleteExperience(ctx, dummyExperience.ID)
+		assert.NoError(t, err)
+
+		err = repo.DeleteEpisode(ctx, created.ID)
+		assert.Error(t, err)
+	})
+}
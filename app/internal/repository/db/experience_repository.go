@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"es-api/app/infrastructure/db"
 	"es-api/app/internal/contextKey"
@@ -16,6 +17,12 @@ type ExperienceRepository interface {
 	FindExperienceByUserID(ctx context.Context) (bool, error)
 	PostExperience(ctx context.Context, input model.InputExperience) (model.Experiences, error)
 	PatchExperience(ctx context.Context, input model.InputExperience) (model.Experiences, error)
+	ListExperiences(ctx context.Context) ([]model.Experiences, error)
+	GetExperienceByID(ctx context.Context, profileID string) (model.Experiences, error)
+	CreateExperience(ctx context.Context, input model.InputExperience, isDefault bool) (model.Experiences, error)
+	UpdateExperience(ctx context.Context, profileID string, input model.InputExperience) (model.Experiences, error)
+	DeleteExperience(ctx context.Context, profileID string) error
+	SetDefaultExperience(ctx context.Context, profileID string) (model.Experiences, error)
 }
 
 type experienceRepository struct {
@@ -36,17 +43,19 @@ func NewExperienceRepositoryWithDBManager(dbManager db.DBConnectionManager) Expe
 	}
 }
 
-func (r *experienceRepository) GetExperienceByUserID(ctx context.Context) (model.Experiences, error) {
-	var experience model.Experiences
+func (r *experienceRepository) conn(ctx context.Context) *gorm.DB {
 	idp := ctx.Value(contextKey.IDPKey).(string)
-	userID := ctx.Value(contextKey.UserIDKey).(string)
-	var dbConn *gorm.DB
 	if r.dbManager != nil && idp != "" {
-		dbConn = r.dbManager.GetConnection(idp)
-	} else {
-		dbConn = r.defaultDB
+		return r.dbManager.GetConnection(idp)
 	}
-	result := dbConn.First(&experience, "user_id = ?", userID)
+	return r.defaultDB
+}
+
+// GetExperienceByUserID - ユーザーのデフォルトのプロフィールを取得
+func (r *experienceRepository) GetExperienceByUserID(ctx context.Context) (model.Experiences, error) {
+	var experience model.Experiences
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+	result := withExperienceEntries(r.conn(ctx)).Order("is_default DESC, created_at").First(&experience, "user_id = ?", userID)
 	if result.Error != nil {
 		return model.Experiences{}, result.Error
 	}
@@ -55,15 +64,8 @@ func (r *experienceRepository) GetExperienceByUserID(ctx context.Context) (model
 
 func (r *experienceRepository) FindExperienceByUserID(ctx context.Context) (bool, error) {
 	var experience model.Experiences
-	idp := ctx.Value(contextKey.IDPKey).(string)
 	userID := ctx.Value(contextKey.UserIDKey).(string)
-	var dbConn *gorm.DB
-	if r.dbManager != nil && idp != "" {
-		dbConn = r.dbManager.GetConnection(idp)
-	} else {
-		dbConn = r.defaultDB
-	}
-	result := dbConn.Where("user_id = ?", userID).First(&experience)
+	result := r.conn(ctx).Where("user_id = ?", userID).First(&experience)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return false, nil
@@ -73,51 +75,156 @@ func (r *experienceRepository) FindExperienceByUserID(ctx context.Context) (bool
 	return true, nil
 }
 
+// PostExperience - デフォルトのプロフィールを作成
 func (r *experienceRepository) PostExperience(ctx context.Context, input model.InputExperience) (model.Experiences, error) {
-	idp := ctx.Value(contextKey.IDPKey).(string)
+	return r.CreateExperience(ctx, input, true)
+}
+
+// PatchExperience - デフォルトのプロフィールを更新
+func (r *experienceRepository) PatchExperience(ctx context.Context, input model.InputExperience) (model.Experiences, error) {
+	experience, err := r.GetExperienceByUserID(ctx)
+	if err != nil {
+		return model.Experiences{}, err
+	}
+	return r.UpdateExperience(ctx, experience.ID, input)
+}
+
+// ListExperiences - ユーザーのプロフィール一覧。デフォルトのプロフィールを先頭にする
+func (r *experienceRepository) ListExperiences(ctx context.Context) ([]model.Experiences, error) {
 	userID := ctx.Value(contextKey.UserIDKey).(string)
-	var dbConn *gorm.DB
-	if r.dbManager != nil && idp != "" {
-		dbConn = r.dbManager.GetConnection(idp)
-	} else {
-		dbConn = r.defaultDB
+
+	var experiences []model.Experiences
+	result := r.conn(ctx).Where("user_id = ?", userID).Order("is_default DESC, created_at").Find(&experiences)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return experiences, nil
+}
+
+// GetExperienceByID - ユーザーのプロフィールをIDで取得。他のユーザーのプロフィールはgorm.ErrRecordNotFoundになる
+func (r *experienceRepository) GetExperienceByID(ctx context.Context, profileID string) (model.Experiences, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var experience model.Experiences
+	result := withExperienceEntries(r.conn(ctx)).Where("id = ? AND user_id = ?", profileID, userID).First(&experience)
+	if result.Error != nil {
+		return model.Experiences{}, result.Error
+	}
+	return experience, nil
+}
+
+// withExperienceEntries - エピソード・スキル・実績を作成順に読み込む
+func withExperienceEntries(tx *gorm.DB) *gorm.DB {
+	byCreatedAt := func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}
+	return tx.Preload("Episodes", byCreatedAt).
+		Preload("SkillEntries", byCreatedAt).
+		Preload("Achievements", byCreatedAt)
+}
+
+// CreateExperience - プロフィールを作成。isDefaultがtrueの場合は既存のデフォルトを解除する
+func (r *experienceRepository) CreateExperience(ctx context.Context, input model.InputExperience, isDefault bool) (model.Experiences, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+	name := input.Name
+	if name == "" {
+		name = model.DefaultExperienceProfileName
 	}
 	experience := model.Experiences{
 		UserID:      userID,
+		Name:        name,
+		IsDefault:   isDefault,
 		Work:        input.Work,
 		Skills:      input.Skills,
 		SelfPR:      input.SelfPR,
 		FutureGoals: input.FutureGoals,
 	}
 
-	result := dbConn.Create(&experience)
-	if result.Error != nil {
-		return model.Experiences{}, result.Error
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if isDefault {
+			if err := clearDefaultExperience(tx, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&experience).Error
+	})
+	if err != nil {
+		return model.Experiences{}, err
 	}
 	return experience, nil
 }
 
-func (r *experienceRepository) PatchExperience(ctx context.Context, input model.InputExperience) (model.Experiences, error) {
-	idp := ctx.Value(contextKey.IDPKey).(string)
-	userID := ctx.Value(contextKey.UserIDKey).(string)
-	var dbConn *gorm.DB
-	if r.dbManager != nil && idp != "" {
-		dbConn = r.dbManager.GetConnection(idp)
-	} else {
-		dbConn = r.defaultDB
+// UpdateExperience - プロフィールの内容を更新。Nameが空の場合は名前を変更しない
+func (r *experienceRepository) UpdateExperience(ctx context.Context, profileID string, input model.InputExperience) (model.Experiences, error) {
+	experience, err := r.GetExperienceByID(ctx, profileID)
+	if err != nil {
+		return model.Experiences{}, err
 	}
 
-	var experience model.Experiences
-	_ = dbConn.Where("user_id = ?", userID).First(&experience)
-
+	if input.Name != "" {
+		experience.Name = input.Name
+	}
 	experience.Work = input.Work
 	experience.Skills = input.Skills
 	experience.SelfPR = input.SelfPR
 	experience.FutureGoals = input.FutureGoals
 
-	result := dbConn.Save(&experience)
+	// 読み込んだエピソード等は別のエンドポイントで更新するため保存しない
+	result := r.conn(ctx).Omit(clause.Associations).Save(&experience)
 	if result.Error != nil {
 		return model.Experiences{}, result.Error
 	}
 	return experience, nil
 }
+
+// DeleteExperience - プロフィールを削除。デフォルトを削除した場合は最も古いプロフィールをデフォルトにする
+func (r *experienceRepository) DeleteExperience(ctx context.Context, profileID string) error {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		var experience model.Experiences
+		if err := tx.Where("id = ? AND user_id = ?", profileID, userID).First(&experience).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&experience).Error; err != nil {
+			return err
+		}
+		if !experience.IsDefault {
+			return nil
+		}
+
+		var next model.Experiences
+		result := tx.Where("user_id = ?", userID).Order("created_at").Limit(1).Find(&next)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+// SetDefaultExperience - 指定したプロフィールをデフォルトにする
+func (r *experienceRepository) SetDefaultExperience(ctx context.Context, profileID string) (model.Experiences, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var experience model.Experiences
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", profileID, userID).First(&experience).Error; err != nil {
+			return err
+		}
+		if err := clearDefaultExperience(tx, userID); err != nil {
+			return err
+		}
+		experience.IsDefault = true
+		return tx.Model(&experience).Update("is_default", true).Error
+	})
+	if err != nil {
+		return model.Experiences{}, err
+	}
+	return experience, nil
+}
+
+func clearDefaultExperience(tx *gorm.DB, userID string) error {
+	return tx.Model(&model.Experiences{}).
+		Where("user_id = ? AND is_default", userID).
+		Update("is_default", false).Error
+}
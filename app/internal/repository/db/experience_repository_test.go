@@ -126,3 +126,60 @@ func TestExperienceRepository_PatchExperience(t *testing.T) {
 		assert.Equal(t, experience.FutureGoals, input.FutureGoals)
 	})
 }
+
+func TestExperienceRepository_Profiles(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewExperienceRepository(db)
+	dummyUser := factory.CreateUser1(t, db)
+	defaultExperience := factory.CreateExperience1(t, db)
+
+	ctx := test.SetupContextContext("test-user-id")
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, dummyUser.ID)
+
+	var created model.Experiences
+
+	t.Run("正常系:プロフィールを追加してもデフォルトは変わらない", func(t *testing.T) {
+		var err error
+		created, err = repo.CreateExperience(ctx, model.InputExperience{Name: "IT業界向け", Work: "work2"}, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "IT業界向け", created.Name)
+		assert.False(t, created.IsDefault)
+
+		res, err := repo.GetExperienceByUserID(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, defaultExperience.ID, res.ID)
+	})
+
+	t.Run("正常系:デフォルトを切り替えられる", func(t *testing.T) {
+		res, err := repo.SetDefaultExperience(ctx, created.ID)
+
+		assert.NoError(t, err)
+		assert.True(t, res.IsDefault)
+
+		profiles, err := repo.ListExperiences(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, profiles, 2)
+		assert.Equal(t, created.ID, profiles[0].ID)
+		assert.False(t, profiles[1].IsDefault)
+	})
+
+	t.Run("正常系:デフォルトを削除すると残りのプロフィールがデフォルトになる", func(t *testing.T) {
+		err := repo.DeleteExperience(ctx, created.ID)
+		assert.NoError(t, err)
+
+		res, err := repo.GetExperienceByID(ctx, defaultExperience.ID)
+		assert.NoError(t, err)
+		assert.True(t, res.IsDefault)
+	})
+
+	t.Run("異常系:他のユーザーのプロフィールは取得できない", func(t *testing.T) {
+		_ = factory.CreateUser2(t, db)
+		other := factory.CreateExperience2(t, db)
+
+		_, err := repo.GetExperienceByID(ctx, other.ID)
+		assert.Error(t, err)
+	})
+}
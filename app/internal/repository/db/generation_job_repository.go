@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"es-api/app/infrastructure/db"
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+)
+
+type GenerationJobRepository interface {
+	Create(ctx context.Context, job *model.GenerationJobs) error
+	FindByID(ctx context.Context, id string) (*model.GenerationJobs, error)
+	RequestCancel(ctx context.Context, id string) (*model.GenerationJobs, error)
+	ClaimNext(ctx context.Context) (*model.GenerationJobs, error)
+	Heartbeat(ctx context.Context, id string, attempt int) (bool, error)
+	UpdateProgress(ctx context.Context, id string, attempt int, progress []model.GenerationJobQuestion) error
+	Finish(ctx context.Context, id string, attempt int, status model.GenerationJobStatus, answers []model.LLMGeneratedResponse, errMsg string) error
+	RequeueStale(ctx context.Context, staleBefore time.Time, maxAttempts int) (int64, error)
+}
+
+type generationJobRepository struct {
+	dbManager db.DBConnectionManager
+	defaultDB *gorm.DB
+}
+
+func NewGenerationJobRepository(defaultDB *gorm.DB) GenerationJobRepository {
+	return &generationJobRepository{
+		defaultDB: defaultDB,
+	}
+}
+
+func NewGenerationJobRepositoryWithDBManager(dbManager db.DBConnectionManager) GenerationJobRepository {
+	return &generationJobRepository{
+		dbManager: dbManager,
+		defaultDB: dbManager.GetConnection("clerk"),
+	}
+}
+
+func (r *generationJobRepository) conn(ctx context.Context) *gorm.DB {
+	idp := ctx.Value(contextKey.IDPKey).(string)
+	if r.dbManager != nil && idp != "" {
+		return r.dbManager.GetConnection(idp)
+	}
+	return r.defaultDB
+}
+
+// Create - ジョブを実行待ちとして保存。UserIDはコンテキストのユーザーで上書きする
+func (r *generationJobRepository) Create(ctx context.Context, job *model.GenerationJobs) error {
+	job.UserID = ctx.Value(contextKey.UserIDKey).(string)
+	job.Status = model.GenerationJobStatusQueued
+	return r.conn(ctx).Create(job).Error
+}
+
+// FindByID - ユーザーのジョブをIDで取得。他のユーザーのジョブは見つからない扱いにする
+func (r *generationJobRepository) FindByID(ctx context.Context, id string) (*model.GenerationJobs, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var job model.GenerationJobs
+	result := r.conn(ctx).Where("id = ? AND user_id = ?", id, userID).First(&job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// RequestCancel - ユーザーのジョブのキャンセルを要求する。実行待ちのジョブはその場でキャンセル済みにする
+// 終了済みのジョブは変更せず、そのまま返す。ジョブが存在しない場合はnilを返す
+func (r *generationJobRepository) RequestCancel(ctx context.Context, id string) (*model.GenerationJobs, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	err := r.conn(ctx).Model(&model.GenerationJobs{}).
+		Where("id = ? AND user_id = ? AND status IN ?", id, userID, []model.GenerationJobStatus{model.GenerationJobStatusQueued, model.GenerationJobStatusRunning}).
+		Updates(map[string]interface{}{
+			"cancel_requested": true,
+			"status":           gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END", model.GenerationJobStatusQueued, model.GenerationJobStatusCanceled),
+			"finished_at":      gorm.Expr("CASE WHEN status = ? THEN ? ELSE finished_at END", model.GenerationJobStatusQueued, time.Now()),
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
+// ClaimNext - 最も古い実行待ちのジョブを実行中にして返す。実行待ちのジョブがない場合はnilを返す
+// 複数のワーカーが同時に呼び出しても、同じジョブを取得しないようにSKIP LOCKEDで行をロックする
+func (r *generationJobRepository) ClaimNext(ctx context.Context) (*model.GenerationJobs, error) {
+	var claimed *model.GenerationJobs
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		var job model.GenerationJobs
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", model.GenerationJobStatusQueued).
+			Order("created_at").
+			Limit(1).
+			Find(&job)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		job.Status = model.GenerationJobStatusRunning
+		job.Attempts++
+		job.HeartbeatAt = &now
+		if job.StartedAt == nil {
+			job.StartedAt = &now
+		}
+		if err := tx.Model(&job).Select("status", "attempts", "heartbeat_at", "started_at").Updates(&job).Error; err != nil {
+			return err
+		}
+		claimed = &job
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// Heartbeat - 実行中のジョブの生存を通知し、キャンセルが要求されているかを返す。attemptはClaimNextで取得した時点の実行回数
+// 実行待ちに戻されて別のワーカーが実行し直している場合や、終了済みの場合は更新せずgorm.ErrRecordNotFoundを返す
+func (r *generationJobRepository) Heartbeat(ctx context.Context, id string, attempt int) (bool, error) {
+	result := r.conn(ctx).Model(&model.GenerationJobs{}).
+		Where("id = ? AND status = ? AND attempts = ?", id, model.GenerationJobStatusRunning, attempt).
+		Update("heartbeat_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, gorm.ErrRecordNotFound
+	}
+
+	var job model.GenerationJobs
+	if err := r.conn(ctx).Select("cancel_requested").Where("id = ?", id).First(&job).Error; err != nil {
+		return false, err
+	}
+	return job.CancelRequested, nil
+}
+
+// UpdateProgress - 質問ごとの進捗を保存する。進捗の更新も生存の通知とみなす
+// Heartbeatと同様に、attemptの実行が続いていない場合は更新せずgorm.ErrRecordNotFoundを返す
+func (r *generationJobRepository) UpdateProgress(ctx context.Context, id string, attempt int, progress []model.GenerationJobQuestion) error {
+	now := time.Now()
+	result := r.conn(ctx).Model(&model.GenerationJobs{ID: id}).
+		Where("status = ? AND attempts = ?", model.GenerationJobStatusRunning, attempt).
+		Select("progress", "heartbeat_at").
+		Updates(&model.GenerationJobs{Progress: progress, HeartbeatAt: &now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Finish - ジョブを終了状態にして、生成結果を保存する。attemptはClaimNextで取得した時点の実行回数
+// 生存通知が途絶えて実行待ちに戻され、別のワーカーが実行し直している場合や、終了済みの場合は更新せずgorm.ErrRecordNotFoundを返す
+func (r *generationJobRepository) Finish(ctx context.Context, id string, attempt int, status model.GenerationJobStatus, answers []model.LLMGeneratedResponse, errMsg string) error {
+	now := time.Now()
+	result := r.conn(ctx).Model(&model.GenerationJobs{ID: id}).
+		Where("status = ? AND attempts = ?", model.GenerationJobStatusRunning, attempt).
+		Select("status", "answers", "error", "finished_at").
+		Updates(&model.GenerationJobs{Status: status, Answers: answers, Error: errMsg, FinishedAt: &now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RequeueStale - staleBefore以降に生存の通知がない実行中のジョブを、ワーカーが停止したとみなして実行待ちに戻す
+// キャンセルが要求されていたジョブはキャンセル済みに、maxAttempts回実行を始めたジョブは失敗にする
+func (r *generationJobRepository) RequeueStale(ctx context.Context, staleBefore time.Time, maxAttempts int) (int64, error) {
+	now := time.Now()
+	stale := func() *gorm.DB {
+		return r.conn(ctx).Model(&model.GenerationJobs{}).
+			Where("status = ? AND heartbeat_at < ?", model.GenerationJobStatusRunning, staleBefore)
+	}
+
+	if err := stale().Where("cancel_requested").Updates(map[string]interface{}{
+		"status":      model.GenerationJobStatusCanceled,
+		"finished_at": now,
+	}).Error; err != nil {
+		return 0, err
+	}
+	if err := stale().Where("attempts >= ?", maxAttempts).Updates(map[string]interface{}{
+		"status":      model.GenerationJobStatusFailed,
+		"error":       "ワーカーが停止したため、ジョブを完了できませんでした",
+		"finished_at": now,
+	}).Error; err != nil {
+		return 0, err
+	}
+
+	result := stale().Update("status", model.GenerationJobStatusQueued)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,163 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+	"es-api/app/test"
+	"es-api/app/test/factory"
+)
+
+func createGenerationJob(t *testing.T, repo repository.GenerationJobRepository, ctx context.Context) *model.GenerationJobs {
+	job := &model.GenerationJobs{
+		Request:     model.LLMGenerateRequest{CompanyName: "テスト株式会社", CompanyID: "1234567890123", HTML: "<textarea></textarea>"},
+		CompanyID:   "1234567890123",
+		CompanyName: "テスト株式会社",
+	}
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("Error creating generation job: %v", err)
+	}
+	return job
+}
+
+func setupGenerationJobTest(t *testing.T) (*gorm.DB, repository.GenerationJobRepository, context.Context) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	dummyUser := factory.CreateUser1(t, db)
+	ctx := context.WithValue(test.SetupContextContext("test-user-id"), contextKey.UserIDKey, dummyUser.ID)
+	return db, repository.NewGenerationJobRepository(db), ctx
+}
+
+func TestGenerationJobRepository_FindByID(t *testing.T) {
+	db, repo, ctx := setupGenerationJobTest(t)
+	defer test.CleanupDB(t, db)
+	otherUser := factory.CreateUser2(t, db)
+	job := createGenerationJob(t, repo, ctx)
+
+	t.Run("正常系:作成したジョブを実行待ちとして取得する", func(t *testing.T) {
+		res, err := repo.FindByID(ctx, job.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.GenerationJobStatusQueued, res.Status)
+		assert.Equal(t, "<textarea></textarea>", res.Request.HTML)
+	})
+
+	t.Run("異常系:他のユーザーのジョブは取得できない", func(t *testing.T) {
+		otherCtx := context.WithValue(ctx, contextKey.UserIDKey, otherUser.ID)
+
+		res, err := repo.FindByID(otherCtx, job.ID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestGenerationJobRepository_ClaimNext(t *testing.T) {
+	db, repo, ctx := setupGenerationJobTest(t)
+	defer test.CleanupDB(t, db)
+	first := createGenerationJob(t, repo, ctx)
+	second := createGenerationJob(t, repo, ctx)
+
+	t.Run("正常系:古いジョブから実行中にして取得し、進捗と結果を保存する", func(t *testing.T) {
+		claimed, err := repo.ClaimNext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, first.ID, claimed.ID)
+		assert.Equal(t, model.GenerationJobStatusRunning, claimed.Status)
+		assert.Equal(t, 1, claimed.Attempts)
+
+		progress := []model.GenerationJobQuestion{{Question: "自己PR", Status: model.GenerationJobQuestionSucceeded}}
+		assert.NoError(t, repo.UpdateProgress(ctx, claimed.ID, claimed.Attempts, progress))
+		answers := []model.LLMGeneratedResponse{{Question: "自己PR", Answer: "回答", Status: model.LLMAnswerStatusSucceeded}}
+		assert.NoError(t, repo.Finish(ctx, claimed.ID, claimed.Attempts, model.GenerationJobStatusSucceeded, answers, ""))
+		// 終了済みのジョブは上書きしない
+		assert.ErrorIs(t, repo.Finish(ctx, claimed.ID, claimed.Attempts, model.GenerationJobStatusFailed, nil, "error"), gorm.ErrRecordNotFound)
+
+		res, err := repo.FindByID(ctx, claimed.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.GenerationJobStatusSucceeded, res.Status)
+		assert.Equal(t, progress, res.Progress)
+		assert.Equal(t, answers, res.Answers)
+		assert.NotNil(t, res.FinishedAt)
+	})
+
+	t.Run("正常系:実行待ちのジョブがなくなったらnilを返す", func(t *testing.T) {
+		claimed, err := repo.ClaimNext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, second.ID, claimed.ID)
+
+		claimed, err = repo.ClaimNext(ctx)
+		assert.NoError(t, err)
+		assert.Nil(t, claimed)
+	})
+}
+
+func TestGenerationJobRepository_RequestCancel(t *testing.T) {
+	db, repo, ctx := setupGenerationJobTest(t)
+	defer test.CleanupDB(t, db)
+	running := createGenerationJob(t, repo, ctx)
+	claimed, err := repo.ClaimNext(ctx)
+	assert.NoError(t, err)
+	queued := createGenerationJob(t, repo, ctx)
+
+	t.Run("正常系:実行待ちのジョブはキャンセル済みにする", func(t *testing.T) {
+		res, err := repo.RequestCancel(ctx, queued.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.GenerationJobStatusCanceled, res.Status)
+		assert.True(t, res.CancelRequested)
+		assert.NotNil(t, res.FinishedAt)
+	})
+
+	t.Run("正常系:実行中のジョブはキャンセルの要求をハートビートで伝える", func(t *testing.T) {
+		res, err := repo.RequestCancel(ctx, running.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.GenerationJobStatusRunning, res.Status)
+
+		cancelRequested, err := repo.Heartbeat(ctx, running.ID, claimed.Attempts)
+		assert.NoError(t, err)
+		assert.True(t, cancelRequested)
+	})
+}
+
+func TestGenerationJobRepository_RequeueStale(t *testing.T) {
+	db, repo, ctx := setupGenerationJobTest(t)
+	defer test.CleanupDB(t, db)
+	job := createGenerationJob(t, repo, ctx)
+	_, err := repo.ClaimNext(ctx)
+	assert.NoError(t, err)
+
+	t.Run("正常系:生存の通知が途絶えたジョブを実行待ちに戻す", func(t *testing.T) {
+		requeued, err := repo.RequeueStale(ctx, time.Now().Add(time.Minute), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), requeued)
+
+		res, err := repo.FindByID(ctx, job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.GenerationJobStatusQueued, res.Status)
+
+		// 実行待ちに戻された後は、元のワーカーの生存通知と進捗を受け付けない
+		_, err = repo.Heartbeat(ctx, job.ID, 1)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repo.UpdateProgress(ctx, job.ID, 1, []model.GenerationJobQuestion{}), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("正常系:実行回数の上限に達したジョブは失敗にする", func(t *testing.T) {
+		_, err := repo.ClaimNext(ctx)
+		assert.NoError(t, err)
+
+		requeued, err := repo.RequeueStale(ctx, time.Now().Add(time.Minute), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), requeued)
+
+		res, err := repo.FindByID(ctx, job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.GenerationJobStatusFailed, res.Status)
+		assert.NotEmpty(t, res.Error)
+	})
+}
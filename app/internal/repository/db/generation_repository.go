@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"es-api/app/infrastructure/db"
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+)
+
+type GenerationRepository interface {
+	Create(ctx context.Context, generation *model.Generations) error
+	ListByUserID(ctx context.Context, limit int, offset int) ([]model.Generations, error)
+	FindByID(ctx context.Context, id string) (*model.Generations, error)
+}
+
+type generationRepository struct {
+	dbManager db.DBConnectionManager
+	defaultDB *gorm.DB
+}
+
+func NewGenerationRepository(defaultDB *gorm.DB) GenerationRepository {
+	return &generationRepository{
+		defaultDB: defaultDB,
+	}
+}
+
+func NewGenerationRepositoryWithDBManager(dbManager db.DBConnectionManager) GenerationRepository {
+	return &generationRepository{
+		dbManager: dbManager,
+		defaultDB: dbManager.GetConnection("clerk"),
+	}
+}
+
+func (r *generationRepository) conn(ctx context.Context) *gorm.DB {
+	idp := ctx.Value(contextKey.IDPKey).(string)
+	if r.dbManager != nil && idp != "" {
+		return r.dbManager.GetConnection(idp)
+	}
+	return r.defaultDB
+}
+
+// Create - 生成結果を保存。UserIDはコンテキストのユーザーで上書きする
+func (r *generationRepository) Create(ctx context.Context, generation *model.Generations) error {
+	generation.UserID = ctx.Value(contextKey.UserIDKey).(string)
+	return r.conn(ctx).Create(generation).Error
+}
+
+// ListByUserID - ユーザーの生成履歴を新しい順に取得。一覧ではHTMLを返さない
+func (r *generationRepository) ListByUserID(ctx context.Context, limit int, offset int) ([]model.Generations, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var generations []model.Generations
+	result := r.conn(ctx).
+		Select("id", "user_id", "company_id", "company_name", "model", "questions", "answers", "created_at", "updated_at").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&generations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return generations, nil
+}
+
+// FindByID - ユーザーの生成履歴をIDで取得。他のユーザーの履歴は見つからない扱いにする
+func (r *generationRepository) FindByID(ctx context.Context, id string) (*model.Generations, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var generation model.Generations
+	result := r.conn(ctx).Where("id = ? AND user_id = ?", id, userID).First(&generation)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &generation, nil
+}
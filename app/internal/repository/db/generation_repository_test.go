@@ -0,0 +1,104 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+	"es-api/app/test"
+	"es-api/app/test/factory"
+)
+
+func TestGenerationRepository_Create(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewGenerationRepository(db)
+
+	t.Run("正常系:生成履歴を作成する", func(t *testing.T) {
+		dummyUser := factory.CreateUser1(t, db)
+		generation := &model.Generations{
+			CompanyID:   "1234567890123",
+			CompanyName: "テスト株式会社",
+			Model:       string(model.GeminiFlashLite),
+			HTML:        "<textarea></textarea>",
+			Questions:   []string{"自己PR"},
+			Answers: []model.LLMGeneratedResponse{
+				{Question: "自己PR", Answer: "回答", Status: model.LLMAnswerStatusSucceeded},
+			},
+		}
+
+		ctx := test.SetupContextContext("test-user-id")
+		ctx = context.WithValue(ctx, contextKey.UserIDKey, dummyUser.ID)
+		err := repo.Create(ctx, generation)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, generation.ID)
+		assert.Equal(t, dummyUser.ID, generation.UserID)
+	})
+}
+
+func TestGenerationRepository_ListByUserID(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewGenerationRepository(db)
+	dummyUser := factory.CreateUser1(t, db)
+	_ = factory.CreateUser2(t, db)
+	dummyGeneration := factory.CreateGeneration1(t, db)
+
+	t.Run("正常系:自分の履歴のみをHTMLなしで取得する", func(t *testing.T) {
+		ctx := test.SetupContextContext("test-user-id")
+		ctx = context.WithValue(ctx, contextKey.UserIDKey, dummyUser.ID)
+		res, err := repo.ListByUserID(ctx, 20, 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, dummyGeneration.ID, res[0].ID)
+		assert.Equal(t, dummyGeneration.Answers, res[0].Answers)
+		assert.Empty(t, res[0].HTML)
+	})
+
+	t.Run("正常系:他のユーザーの履歴は取得しない", func(t *testing.T) {
+		ctx := test.SetupContextContext("test-user-id")
+		ctx = context.WithValue(ctx, contextKey.UserIDKey, factory.DummyUserID2)
+		res, err := repo.ListByUserID(ctx, 20, 0)
+
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestGenerationRepository_FindByID(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewGenerationRepository(db)
+	dummyUser := factory.CreateUser1(t, db)
+	_ = factory.CreateUser2(t, db)
+	dummyGeneration := factory.CreateGeneration1(t, db)
+
+	t.Run("正常系:履歴が存在する場合", func(t *testing.T) {
+		ctx := test.SetupContextContext("test-user-id")
+		ctx = context.WithValue(ctx, contextKey.UserIDKey, dummyUser.ID)
+		res, err := repo.FindByID(ctx, dummyGeneration.ID)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, dummyGeneration.HTML, res.HTML)
+		assert.Equal(t, dummyGeneration.Questions, res.Questions)
+	})
+
+	t.Run("異常系:他のユーザーの履歴の場合", func(t *testing.T) {
+		ctx := test.SetupContextContext("test-user-id")
+		ctx = context.WithValue(ctx, contextKey.UserIDKey, factory.DummyUserID2)
+		res, err := repo.FindByID(ctx, dummyGeneration.ID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"es-api/app/infrastructure/db"
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+)
+
+type PromptTemplateRepository interface {
+	ListByName(ctx context.Context, name model.PromptTemplateName) ([]model.PromptTemplates, error)
+	FindActive(ctx context.Context, name model.PromptTemplateName) (*model.PromptTemplates, error)
+	Create(ctx context.Context, template *model.PromptTemplates) error
+	Activate(ctx context.Context, name model.PromptTemplateName, version int) (*model.PromptTemplates, error)
+}
+
+type promptTemplateRepository struct {
+	dbManager db.DBConnectionManager
+	defaultDB *gorm.DB
+}
+
+func NewPromptTemplateRepository(defaultDB *gorm.DB) PromptTemplateRepository {
+	return &promptTemplateRepository{
+		defaultDB: defaultDB,
+	}
+}
+
+func NewPromptTemplateRepositoryWithDBManager(dbManager db.DBConnectionManager) PromptTemplateRepository {
+	return &promptTemplateRepository{
+		dbManager: dbManager,
+		defaultDB: dbManager.GetConnection("clerk"),
+	}
+}
+
+func (r *promptTemplateRepository) conn(ctx context.Context) *gorm.DB {
+	idp := ctx.Value(contextKey.IDPKey).(string)
+	if r.dbManager != nil && idp != "" {
+		return r.dbManager.GetConnection(idp)
+	}
+	return r.defaultDB
+}
+
+// ListByName - 種類ごとの全てのバージョンを新しい順に取得
+func (r *promptTemplateRepository) ListByName(ctx context.Context, name model.PromptTemplateName) ([]model.PromptTemplates, error) {
+	var templates []model.PromptTemplates
+	if err := r.conn(ctx).Where("name = ?", name).Order("version DESC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// FindActive - 有効なバージョンを取得。有効なバージョンがない場合はnilを返す
+func (r *promptTemplateRepository) FindActive(ctx context.Context, name model.PromptTemplateName) (*model.PromptTemplates, error) {
+	var template model.PromptTemplates
+	result := r.conn(ctx).Where("name = ? AND active", name).Limit(1).Find(&template)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &template, nil
+}
+
+// Create - 新しいバージョンを無効な状態で保存。Versionはその種類の最新のバージョンの次の番号にする
+// CreatedByはコンテキストのユーザーで上書きする
+func (r *promptTemplateRepository) Create(ctx context.Context, template *model.PromptTemplates) error {
+	template.CreatedBy = ctx.Value(contextKey.UserIDKey).(string)
+	template.Active = false
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		// 同時に作成しても番号が重複しないよう、種類ごとにロックを取る
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "prompt_templates:"+string(template.Name)).Error; err != nil {
+			return err
+		}
+		var latest int
+		if err := tx.Model(&model.PromptTemplates{}).Where("name = ?", template.Name).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		template.Version = latest + 1
+		return tx.Create(template).Error
+	})
+}
+
+// Activate - 指定したバージョンを有効にし、同じ種類の他のバージョンを無効にする。バージョンが存在しない場合はnilを返す
+func (r *promptTemplateRepository) Activate(ctx context.Context, name model.PromptTemplateName, version int) (*model.PromptTemplates, error) {
+	var activated *model.PromptTemplates
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		var template model.PromptTemplates
+		result := tx.Where("name = ? AND version = ?", name, version).Limit(1).Find(&template)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// 有効なバージョンは種類ごとに1つだけなので、先に無効にしてから有効にする
+		if err := tx.Model(&model.PromptTemplates{}).Where("name = ? AND active", name).Update("active", false).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		template.Active = true
+		template.ActivatedAt = &now
+		if err := tx.Model(&template).Select("active", "activated_at").Updates(&template).Error; err != nil {
+			return err
+		}
+		activated = &template
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return activated, nil
+}
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+	"es-api/app/test"
+)
+
+func TestPromptTemplateRepository_Activate(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewPromptTemplateRepository(db)
+	ctx := test.SetupContextContext("admin-user-id")
+
+	t.Run("正常系:作成したバージョンは連番になり、有効にするまで使われない", func(t *testing.T) {
+		first := &model.PromptTemplates{Name: model.PromptTemplateESGeneration, Content: "「{{.Question}}」"}
+		second := &model.PromptTemplates{Name: model.PromptTemplateESGeneration, Content: "質問:{{.Question}}"}
+		assert.NoError(t, repo.Create(ctx, first))
+		assert.NoError(t, repo.Create(ctx, second))
+		assert.Equal(t, 1, first.Version)
+		assert.Equal(t, 2, second.Version)
+		assert.Equal(t, "admin-user-id", second.CreatedBy)
+
+		active, err := repo.FindActive(ctx, model.PromptTemplateESGeneration)
+		assert.NoError(t, err)
+		assert.Nil(t, active)
+	})
+
+	t.Run("正常系:有効にしたバージョンだけが有効になる", func(t *testing.T) {
+		_, err := repo.Activate(ctx, model.PromptTemplateESGeneration, 1)
+		assert.NoError(t, err)
+		activated, err := repo.Activate(ctx, model.PromptTemplateESGeneration, 2)
+		assert.NoError(t, err)
+		if assert.NotNil(t, activated) {
+			assert.True(t, activated.Active)
+			assert.NotNil(t, activated.ActivatedAt)
+		}
+
+		active, err := repo.FindActive(ctx, model.PromptTemplateESGeneration)
+		assert.NoError(t, err)
+		if assert.NotNil(t, active) {
+			assert.Equal(t, 2, active.Version)
+		}
+
+		versions, err := repo.ListByName(ctx, model.PromptTemplateESGeneration)
+		assert.NoError(t, err)
+		if assert.Len(t, versions, 2) {
+			assert.Equal(t, 2, versions[0].Version)
+			assert.False(t, versions[1].Active)
+		}
+	})
+
+	t.Run("異常系:存在しないバージョンの場合はnilを返す", func(t *testing.T) {
+		activated, err := repo.Activate(ctx, model.PromptTemplateESGeneration, 99)
+
+		assert.NoError(t, err)
+		assert.Nil(t, activated)
+	})
+}
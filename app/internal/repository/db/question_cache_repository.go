@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"es-api/app/infrastructure/db"
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QuestionCacheRepository interface {
+	FindByHTMLHash(ctx context.Context, htmlHash string) (*model.QuestionCaches, error)
+	FindLatestByPageURL(ctx context.Context, pageURL string) (*model.QuestionCaches, error)
+	Upsert(ctx context.Context, cache *model.QuestionCaches) error
+}
+
+type questionCacheRepository struct {
+	dbManager db.DBConnectionManager
+	defaultDB *gorm.DB
+}
+
+func NewQuestionCacheRepository(defaultDB *gorm.DB) QuestionCacheRepository {
+	return &questionCacheRepository{
+		defaultDB: defaultDB,
+	}
+}
+
+func NewQuestionCacheRepositoryWithDBManager(dbManager db.DBConnectionManager) QuestionCacheRepository {
+	return &questionCacheRepository{
+		dbManager: dbManager,
+		defaultDB: dbManager.GetConnection("clerk"),
+	}
+}
+
+func (r *questionCacheRepository) conn(ctx context.Context) *gorm.DB {
+	idp := ctx.Value(contextKey.IDPKey).(string)
+	if r.dbManager != nil && idp != "" {
+		return r.dbManager.GetConnection(idp)
+	}
+	return r.defaultDB
+}
+
+// FindByHTMLHash - ユーザーの保存済みの設問をHTMLのハッシュで検索。存在しない場合はnilを返す
+func (r *questionCacheRepository) FindByHTMLHash(ctx context.Context, htmlHash string) (*model.QuestionCaches, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var cache model.QuestionCaches
+	result := r.conn(ctx).Where("user_id = ? AND html_hash = ?", userID, htmlHash).Find(&cache)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &cache, nil
+}
+
+// FindLatestByPageURL - ユーザーの保存済みの設問のうち、URLが一致する最も新しいものを検索。存在しない場合はnilを返す
+func (r *questionCacheRepository) FindLatestByPageURL(ctx context.Context, pageURL string) (*model.QuestionCaches, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var cache model.QuestionCaches
+	result := r.conn(ctx).Where("user_id = ? AND page_url = ?", userID, pageURL).Order("updated_at DESC").Limit(1).Find(&cache)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &cache, nil
+}
+
+// Upsert - 設問を保存。UserIDはコンテキストのユーザーで上書きし、同じHTMLの設問がある場合は内容を更新する
+func (r *questionCacheRepository) Upsert(ctx context.Context, cache *model.QuestionCaches) error {
+	cache.UserID = ctx.Value(contextKey.UserIDKey).(string)
+	return r.conn(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "html_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"page_url", "questions", "prompt_version_id", "updated_at"}),
+	}).Create(cache).Error
+}
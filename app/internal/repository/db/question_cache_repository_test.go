@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+	"es-api/app/test"
+	"es-api/app/test/factory"
+)
+
+func TestQuestionCacheRepository_Upsert(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewQuestionCacheRepository(db)
+
+	t.Run("正常系:同じHTMLの設問は上書きし、URLでは最新の設問を返す", func(t *testing.T) {
+		dummyUser := factory.CreateUser1(t, db)
+		ctx := test.SetupContextContext("test-user-id")
+		ctx = context.WithValue(ctx, contextKey.UserIDKey, dummyUser.ID)
+
+		assert.NoError(t, repo.Upsert(ctx, &model.QuestionCaches{
+			HTMLHash:  "hash-1",
+			PageURL:   "https://example.com/entry",
+			Questions: []model.ExtractedQuestion{{Question: "志望動機", FieldType: model.QuestionFieldTypeTextarea}},
+		}))
+		assert.NoError(t, repo.Upsert(ctx, &model.QuestionCaches{
+			HTMLHash:  "hash-1",
+			PageURL:   "https://example.com/entry",
+			Questions: []model.ExtractedQuestion{{Question: "志望動機(400字以内)", CharLimit: 400, FieldType: model.QuestionFieldTypeTextarea}},
+		}))
+
+		found, err := repo.FindByHTMLHash(ctx, "hash-1")
+		assert.NoError(t, err)
+		if assert.NotNil(t, found) && assert.Len(t, found.Questions, 1) {
+			assert.Equal(t, 400, found.Questions[0].CharLimit)
+		}
+
+		latest, err := repo.FindLatestByPageURL(ctx, "https://example.com/entry")
+		assert.NoError(t, err)
+		if assert.NotNil(t, latest) {
+			assert.Equal(t, "hash-1", latest.HTMLHash)
+		}
+	})
+
+	t.Run("異常系:他のユーザーの設問は見つからない", func(t *testing.T) {
+		ctx := test.SetupContextContext("other-user-id")
+		found, err := repo.FindByHTMLHash(ctx, "hash-1")
+
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	})
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"es-api/app/infrastructure/db"
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+)
+
+type UsageRepository interface {
+	Create(ctx context.Context, usages []model.LLMUsages) error
+	SumByModel(ctx context.Context, since time.Time) ([]model.LLMUsageByModel, error)
+	CountRequests(ctx context.Context, since time.Time) (int64, error)
+}
+
+type usageRepository struct {
+	dbManager db.DBConnectionManager
+	defaultDB *gorm.DB
+}
+
+func NewUsageRepository(defaultDB *gorm.DB) UsageRepository {
+	return &usageRepository{
+		defaultDB: defaultDB,
+	}
+}
+
+func NewUsageRepositoryWithDBManager(dbManager db.DBConnectionManager) UsageRepository {
+	return &usageRepository{
+		dbManager: dbManager,
+		defaultDB: dbManager.GetConnection("clerk"),
+	}
+}
+
+func (r *usageRepository) conn(ctx context.Context) *gorm.DB {
+	idp := ctx.Value(contextKey.IDPKey).(string)
+	if r.dbManager != nil && idp != "" {
+		return r.dbManager.GetConnection(idp)
+	}
+	return r.defaultDB
+}
+
+// Create - 使用量をまとめて保存。UserIDはコンテキストのユーザーで上書きする
+func (r *usageRepository) Create(ctx context.Context, usages []model.LLMUsages) error {
+	if len(usages) == 0 {
+		return nil
+	}
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+	for i := range usages {
+		usages[i].UserID = userID
+	}
+	return r.conn(ctx).Create(&usages).Error
+}
+
+// SumByModel - since以降のユーザーの使用量をモデルごとに集計
+func (r *usageRepository) SumByModel(ctx context.Context, since time.Time) ([]model.LLMUsageByModel, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var results []model.LLMUsageByModel
+	err := r.conn(ctx).Model(&model.LLMUsages{}).
+		Select("model, SUM(input_tokens) AS input_tokens, SUM(output_tokens) AS output_tokens, COUNT(*) AS calls").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Group("model").
+		Order("model").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CountRequests - since以降のユーザーの生成リクエスト数
+func (r *usageRepository) CountRequests(ctx context.Context, since time.Time) (int64, error) {
+	userID := ctx.Value(contextKey.UserIDKey).(string)
+
+	var count int64
+	err := r.conn(ctx).Model(&model.LLMUsages{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Distinct("request_id").
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,61 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+	"es-api/app/test"
+	"es-api/app/test/factory"
+)
+
+func TestUsageRepository_Summarize(t *testing.T) {
+	db := test.SetupTestDB(t, "../../../../.env")
+	defer test.CleanupDB(t, db)
+
+	repo := repository.NewUsageRepository(db)
+	dummyUser := factory.CreateUser1(t, db)
+
+	ctx := test.SetupContextContext("test-user-id")
+	ctx = context.WithValue(ctx, contextKey.UserIDKey, dummyUser.ID)
+
+	requestID1 := "323e4567-e89b-12d3-a456-426614174000"
+	requestID2 := "323e4567-e89b-12d3-a456-426614174001"
+	err := repo.Create(ctx, []model.LLMUsages{
+		{RequestID: requestID1, Model: "gemini-2.0-flash-lite", Purpose: model.LLMUsagePurposeExtraction, InputTokens: 100, OutputTokens: 10},
+		{RequestID: requestID1, Model: "gemini-2.0-flash", Purpose: model.LLMUsagePurposeGeneration, InputTokens: 50, OutputTokens: 20},
+		{RequestID: requestID2, Model: "gemini-2.0-flash", Purpose: model.LLMUsagePurposeGeneration, InputTokens: 50, OutputTokens: 30},
+	})
+	assert.NoError(t, err)
+
+	since := time.Now().Add(-time.Hour)
+
+	t.Run("正常系:モデルごとに集計する", func(t *testing.T) {
+		res, err := repo.SumByModel(ctx, since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.LLMUsageByModel{
+			{Model: "gemini-2.0-flash", InputTokens: 100, OutputTokens: 50, Calls: 2},
+			{Model: "gemini-2.0-flash-lite", InputTokens: 100, OutputTokens: 10, Calls: 1},
+		}, res)
+	})
+
+	t.Run("正常系:リクエスト数を数える", func(t *testing.T) {
+		count, err := repo.CountRequests(ctx, since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("正常系:期間外の使用量は含めない", func(t *testing.T) {
+		count, err := repo.CountRequests(ctx, time.Now().Add(time.Hour))
+
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
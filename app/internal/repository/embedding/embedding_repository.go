@@ -0,0 +1,30 @@
+package embedding
+
+import (
+	"context"
+	"math"
+)
+
+// EmbeddingRepository - テキストをベクトルに変換する
+type EmbeddingRepository interface {
+	// Embed はtextsと同じ順番でベクトルを返す
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// CosineSimilarity - 2つのベクトルのコサイン類似度。どちらかがゼロベクトルの場合は0を返す
+func CosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	for _, v := range a {
+		normA += float64(v) * float64(v)
+	}
+	for _, v := range b {
+		normB += float64(v) * float64(v)
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
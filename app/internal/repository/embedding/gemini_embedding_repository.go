@@ -0,0 +1,37 @@
+package embedding
+
+import (
+	"context"
+
+	geminiRepo "es-api/app/internal/repository/gemini"
+)
+
+const defaultGeminiEmbeddingModel = "text-embedding-004"
+
+// geminiEmbeddingRepository - 生成と同じGeminiRepositoryを使い、クライアントと再試行・同時実行数の制限を共有する
+type geminiEmbeddingRepository struct {
+	gemini geminiRepo.GeminiRepository
+	model  string
+}
+
+// NewGeminiEmbeddingRepository - Geminiの埋め込みモデルを使う。modelが空の場合はtext-embedding-004
+func NewGeminiEmbeddingRepository(gemini geminiRepo.GeminiRepository, model string) EmbeddingRepository {
+	if model == "" {
+		model = defaultGeminiEmbeddingModel
+	}
+	return &geminiEmbeddingRepository{gemini: gemini, model: model}
+}
+
+// Embed - 1回のリクエストの上限を超えないように、MaxEmbedBatchSize件ずつに分けて埋め込む
+func (r *geminiEmbeddingRepository) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += geminiRepo.MaxEmbedBatchSize {
+		end := min(start+geminiRepo.MaxEmbedBatchSize, len(texts))
+		batch, err := r.gemini.EmbedContents(ctx, r.model, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
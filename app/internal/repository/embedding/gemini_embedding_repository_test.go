@@ -0,0 +1,73 @@
+package embedding_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/repository/embedding"
+	mock "es-api/app/test/mock/repository"
+)
+
+func TestGeminiEmbeddingRepository_Embed(t *testing.T) {
+	texts := func(n int) []string {
+		result := make([]string, n)
+		for i := range result {
+			result[i] = fmt.Sprintf("経験%d", i)
+		}
+		return result
+	}
+	vectors := func(texts []string) [][]float32 {
+		result := make([][]float32, len(texts))
+		for i := range texts {
+			result[i] = []float32{float32(len(texts)), float32(i)}
+		}
+		return result
+	}
+
+	t.Run("正常系:100件を超える場合は100件ずつに分けて埋め込み、元の順番で返す", func(t *testing.T) {
+		mockGemini := new(mock.GeminiRepositoryMock)
+		input := texts(250)
+		mockGemini.On("EmbedContents", testifymock.Anything, "text-embedding-004", input[:100]).Return(vectors(input[:100]), nil)
+		mockGemini.On("EmbedContents", testifymock.Anything, "text-embedding-004", input[100:200]).Return(vectors(input[100:200]), nil)
+		mockGemini.On("EmbedContents", testifymock.Anything, "text-embedding-004", input[200:]).Return(vectors(input[200:]), nil)
+
+		repo := embedding.NewGeminiEmbeddingRepository(mockGemini, "")
+		res, err := repo.Embed(context.Background(), input)
+
+		assert.NoError(t, err)
+		if assert.Len(t, res, 250) {
+			assert.Equal(t, []float32{100, 0}, res[0])
+			assert.Equal(t, []float32{100, 99}, res[99])
+			assert.Equal(t, []float32{50, 49}, res[249])
+		}
+		mockGemini.AssertNumberOfCalls(t, "EmbedContents", 3)
+	})
+
+	t.Run("正常系:テキストがない場合はGeminiを呼び出さない", func(t *testing.T) {
+		mockGemini := new(mock.GeminiRepositoryMock)
+
+		repo := embedding.NewGeminiEmbeddingRepository(mockGemini, "")
+		res, err := repo.Embed(context.Background(), nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+		mockGemini.AssertNotCalled(t, "EmbedContents", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:途中の埋め込みに失敗した場合はエラーを返す", func(t *testing.T) {
+		mockGemini := new(mock.GeminiRepositoryMock)
+		input := texts(150)
+		mockGemini.On("EmbedContents", testifymock.Anything, "text-embedding-004", input[:100]).Return(vectors(input[:100]), nil)
+		mockGemini.On("EmbedContents", testifymock.Anything, "text-embedding-004", input[100:]).Return(nil, errors.New("quota exceeded"))
+
+		repo := embedding.NewGeminiEmbeddingRepository(mockGemini, "")
+		_, err := repo.Embed(context.Background(), input)
+
+		assert.Error(t, err)
+	})
+}
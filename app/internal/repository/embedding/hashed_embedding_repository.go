@@ -0,0 +1,69 @@
+package embedding
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const defaultHashedDimensions = 512
+
+type hashedEmbeddingRepository struct {
+	dimensions int
+}
+
+// NewHashedEmbeddingRepository - 文字bigramのbag-of-wordsをハッシュで固定長に畳み込むローカルの埋め込み
+// 外部APIを呼ばず、同じ入力には常に同じベクトルを返す。dimensionsが0以下の場合は512
+func NewHashedEmbeddingRepository(dimensions int) EmbeddingRepository {
+	if dimensions <= 0 {
+		dimensions = defaultHashedDimensions
+	}
+	return &hashedEmbeddingRepository{dimensions: dimensions}
+}
+
+func (r *hashedEmbeddingRepository) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = r.embed(text)
+	}
+	return vectors, nil
+}
+
+// embed - 日本語は分かち書きされないため、単語ではなく文字bigramを特徴量にする
+func (r *hashedEmbeddingRepository) embed(text string) []float32 {
+	vector := make([]float32, r.dimensions)
+
+	runes := normalize(text)
+	for i := 0; i+1 < len(runes); i++ {
+		h := fnv.New32a()
+		h.Write([]byte(string(runes[i : i+2])))
+		vector[h.Sum32()%uint32(r.dimensions)]++
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return vector
+}
+
+// normalize - 小文字にして空白・記号を取り除く
+func normalize(text string) []rune {
+	runes := make([]rune, 0, len(text))
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		runes = append(runes, r)
+	}
+	return runes
+}
@@ -0,0 +1,45 @@
+package embedding_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/repository/embedding"
+)
+
+func TestHashedEmbeddingRepository_Embed(t *testing.T) {
+	repo := embedding.NewHashedEmbeddingRepository(0)
+
+	t.Run("正常系:同じ入力には同じベクトルを返す", func(t *testing.T) {
+		first, err := repo.Embed(context.Background(), []string{"学生時代に力を入れたこと"})
+		assert.NoError(t, err)
+		second, err := repo.Embed(context.Background(), []string{"学生時代に力を入れたこと"})
+		assert.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Len(t, first[0], 512)
+		assert.InDelta(t, 1.0, embedding.CosineSimilarity(first[0], second[0]), 1e-6)
+	})
+
+	t.Run("正常系:内容が近いテキストほど類似度が高い", func(t *testing.T) {
+		vectors, err := repo.Embed(context.Background(), []string{
+			"学生時代に力を入れたことを教えてください",
+			"学生時代はサークル活動に力を入れた",
+			"御社を志望する理由は事業の将来性です",
+		})
+		assert.NoError(t, err)
+
+		related := embedding.CosineSimilarity(vectors[0], vectors[1])
+		unrelated := embedding.CosineSimilarity(vectors[0], vectors[2])
+		assert.Greater(t, related, unrelated)
+	})
+
+	t.Run("正常系:記号だけのテキストはゼロベクトルになる", func(t *testing.T) {
+		vectors, err := repo.Embed(context.Background(), []string{"、。!?"})
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0.0, embedding.CosineSimilarity(vectors[0], vectors[0]))
+	})
+}
@@ -3,6 +3,7 @@ package gbiz
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -11,8 +12,12 @@ import (
 	"es-api/app/internal/entity/model"
 )
 
+// ErrCorporationNotFound - 指定した法人番号の法人がgBizINFOに存在しない
+var ErrCorporationNotFound = errors.New("corporation not found")
+
 type GBizInfoRepository interface {
 	SearchCompanies(ctx context.Context, keyword string) ([]model.CompanyBasicInfo, error)
+	GetCompany(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error)
 }
 
 type gbizInfoRepository struct {
@@ -27,19 +32,65 @@ func NewGBizInfoRepository() GBizInfoRepository {
 
 // SearchCompanies - 法人名の検索を行う
 func (r *gbizInfoRepository) SearchCompanies(ctx context.Context, keyword string) ([]model.CompanyBasicInfo, error) {
+	var gbizResponse model.GBizInfoResponse
+	if err := r.get(ctx, fmt.Sprintf("%s?name=%s&exist_flg=true", r.baseURL, url.QueryEscape(keyword)), &gbizResponse); err != nil {
+		return nil, err
+	}
+
+	// 結果の変換
+	companies := make([]model.CompanyBasicInfo, 0, len(gbizResponse.Response))
+	for _, company := range gbizResponse.Response {
+		companies = append(companies, model.CompanyBasicInfo{
+			CompanyID:   company.CorporateNumber,
+			CompanyName: company.Name,
+		})
+	}
+
+	return companies, nil
+}
+
+// GetCompany - 法人番号を指定して法人情報を取得する
+func (r *gbizInfoRepository) GetCompany(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error) {
+	var gbizResponse model.GBizInfoDetailResponse
+	if err := r.get(ctx, fmt.Sprintf("%s/%s", r.baseURL, url.PathEscape(corporateNumber)), &gbizResponse); err != nil {
+		return nil, err
+	}
+	if len(gbizResponse.Response) == 0 {
+		return nil, ErrCorporationNotFound
+	}
+
+	hojin := gbizResponse.Response[0]
+	details := &model.CompanyDetails{
+		CorporateNumber: hojin.CorporateNumber,
+		Name:            hojin.Name,
+		Address:         hojin.Location,
+		Capital:         hojin.CapitalStock,
+		EmployeeCount:   hojin.EmployeeNumber,
+		BusinessSummary: hojin.BusinessSummary,
+		URL:             hojin.CompanyURL,
+	}
+	// 設立年月日は「2000-01-01T00:00:00+09:00」の形式で返るので日付だけを使う。ない場合は設立年を使う
+	switch {
+	case len(hojin.DateOfEstablishment) >= 10:
+		details.FoundedAt = hojin.DateOfEstablishment[:10]
+	case hojin.FoundingYear > 0:
+		details.FoundedAt = fmt.Sprintf("%d", hojin.FoundingYear)
+	}
+
+	return details, nil
+}
+
+// get - gBizINFO APIにGETリクエストを送り、レスポンスをoutにデコードする
+func (r *gbizInfoRepository) get(ctx context.Context, requestURL string, out interface{}) error {
 	apiKey := os.Getenv("GBIZ_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("GBIZ_API_KEY is not set")
+		return fmt.Errorf("GBIZ_API_KEY is not set")
 	}
 
 	// リクエストの構築
-	req, err := http.NewRequest(
-		"GET",
-		fmt.Sprintf("%s?name=%s&exist_flg=true", r.baseURL, url.QueryEscape(keyword)),
-		nil,
-	)
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// APIキーをヘッダーに設定
@@ -49,29 +100,21 @@ func (r *gbizInfoRepository) SearchCompanies(ctx context.Context, keyword string
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// エラーレスポンスの確認
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrCorporationNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned non-200 status code: %d", resp.StatusCode)
+		return fmt.Errorf("API returned non-200 status code: %d", resp.StatusCode)
 	}
 
 	// レスポンスのパース
-	var gbizResponse model.GBizInfoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&gbizResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	// 結果の変換
-	companies := make([]model.CompanyBasicInfo, 0, len(gbizResponse.Response))
-	for _, company := range gbizResponse.Response {
-		companies = append(companies, model.CompanyBasicInfo{
-			CompanyID:   company.CorporateNumber,
-			CompanyName: company.Name,
-		})
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
-
-	return companies, nil
+	return nil
 }
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// ErrCircuitOpen - Geminiの障害中のため、呼び出さずに失敗させた
+var ErrCircuitOpen = errors.New("gemini circuit breaker is open")
+
+// CallGuardConfig - Gemini呼び出しの再試行・サーキットブレーカー・同時実行数の設定
+type CallGuardConfig struct {
+	MaxAttempts      int           // 再試行を含めた最大の呼び出し回数
+	BaseDelay        time.Duration // 1回目の再試行までの待ち時間の上限。再試行のたびに2倍にする
+	MaxDelay         time.Duration // 再試行までの待ち時間の上限
+	MaxConcurrency   int           // 同時に実行する呼び出しの上限
+	BreakerThreshold int           // 連続してこの回数失敗したら、呼び出しを遮断する
+	BreakerCooldown  time.Duration // 遮断してから、試しに1回だけ呼び出すまでの時間
+}
+
+// CallGuardConfigFromEnv - 設定を環境変数から読み込む。未設定や不正な値の場合はデフォルト値を使う
+// GEMINI_MAX_ATTEMPTS(4)、GEMINI_RETRY_BASE_DELAY(500ms)、GEMINI_RETRY_MAX_DELAY(8s)、
+// GEMINI_MAX_CONCURRENCY(8)、GEMINI_BREAKER_THRESHOLD(5)、GEMINI_BREAKER_COOLDOWN(30s)
+func CallGuardConfigFromEnv() CallGuardConfig {
+	return CallGuardConfig{
+		MaxAttempts:      intFromEnv("GEMINI_MAX_ATTEMPTS", 4),
+		BaseDelay:        durationFromEnv("GEMINI_RETRY_BASE_DELAY", 500*time.Millisecond),
+		MaxDelay:         durationFromEnv("GEMINI_RETRY_MAX_DELAY", 8*time.Second),
+		MaxConcurrency:   intFromEnv("GEMINI_MAX_CONCURRENCY", 8),
+		BreakerThreshold: intFromEnv("GEMINI_BREAKER_THRESHOLD", 5),
+		BreakerCooldown:  durationFromEnv("GEMINI_BREAKER_COOLDOWN", 30*time.Second),
+	}
+}
+
+// CallGuard - Geminiの呼び出しに再試行・サーキットブレーカー・同時実行数の制限を適用する
+// 1つのCallGuardを全てのリクエストで共有することで、プロセス全体の同時実行数を制限する
+type CallGuard struct {
+	config CallGuardConfig
+	slots  chan struct{}
+
+	mu          sync.Mutex
+	failures    int       // 連続して失敗した回数
+	openedUntil time.Time // 遮断を続ける期限。ゼロ値の場合は遮断していない
+	probing     bool      // 遮断の期限後に、試しの呼び出しを実行中
+}
+
+func NewCallGuard(config CallGuardConfig) *CallGuard {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	if config.MaxConcurrency < 1 {
+		config.MaxConcurrency = 1
+	}
+	if config.BreakerThreshold < 1 {
+		config.BreakerThreshold = 1
+	}
+	return &CallGuard{
+		config: config,
+		slots:  make(chan struct{}, config.MaxConcurrency),
+	}
+}
+
+// Do - fnを実行する。再試行できるエラーの場合は、指数関数的に伸ばした時間からランダムに選んだ時間だけ待って再試行する
+// 遮断中はfnを呼ばずにErrCircuitOpenを返す。再試行の途中で遮断した場合は、直前のエラーも合わせて返す
+func (g *CallGuard) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if !g.allow() {
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrCircuitOpen, err)
+			}
+			return ErrCircuitOpen
+		}
+
+		err = g.call(ctx, fn)
+		if ctx.Err() != nil {
+			// 呼び出し元のキャンセルや制限時間切れはGeminiの障害ではないので記録しない
+			g.release()
+			return err
+		}
+		g.record(err)
+		if err == nil || !IsRetryable(err) || attempt >= g.config.MaxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(g.backoff(attempt)):
+		}
+	}
+}
+
+// call - 同時実行数の枠が空くのを待ってからfnを実行する。再試行を待つ間は枠を解放する
+func (g *CallGuard) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	select {
+	case g.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-g.slots }()
+	return fn(ctx)
+}
+
+// backoff - attempt回目の失敗の後に待つ時間。0から上限までの間でランダムに選ぶ(full jitter)
+func (g *CallGuard) backoff(attempt int) time.Duration {
+	limit := g.config.BaseDelay << (attempt - 1)
+	if limit > g.config.MaxDelay || limit <= 0 {
+		limit = g.config.MaxDelay
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit)
+}
+
+// allow - 呼び出してよいかどうか。遮断の期限後は、試しの呼び出しを1つだけ通す
+func (g *CallGuard) allow() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.openedUntil.IsZero() {
+		return true
+	}
+	if time.Now().Before(g.openedUntil) || g.probing {
+		return false
+	}
+	g.probing = true
+	return true
+}
+
+// release - 結果を記録せずに、試しの呼び出しを終える
+func (g *CallGuard) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.probing = false
+}
+
+// record - 呼び出しの結果を記録する。Gemini側の障害によるエラーが続いた場合に遮断する
+// リクエストの内容によるエラーはGeminiが応答しているので成功とみなす
+func (g *CallGuard) record(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.probing = false
+	if err == nil || !IsRetryable(err) {
+		g.failures = 0
+		g.openedUntil = time.Time{}
+		return
+	}
+
+	g.failures++
+	if g.failures >= g.config.BreakerThreshold || !g.openedUntil.IsZero() {
+		g.openedUntil = time.Now().Add(g.config.BreakerCooldown)
+	}
+}
+
+// IsRetryable - 時間をおけば成功する可能性のあるエラーかどうか(レート制限、サーバーエラー、通信エラー)
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		switch gerr.Code {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+func intFromEnv(key string, defaultValue int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return defaultValue
+}
+
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return defaultValue
+}
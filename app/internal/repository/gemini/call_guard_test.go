@@ -0,0 +1,185 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+
+	repository "es-api/app/internal/repository/gemini"
+)
+
+func newTestCallGuard() *repository.CallGuard {
+	return repository.NewCallGuard(repository.CallGuardConfig{
+		MaxAttempts:      3,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         5 * time.Millisecond,
+		MaxConcurrency:   2,
+		BreakerThreshold: 3,
+		BreakerCooldown:  50 * time.Millisecond,
+	})
+}
+
+func apiError(code int) error {
+	return &googleapi.Error{Code: code}
+}
+
+func TestCallGuard_Do(t *testing.T) {
+	t.Run("正常系:再試行できるエラーの後に成功した場合", func(t *testing.T) {
+		guard := newTestCallGuard()
+		calls := 0
+		err := guard.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return apiError(http.StatusServiceUnavailable)
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("異常系:リクエストの内容によるエラーは再試行しない", func(t *testing.T) {
+		guard := newTestCallGuard()
+		calls := 0
+		err := guard.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return apiError(http.StatusBadRequest)
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("異常系:最大回数まで失敗した場合は最後のエラーを返す", func(t *testing.T) {
+		guard := repository.NewCallGuard(repository.CallGuardConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, BreakerThreshold: 10})
+		calls := 0
+		err := guard.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return apiError(http.StatusTooManyRequests)
+		})
+
+		var gerr *googleapi.Error
+		assert.ErrorAs(t, err, &gerr)
+		assert.Equal(t, http.StatusTooManyRequests, gerr.Code)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("異常系:キャンセルされた場合は再試行しない", func(t *testing.T) {
+		guard := newTestCallGuard()
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := guard.Do(ctx, func(ctx context.Context) error {
+			calls++
+			cancel()
+			return apiError(http.StatusServiceUnavailable)
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestCallGuard_CircuitBreaker(t *testing.T) {
+	t.Run("正常系:連続して失敗すると遮断し、一定時間後の呼び出しが成功すると再開する", func(t *testing.T) {
+		guard := newTestCallGuard()
+		fail := func(ctx context.Context) error { return apiError(http.StatusServiceUnavailable) }
+
+		// 3回の呼び出しで遮断する
+		assert.Error(t, guard.Do(context.Background(), fail))
+
+		calls := 0
+		err := guard.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+		assert.ErrorIs(t, err, repository.ErrCircuitOpen)
+		assert.Equal(t, 0, calls)
+
+		time.Sleep(60 * time.Millisecond)
+		assert.NoError(t, guard.Do(context.Background(), func(ctx context.Context) error { return nil }))
+		assert.NoError(t, guard.Do(context.Background(), func(ctx context.Context) error { return nil }))
+	})
+
+	t.Run("異常系:遮断後の試しの呼び出しが失敗した場合は遮断を続ける", func(t *testing.T) {
+		guard := newTestCallGuard()
+		fail := func(ctx context.Context) error { return apiError(http.StatusServiceUnavailable) }
+		assert.Error(t, guard.Do(context.Background(), fail))
+
+		time.Sleep(60 * time.Millisecond)
+		err := guard.Do(context.Background(), fail)
+		var gerr *googleapi.Error
+		assert.ErrorAs(t, err, &gerr)
+		assert.ErrorIs(t, err, repository.ErrCircuitOpen)
+
+		assert.ErrorIs(t, guard.Do(context.Background(), func(ctx context.Context) error { return nil }), repository.ErrCircuitOpen)
+	})
+
+	t.Run("正常系:リクエストの内容によるエラーでは遮断しない", func(t *testing.T) {
+		guard := newTestCallGuard()
+		for i := 0; i < 5; i++ {
+			assert.Error(t, guard.Do(context.Background(), func(ctx context.Context) error { return apiError(http.StatusBadRequest) }))
+		}
+
+		assert.NoError(t, guard.Do(context.Background(), func(ctx context.Context) error { return nil }))
+	})
+}
+
+func TestCallGuard_Concurrency(t *testing.T) {
+	t.Run("正常系:同時に実行する呼び出しを上限までに抑える", func(t *testing.T) {
+		guard := newTestCallGuard()
+		var running, maxRunning int32
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = guard.Do(context.Background(), func(ctx context.Context) error {
+					n := atomic.AddInt32(&running, 1)
+					for {
+						m := atomic.LoadInt32(&maxRunning)
+						if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(2), maxRunning)
+	})
+
+	t.Run("異常系:枠が空くのを待つ間にキャンセルされた場合", func(t *testing.T) {
+		guard := repository.NewCallGuard(repository.CallGuardConfig{MaxAttempts: 1, MaxConcurrency: 1, BreakerThreshold: 1})
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go func() {
+			_ = guard.Do(context.Background(), func(ctx context.Context) error {
+				close(started)
+				<-release
+				return nil
+			})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := guard.Do(ctx, func(ctx context.Context) error { return nil })
+		close(release)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, errors.Is(err, repository.ErrCircuitOpen))
+	})
+}
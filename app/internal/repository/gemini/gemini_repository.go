@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"es-api/app/internal/entity/model"
 
@@ -13,25 +14,129 @@ import (
 
 type GeminiRepository interface {
 	GetGeminiRequest(ctx context.Context, input model.GeminiInput) (model.GeminiResponse, error)
+	// EmbedContents はtextsと同じ順番で埋め込みベクトルを返す。textsはMaxEmbedBatchSize件まで
+	EmbedContents(ctx context.Context, embeddingModel string, texts []string) ([][]float32, error)
 }
 
-type geminiRepository struct{}
+// MaxEmbedBatchSize - BatchEmbedContentsの1回のリクエストに含められるテキストの最大数
+const MaxEmbedBatchSize = 100
 
+// geminiRepository - 1つのクライアントを全てのリクエストで使い回す
+// 呼び出しにはCallGuardで再試行・サーキットブレーカー・同時実行数の制限を適用する
+type geminiRepository struct {
+	guard *CallGuard
+
+	mu     sync.Mutex
+	client *genai.Client
+}
+
+// NewGeminiRepository - 設定はCallGuardConfigFromEnvで環境変数から読み込む
+// 同時実行数の制限はこのリポジトリ単位なので、プロセス内で1つだけ作って共有する
 func NewGeminiRepository() GeminiRepository {
-	return &geminiRepository{}
+	return NewGeminiRepositoryWithGuard(NewCallGuard(CallGuardConfigFromEnv()))
+}
+
+func NewGeminiRepositoryWithGuard(guard *CallGuard) GeminiRepository {
+	return &geminiRepository{guard: guard}
+}
+
+// getClient - 最初の呼び出しでクライアントを作成し、以降は同じクライアントを返す
+// クライアントはリクエストより長く使うので、リクエストのコンテキストでは作成しない
+func (r *geminiRepository) getClient() (*genai.Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.client != nil {
+		return r.client, nil
+	}
+	client, err := genai.NewClient(context.Background(), option.WithAPIKey(os.Getenv("GEMINI_API_KEY")))
+	if err != nil {
+		return nil, err
+	}
+	r.client = client
+	return client, nil
 }
 
 func (r *geminiRepository) GetGeminiRequest(ctx context.Context, input model.GeminiInput) (model.GeminiResponse, error) {
-	client, err := genai.NewClient(ctx, option.WithAPIKey(os.Getenv("GEMINI_API_KEY")))
+	client, err := r.getClient()
 	if err != nil {
 		return model.GeminiResponse{}, err
 	}
-	defer client.Close()
 
+	var result model.GeminiResponse
+	err = r.guard.Do(ctx, func(ctx context.Context) error {
+		var callErr error
+		result, callErr = generate(ctx, client, input)
+		return callErr
+	})
+	return result, err
+}
+
+func (r *geminiRepository) EmbedContents(ctx context.Context, embeddingModel string, texts []string) ([][]float32, error) {
+	if len(texts) > MaxEmbedBatchSize {
+		return nil, fmt.Errorf("too many texts to embed in one batch: got %d, max %d", len(texts), MaxEmbedBatchSize)
+	}
+	client, err := r.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var vectors [][]float32
+	err = r.guard.Do(ctx, func(ctx context.Context) error {
+		var callErr error
+		vectors, callErr = embed(ctx, client, embeddingModel, texts)
+		return callErr
+	})
+	return vectors, err
+}
+
+// embed - BatchEmbedContentsを1回呼び出す
+func embed(ctx context.Context, client *genai.Client, embeddingModel string, texts []string) ([][]float32, error) {
+	em := client.EmbeddingModel(embeddingModel)
+	em.TaskType = genai.TaskTypeSemanticSimilarity
+	batch := em.NewBatch()
+	for _, text := range texts {
+		batch.AddContent(genai.Text(text))
+	}
+
+	response, err := em.BatchEmbedContents(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("unexpected number of embeddings: got %d, want %d", len(response.Embeddings), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for i, e := range response.Embeddings {
+		vectors[i] = e.Values
+	}
+	return vectors, nil
+}
+
+// generate - Geminiを1回呼び出す
+func generate(ctx context.Context, client *genai.Client, input model.GeminiInput) (model.GeminiResponse, error) {
 	gemModel := client.GenerativeModel(string(input.Model))
+	if input.ResponseSchema != nil {
+		gemModel.ResponseMIMEType = "application/json"
+		gemModel.ResponseSchema = toGenaiSchema(input.ResponseSchema)
+	}
+	if input.SystemInstruction != "" {
+		gemModel.SystemInstruction = genai.NewUserContent(genai.Text(input.SystemInstruction))
+	}
+	applyGenerationParams(gemModel, input.Params)
 	text := input.Text
 
-	response, err := gemModel.GenerateContent(ctx, genai.Text(text))
+	var response *genai.GenerateContentResponse
+	var err error
+	if len(input.History) > 0 {
+		// 会話の履歴がある場合は、チャットセッションに履歴を積んでから続きを送る
+		session := gemModel.StartChat()
+		session.History = toGenaiContents(input.History)
+		response, err = session.SendMessage(ctx, genai.Text(text))
+	} else {
+		response, err = gemModel.GenerateContent(ctx, genai.Text(text))
+	}
 	if err != nil {
 		return model.GeminiResponse{}, err
 	}
@@ -52,3 +157,95 @@ func (r *geminiRepository) GetGeminiRequest(ctx context.Context, input model.Gem
 	}
 	return result, nil
 }
+
+// applyGenerationParams - 指定された生成パラメータだけをモデルに設定する
+func applyGenerationParams(gemModel *genai.GenerativeModel, params model.LLMGenerationParams) {
+	if params.Temperature != nil {
+		gemModel.SetTemperature(*params.Temperature)
+	}
+	if params.TopP != nil {
+		gemModel.SetTopP(*params.TopP)
+	}
+	if params.MaxOutputTokens != nil {
+		gemModel.SetMaxOutputTokens(*params.MaxOutputTokens)
+	}
+	if len(params.StopSequences) > 0 {
+		gemModel.StopSequences = params.StopSequences
+	}
+	for _, setting := range params.SafetySettings {
+		category, ok := genaiHarmCategories[setting.Category]
+		if !ok {
+			continue
+		}
+		threshold, ok := genaiHarmThresholds[setting.Threshold]
+		if !ok {
+			continue
+		}
+		gemModel.SafetySettings = append(gemModel.SafetySettings, &genai.SafetySetting{Category: category, Threshold: threshold})
+	}
+}
+
+var genaiHarmCategories = map[model.LLMSafetyCategory]genai.HarmCategory{
+	model.LLMSafetyCategoryHarassment:       genai.HarmCategoryHarassment,
+	model.LLMSafetyCategoryHateSpeech:       genai.HarmCategoryHateSpeech,
+	model.LLMSafetyCategorySexuallyExplicit: genai.HarmCategorySexuallyExplicit,
+	model.LLMSafetyCategoryDangerousContent: genai.HarmCategoryDangerousContent,
+}
+
+var genaiHarmThresholds = map[model.LLMSafetyThreshold]genai.HarmBlockThreshold{
+	model.LLMSafetyThresholdBlockNone:           genai.HarmBlockNone,
+	model.LLMSafetyThresholdBlockOnlyHigh:       genai.HarmBlockOnlyHigh,
+	model.LLMSafetyThresholdBlockMediumAndAbove: genai.HarmBlockMediumAndAbove,
+	model.LLMSafetyThresholdBlockLowAndAbove:    genai.HarmBlockLowAndAbove,
+}
+
+// toGenaiContents - 会話の履歴をGeminiのチャットセッションの履歴に変換する
+func toGenaiContents(messages []model.LLMMessage) []*genai.Content {
+	contents := make([]*genai.Content, 0, len(messages))
+	for _, message := range messages {
+		role := "user"
+		if message.Role == model.LLMMessageRoleModel {
+			role = "model"
+		}
+		contents = append(contents, &genai.Content{
+			Role:  role,
+			Parts: []genai.Part{genai.Text(message.Text)},
+		})
+	}
+	return contents
+}
+
+// toGenaiSchema - プロバイダー共通のスキーマをGeminiのスキーマに変換する
+func toGenaiSchema(schema *model.LLMSchema) *genai.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	result := &genai.Schema{
+		Description: schema.Description,
+		Required:    schema.Required,
+		Enum:        schema.Enum,
+		Items:       toGenaiSchema(schema.Items),
+	}
+	switch schema.Type {
+	case model.LLMSchemaTypeObject:
+		result.Type = genai.TypeObject
+	case model.LLMSchemaTypeArray:
+		result.Type = genai.TypeArray
+	case model.LLMSchemaTypeString:
+		result.Type = genai.TypeString
+	case model.LLMSchemaTypeInteger:
+		result.Type = genai.TypeInteger
+	case model.LLMSchemaTypeNumber:
+		result.Type = genai.TypeNumber
+	case model.LLMSchemaTypeBoolean:
+		result.Type = genai.TypeBoolean
+	}
+	if len(schema.Properties) > 0 {
+		result.Properties = make(map[string]*genai.Schema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			result.Properties[name] = toGenaiSchema(property)
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"context"
+	"strings"
+
+	"es-api/app/internal/entity/model"
+	gemini "es-api/app/internal/repository/gemini"
+)
+
+type geminiProvider struct {
+	geminiRepo gemini.GeminiRepository
+}
+
+// NewGeminiProvider - "gemini-"で始まるモデルをGemini APIで処理する
+func NewGeminiProvider(geminiRepo gemini.GeminiRepository) LLMProvider {
+	return &geminiProvider{
+		geminiRepo: geminiRepo,
+	}
+}
+
+func (p *geminiProvider) Name() string {
+	return "gemini"
+}
+
+func (p *geminiProvider) Supports(llmModel model.LLMModel) bool {
+	return strings.HasPrefix(string(llmModel), "gemini-")
+}
+
+func (p *geminiProvider) Generate(ctx context.Context, input model.LLMInput) (model.LLMResponse, error) {
+	resp, err := p.geminiRepo.GetGeminiRequest(ctx, model.GeminiInput{
+		Model:             input.Model,
+		Text:              input.Text,
+		ResponseSchema:    input.ResponseSchema,
+		History:           input.History,
+		SystemInstruction: input.SystemInstruction,
+		Params:            input.Params,
+	})
+	if err != nil {
+		return model.LLMResponse{}, err
+	}
+	return model.LLMResponse{
+		Text:         resp.Text,
+		InputTokens:  resp.InputTokens,
+		OutputTokens: resp.OutputTokens,
+	}, nil
+}
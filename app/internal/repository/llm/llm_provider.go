@@ -0,0 +1,42 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"es-api/app/internal/entity/model"
+)
+
+// LLMProvider - LLMの呼び出し先を抽象化したインターフェース
+type LLMProvider interface {
+	// Name - ログやエラーメッセージ用のプロバイダー名
+	Name() string
+	// Supports - 指定したモデルをこのプロバイダーで扱えるかどうか
+	Supports(llmModel model.LLMModel) bool
+	Generate(ctx context.Context, input model.LLMInput) (model.LLMResponse, error)
+}
+
+// LLMProviderRegistry - モデル名から利用するプロバイダーを選択する
+type LLMProviderRegistry interface {
+	Resolve(llmModel model.LLMModel) (LLMProvider, error)
+}
+
+type llmProviderRegistry struct {
+	providers []LLMProvider
+}
+
+// NewLLMProviderRegistry - providersは先に渡したものが優先される
+func NewLLMProviderRegistry(providers ...LLMProvider) LLMProviderRegistry {
+	return &llmProviderRegistry{
+		providers: providers,
+	}
+}
+
+func (r *llmProviderRegistry) Resolve(llmModel model.LLMModel) (LLMProvider, error) {
+	for _, provider := range r.providers {
+		if provider.Supports(llmModel) {
+			return provider, nil
+		}
+	}
+	return nil, fmt.Errorf("モデル「%s」に対応するLLMプロバイダーがありません", llmModel)
+}
@@ -0,0 +1,41 @@
+package llm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/repository/llm"
+	"es-api/app/internal/repository/openai"
+	mock "es-api/app/test/mock/repository"
+)
+
+func TestLLMProviderRegistry_Resolve(t *testing.T) {
+	registry := llm.NewLLMProviderRegistry(
+		llm.NewGeminiProvider(new(mock.GeminiRepositoryMock)),
+		llm.NewOpenAICompatibleProvider(openai.NewOpenAICompatibleRepository("http://localhost:11434/v1", ""), []string{"llama3", " qwen2.5 ", ""}),
+	)
+
+	t.Run("正常系:Geminiのモデル", func(t *testing.T) {
+		provider, err := registry.Resolve(model.GeminiFlash)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "gemini", provider.Name())
+	})
+
+	t.Run("正常系:OpenAI互換APIのモデル", func(t *testing.T) {
+		provider, err := registry.Resolve("qwen2.5")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "openai-compatible", provider.Name())
+	})
+
+	t.Run("異常系:対応するプロバイダーがないモデル", func(t *testing.T) {
+		_, err := registry.Resolve("unknown-model")
+		assert.Error(t, err)
+
+		_, err = registry.Resolve("")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+	"strings"
+
+	"es-api/app/internal/entity/model"
+	openai "es-api/app/internal/repository/openai"
+)
+
+type openAICompatibleProvider struct {
+	openAIRepo openai.OpenAICompatibleRepository
+	models     map[model.LLMModel]bool
+}
+
+// NewOpenAICompatibleProvider - modelsに列挙したモデルをOpenAI互換APIで処理する
+func NewOpenAICompatibleProvider(openAIRepo openai.OpenAICompatibleRepository, models []string) LLMProvider {
+	supported := make(map[model.LLMModel]bool, len(models))
+	for _, m := range models {
+		if m = strings.TrimSpace(m); m != "" {
+			supported[model.LLMModel(m)] = true
+		}
+	}
+	return &openAICompatibleProvider{
+		openAIRepo: openAIRepo,
+		models:     supported,
+	}
+}
+
+func (p *openAICompatibleProvider) Name() string {
+	return "openai-compatible"
+}
+
+func (p *openAICompatibleProvider) Supports(llmModel model.LLMModel) bool {
+	return p.models[llmModel]
+}
+
+func (p *openAICompatibleProvider) Generate(ctx context.Context, input model.LLMInput) (model.LLMResponse, error) {
+	messages := make([]model.OpenAIChatMessage, 0, len(input.History)+2)
+	if input.SystemInstruction != "" {
+		messages = append(messages, model.OpenAIChatMessage{Role: "system", Content: input.SystemInstruction})
+	}
+	for _, message := range input.History {
+		role := "user"
+		if message.Role == model.LLMMessageRoleModel {
+			role = "assistant"
+		}
+		messages = append(messages, model.OpenAIChatMessage{Role: role, Content: message.Text})
+	}
+	messages = append(messages, model.OpenAIChatMessage{Role: "user", Content: input.Text})
+
+	// 安全性フィルタの設定はOpenAI互換APIにないので送らない
+	req := model.OpenAIChatRequest{
+		Model:       string(input.Model),
+		Messages:    messages,
+		Temperature: input.Params.Temperature,
+		TopP:        input.Params.TopP,
+		MaxTokens:   input.Params.MaxOutputTokens,
+		Stop:        input.Params.StopSequences,
+	}
+	if input.ResponseSchema != nil {
+		req.ResponseFormat = &model.OpenAIResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &model.OpenAIJSONSchema{
+				Name:   "response",
+				Schema: input.ResponseSchema,
+			},
+		}
+	}
+	resp, err := p.openAIRepo.ChatCompletion(ctx, req)
+	if err != nil {
+		return model.LLMResponse{}, err
+	}
+	return model.LLMResponse{
+		Text:         resp.Choices[0].Message.Content,
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"es-api/app/internal/entity/model"
+)
+
+// OpenAICompatibleRepository - OpenAI互換のChat Completions API(OpenAI、llama.cpp、Ollamaなど)のクライアント
+type OpenAICompatibleRepository interface {
+	ChatCompletion(ctx context.Context, req model.OpenAIChatRequest) (model.OpenAIChatResponse, error)
+}
+
+type openAICompatibleRepository struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewOpenAICompatibleRepository - baseURLは /chat/completions の手前まで(例: http://localhost:11434/v1)
+// ローカルサーバーなど認証が不要な場合、apiKeyは空でよい
+func NewOpenAICompatibleRepository(baseURL string, apiKey string) OpenAICompatibleRepository {
+	return &openAICompatibleRepository{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+func (r *openAICompatibleRepository) ChatCompletion(ctx context.Context, chatReq model.OpenAIChatRequest) (model.OpenAIChatResponse, error) {
+	jsonData, err := json.Marshal(chatReq)
+	if err != nil {
+		return model.OpenAIChatResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", r.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return model.OpenAIChatResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return model.OpenAIChatResponse{}, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return model.OpenAIChatResponse{}, fmt.Errorf("API error: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result model.OpenAIChatResponse
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return model.OpenAIChatResponse{}, fmt.Errorf("JSON解析エラー: %v - レスポンス: %s", err, string(bodyBytes))
+	}
+	if len(result.Choices) == 0 {
+		return model.OpenAIChatResponse{}, fmt.Errorf("no response generated")
+	}
+
+	return result, nil
+}
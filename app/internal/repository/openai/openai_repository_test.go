@@ -0,0 +1,79 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/repository/openai"
+	"es-api/app/test"
+)
+
+func TestOpenAICompatibleRepository_ChatCompletion(t *testing.T) {
+	t.Run("正常系:ローカルサーバーの応答を返す", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+			assert.Empty(t, r.Header.Get("Authorization"))
+
+			var req model.OpenAIChatRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "llama3", req.Model)
+			assert.Equal(t, "こんにちは", req.Messages[0].Content)
+
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"回答"}}],"usage":{"prompt_tokens":3,"completion_tokens":5}}`))
+		}))
+		defer server.Close()
+
+		repo := openai.NewOpenAICompatibleRepository(server.URL+"/v1/", "")
+		res, err := repo.ChatCompletion(test.SetupContextContext(""), model.OpenAIChatRequest{
+			Model:    "llama3",
+			Messages: []model.OpenAIChatMessage{{Role: "user", Content: "こんにちは"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "回答", res.Choices[0].Message.Content)
+		assert.Equal(t, int32(3), res.Usage.PromptTokens)
+		assert.Equal(t, int32(5), res.Usage.CompletionTokens)
+	})
+
+	t.Run("正常系:APIキーがある場合はBearerトークンを送る", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer dummy-api-key", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"回答"}}]}`))
+		}))
+		defer server.Close()
+
+		repo := openai.NewOpenAICompatibleRepository(server.URL, "dummy-api-key")
+		_, err := repo.ChatCompletion(test.SetupContextContext(""), model.OpenAIChatRequest{Model: "gpt-4o-mini"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("異常系:非200ステータスの場合はエラーを返す", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		repo := openai.NewOpenAICompatibleRepository(server.URL, "")
+		_, err := repo.ChatCompletion(test.SetupContextContext(""), model.OpenAIChatRequest{Model: "llama3"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("異常系:choicesが空の場合はエラーを返す", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"choices":[]}`))
+		}))
+		defer server.Close()
+
+		repo := openai.NewOpenAICompatibleRepository(server.URL, "")
+		_, err := repo.ChatCompletion(test.SetupContextContext(""), model.OpenAIChatRequest{Model: "llama3"})
+
+		assert.Error(t, err)
+	})
+}
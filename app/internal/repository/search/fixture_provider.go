@@ -0,0 +1,112 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"es-api/app/internal/entity/model"
+)
+
+// defaultFixtureFile - クエリに対応するフィクスチャがない場合に返すファイル。ない場合はエラーになる
+const defaultFixtureFile = "default.json"
+
+var ErrFixtureNotFound = errors.New("検索結果のフィクスチャが見つかりません")
+
+// searchFixture - フィクスチャファイルの形式。ファイル名はクエリのハッシュ
+type searchFixture struct {
+	Query  string             `json:"query"`
+	Result model.SearchResult `json:"result"`
+}
+
+type fixtureSearchProvider struct {
+	dir string
+}
+
+// NewFixtureSearchProvider - dirに記録した検索結果を返す。外部APIを使わずに企業情報の調査を動かす場合(ローカル・CI)に使う
+func NewFixtureSearchProvider(dir string) SearchProvider {
+	return &fixtureSearchProvider{dir: dir}
+}
+
+func (p *fixtureSearchProvider) Name() string {
+	return "fixture"
+}
+
+func (p *fixtureSearchProvider) SearchWithAnswer(ctx context.Context, query string) (*model.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fixture, err := readFixture(fixturePath(p.dir, query))
+	if errors.Is(err, os.ErrNotExist) {
+		fixture, err = readFixture(filepath.Join(p.dir, defaultFixtureFile))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrFixtureNotFound, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fixture.Result, nil
+}
+
+type recordingSearchProvider struct {
+	next SearchProvider
+	dir  string
+}
+
+// NewRecordingSearchProvider - nextの検索結果をdirにフィクスチャとして保存する
+// 保存したフィクスチャはNewFixtureSearchProviderで再生できる
+func NewRecordingSearchProvider(next SearchProvider, dir string) SearchProvider {
+	return &recordingSearchProvider{next: next, dir: dir}
+}
+
+func (p *recordingSearchProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *recordingSearchProvider) SearchWithAnswer(ctx context.Context, query string) (*model.SearchResult, error) {
+	result, err := p.next.SearchWithAnswer(ctx, query)
+	if err != nil || result == nil {
+		return result, err
+	}
+	if err := writeFixture(p.dir, searchFixture{Query: query, Result: *result}); err != nil {
+		log.Printf("検索結果のフィクスチャの保存に失敗しました: %v", err)
+	}
+	return result, nil
+}
+
+// fixturePath - クエリに対応するフィクスチャファイルのパス
+func fixturePath(dir string, query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+func readFixture(path string) (*searchFixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture searchFixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("フィクスチャ%sのJSONが不正です: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+func writeFixture(dir string, fixture searchFixture) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fixturePath(dir, fixture.Query), content, 0o644)
+}
@@ -0,0 +1,69 @@
+package search_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/repository/search"
+	"es-api/app/test"
+	mock "es-api/app/test/mock/repository"
+)
+
+func TestFixtureSearchProvider_SearchWithAnswer(t *testing.T) {
+	t.Run("正常系:記録した検索結果を再生する", func(t *testing.T) {
+		dir := t.TempDir()
+		recorded := &model.SearchResult{
+			Answer:  "企業理念の要約",
+			Results: []model.SearchResultItem{{Title: "企業情報", URL: "https://example.com/about", Content: "抜粋"}},
+		}
+		upstream := new(mock.SearchProviderMock)
+		upstream.On("SearchWithAnswer", testifymock.Anything, "株式会社テスト 企業理念").Return(recorded, nil)
+
+		recorder := search.NewRecordingSearchProvider(upstream, dir)
+		_, err := recorder.SearchWithAnswer(test.SetupContextContext(""), "株式会社テスト 企業理念")
+		assert.NoError(t, err)
+
+		provider := search.NewFixtureSearchProvider(dir)
+		result, err := provider.SearchWithAnswer(test.SetupContextContext(""), "株式会社テスト 企業理念")
+
+		assert.NoError(t, err)
+		assert.Equal(t, recorded, result)
+	})
+
+	t.Run("正常系:記録がないクエリはdefault.jsonを返す", func(t *testing.T) {
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "default.json"), []byte(`{"query": "", "result": {"answer": "共通の要約"}}`), 0o600)
+		assert.NoError(t, err)
+
+		provider := search.NewFixtureSearchProvider(dir)
+		result, err := provider.SearchWithAnswer(test.SetupContextContext(""), "株式会社テスト 採用情報")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "共通の要約", result.Answer)
+	})
+
+	t.Run("異常系:記録もdefault.jsonもない場合", func(t *testing.T) {
+		provider := search.NewFixtureSearchProvider(t.TempDir())
+		result, err := provider.SearchWithAnswer(test.SetupContextContext(""), "株式会社テスト 採用情報")
+
+		assert.ErrorIs(t, err, search.ErrFixtureNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("異常系:コンテキストがキャンセルされている場合", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(test.SetupContextContext(""))
+		cancel()
+
+		provider := search.NewFixtureSearchProvider(t.TempDir())
+		result, err := provider.SearchWithAnswer(ctx, "株式会社テスト 採用情報")
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,15 @@
+package search
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+)
+
+// SearchProvider - 企業情報の調査に使うWeb検索の呼び出し先を抽象化したインターフェース
+type SearchProvider interface {
+	// Name - ログやエラーメッセージ用のプロバイダー名
+	Name() string
+	// SearchWithAnswer - 検索結果とAIによる要約を返す
+	SearchWithAnswer(ctx context.Context, query string) (*model.SearchResult, error)
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"es-api/app/internal/entity/model"
+)
+
+// DefaultTavilyBaseURL - TavilyのAPIのベースURL
+const DefaultTavilyBaseURL = "https://api.tavily.com"
+
+var ErrTavilyAPIKeyNotSet = errors.New("TAVILY_API_KEYが設定されていません")
+
+type tavilySearchProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+	// retryInterval - リトライまでの待ち時間
+	retryInterval time.Duration
+}
+
+// NewTavilySearchProvider - baseURLは /search の手前まで(例: https://api.tavily.com)。空の場合はDefaultTavilyBaseURL
+func NewTavilySearchProvider(baseURL string, apiKey string) SearchProvider {
+	if baseURL == "" {
+		baseURL = DefaultTavilyBaseURL
+	}
+	return &tavilySearchProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		retryInterval: 1 * time.Second,
+	}
+}
+
+func (p *tavilySearchProvider) Name() string {
+	return "tavily"
+}
+
+// 検索結果とAI要約を返す
+func (p *tavilySearchProvider) SearchWithAnswer(ctx context.Context, query string) (*model.SearchResult, error) {
+	if p.apiKey == "" {
+		return nil, ErrTavilyAPIKeyNotSet
+	}
+
+	var result *model.SearchResult
+	var lastErr error
+
+	// 最大3回のリトライを実行
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			log.Printf("検索クエリ「%s」のリトライ中... (%d/3)", query, attempt+1)
+			time.Sleep(p.retryInterval)
+		}
+
+		result, lastErr = p.doSearch(ctx, query)
+
+		// エラーがなく、結果とAI要約がある場合
+		if lastErr == nil && result != nil && result.Answer != "" {
+			return result, nil
+		}
+	}
+
+	// 全リトライが失敗した場合でも最後の結果を返す
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return result, nil
+}
+
+// 実際の検索リクエストを実行する内部関数
+func (p *tavilySearchProvider) doSearch(ctx context.Context, query string) (*model.SearchResult, error) {
+	params := model.TavilySearchParams{
+		Query:         query,
+		SearchDepth:   "advanced",
+		MaxResults:    5,
+		ApiKey:        p.apiKey,
+		IncludeAnswer: true,
+	}
+
+	jsonData, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/search", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result model.SearchResult
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, fmt.Errorf("JSON解析エラー: %v - レスポンス: %s", err, string(bodyBytes))
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,51 @@
+package search_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/repository/search"
+	"es-api/app/test"
+)
+
+func TestTavilySearchProvider_SearchWithAnswer(t *testing.T) {
+	t.Run("正常系:設定したベースURLに検索クエリを送る", func(t *testing.T) {
+		var params model.TavilySearchParams
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/search", r.URL.Path)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+			w.Write([]byte(`{"answer": "企業理念の要約", "results": [{"title": "企業情報", "url": "https://example.com/about", "content": "抜粋"}]}`))
+		}))
+		defer server.Close()
+
+		provider := search.NewTavilySearchProvider(server.URL+"/", "test-key")
+		result, err := provider.SearchWithAnswer(test.SetupContextContext(""), "株式会社テスト 企業理念")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.SearchResult{
+			Answer:  "企業理念の要約",
+			Results: []model.SearchResultItem{{Title: "企業情報", URL: "https://example.com/about", Content: "抜粋"}},
+		}, result)
+		assert.Equal(t, "株式会社テスト 企業理念", params.Query)
+		assert.Equal(t, "test-key", params.ApiKey)
+		assert.True(t, params.IncludeAnswer)
+	})
+
+	t.Run("異常系:APIキーが設定されていない場合はリクエストしない", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("APIキーがない場合はリクエストしない")
+		}))
+		defer server.Close()
+
+		provider := search.NewTavilySearchProvider(server.URL, "")
+		result, err := provider.SearchWithAnswer(test.SetupContextContext(""), "株式会社テスト 企業理念")
+
+		assert.ErrorIs(t, err, search.ErrTavilyAPIKeyNotSet)
+		assert.Nil(t, result)
+	})
+}
@@ -10,9 +10,17 @@ import (
 
 func NewRouter(
 	eh handler.ExperienceHandler,
+	eeh handler.ExperienceEntryHandler,
 	gh handler.LLMGenerateHandler,
 	ch handler.CompanyHandler,
+	grh handler.GenerationHandler,
+	uh handler.UsageHandler,
+	crh handler.CompanyResearchHandler,
+	gjh handler.GenerationJobHandler,
+	pth handler.PromptTemplateHandler,
 	authMiddleware echo.MiddlewareFunc,
+	quotaMiddleware echo.MiddlewareFunc,
+	adminMiddleware echo.MiddlewareFunc,
 ) *echo.Echo {
 	e := echo.New()
 	e.Logger.SetLevel(log.INFO)
@@ -22,8 +30,45 @@ func NewRouter(
 	api.Use(authMiddleware)
 	api.GET("/experience", eh.GetExperienceByUserID)
 	api.POST("/experience", eh.PostExperience)
-	api.POST("/generate", gh.Generate)
+	api.GET("/experience/profiles", eh.ListProfiles)
+	api.POST("/experience/profiles", eh.CreateProfile)
+	api.PUT("/experience/profiles/:id", eh.UpdateProfile)
+	api.DELETE("/experience/profiles/:id", eh.DeleteProfile)
+	api.POST("/experience/profiles/:id/default", eh.SetDefaultProfile)
+	api.GET("/experience/profiles/:id/episodes", eeh.ListEpisodes)
+	api.POST("/experience/profiles/:id/episodes", eeh.CreateEpisode)
+	api.PUT("/experience/episodes/:id", eeh.UpdateEpisode)
+	api.DELETE("/experience/episodes/:id", eeh.DeleteEpisode)
+	api.GET("/experience/profiles/:id/skills", eeh.ListSkills)
+	api.POST("/experience/profiles/:id/skills", eeh.CreateSkill)
+	api.PUT("/experience/skills/:id", eeh.UpdateSkill)
+	api.DELETE("/experience/skills/:id", eeh.DeleteSkill)
+	api.GET("/experience/profiles/:id/achievements", eeh.ListAchievements)
+	api.POST("/experience/profiles/:id/achievements", eeh.CreateAchievement)
+	api.PUT("/experience/achievements/:id", eeh.UpdateAchievement)
+	api.DELETE("/experience/achievements/:id", eeh.DeleteAchievement)
+	api.POST("/generate", gh.Generate, quotaMiddleware)
+	api.POST("/generate/stream", gh.GenerateStream, quotaMiddleware)
+	api.POST("/generate/jobs", gjh.CreateJob, quotaMiddleware)
+	api.GET("/generate/jobs/:id", gjh.GetJob)
+	api.POST("/generate/jobs/:id/cancel", gjh.CancelJob)
+	api.POST("/generate/questions", gh.CachedQuestions)
+	api.POST("/generate/refine", gh.Refine, quotaMiddleware)
+	api.POST("/review", gh.Review, quotaMiddleware)
 	api.GET("/companies/search", ch.SearchCompanies)
+	api.GET("/companies/:corporateNumber", ch.GetCompany)
+	api.GET("/companies/:id/research", crh.GetResearch)
+	api.GET("/generations", grh.ListGenerations)
+	api.GET("/generations/:id", grh.GetGeneration)
+	api.GET("/usage", uh.GetUsage)
+
+	admin := api.Group("/admin", adminMiddleware)
+	admin.POST("/companies/:id/research/refresh", crh.RefreshResearch)
+	admin.DELETE("/companies/:id/research", crh.DeleteResearch)
+	admin.GET("/prompts/:name", pth.ListVersions)
+	admin.POST("/prompts/:name", pth.CreateVersion)
+	admin.POST("/prompts/:name/versions/:version/activate", pth.ActivateVersion)
+	admin.POST("/prompts/:name/rollback", pth.Rollback)
 
 	return e
 }
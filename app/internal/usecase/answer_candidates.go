@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"es-api/app/internal/entity/model"
+	llm "es-api/app/internal/repository/llm"
+)
+
+// MaxAnswerCandidates - 1つの質問に対して生成できる回答候補の最大数
+const MaxAnswerCandidates = 5
+
+// answerVariant - 回答候補ごとの切り口と生成時のtemperatureの調整幅
+type answerVariant struct {
+	angle            string
+	instruction      string  // 回答生成のプロンプトに追加する指示。空の場合は追加しない
+	temperatureDelta float32 // 生成時のtemperature(リクエストの指定または設定ファイルの値)に加える値
+}
+
+// answerVariants - i番目の候補はi番目の切り口で生成する。候補数はMaxAnswerCandidates以下なので切り口は重複しない
+var answerVariants = [MaxAnswerCandidates]answerVariant{
+	{angle: "standard", temperatureDelta: -0.3},
+	{angle: "episode", instruction: "具体的なエピソードの場面と自分の行動を中心に構成してください。"},
+	{angle: "company_fit", instruction: "企業の理念や求める人材像と、自分の強みとの結びつきを中心に構成してください。"},
+	{angle: "future", instruction: "入社後に実現したいことと、そのために経験をどう活かすかを中心に構成してください。", temperatureDelta: 0.2},
+	{angle: "distinctive", instruction: "他の応募者と差がつくよう、書き出しや表現を工夫してください。", temperatureDelta: 0.4},
+}
+
+// defaultVariantTemperature - 生成時のtemperatureが指定されていない場合に、切り口ごとの調整幅を加える基準の値
+const defaultVariantTemperature float32 = 0.7
+
+// variantTemperature - 生成時のtemperatureに切り口ごとの調整幅を加え、0〜MaxTemperatureに収める
+func variantTemperature(base *float32, variant answerVariant) *float32 {
+	temperature := defaultVariantTemperature
+	if base != nil {
+		temperature = *base
+	}
+	temperature = min(max(temperature+variant.temperatureDelta, 0), MaxTemperature)
+	return &temperature
+}
+
+// answerCandidateCount - リクエストされた候補数を1〜MaxAnswerCandidatesに収める
+func answerCandidateCount(requested int) int {
+	switch {
+	case requested < 1:
+		return 1
+	case requested > MaxAnswerCandidates:
+		return MaxAnswerCandidates
+	}
+	return requested
+}
+
+// generateAnswerCandidates - baseを元に質問への回答候補をcount個並列に生成する。countが1の場合は切り口を指定せずに1つだけ生成する
+// 複数の候補を生成する場合は、候補ごとの切り口に合わせてbaseのtemperatureを調整する
+// 一部の候補の生成に失敗した場合は成功した候補だけを返し、全て失敗した場合のみエラーを返す
+func (u *llmGenerateUsecase) generateAnswerCandidates(ctx context.Context, provider llm.LLMProvider, base model.LLMInput, question string, charLimit int, count int, usage *usageRecorder) ([]model.LLMAnswerCandidate, error) {
+	llmModel := base.Model
+	results := make([]*model.LLMAnswerCandidate, count)
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("回答候補%dの生成中にパニックが発生: %v", i+1, r)
+				}
+			}()
+
+			variant := answerVariants[i]
+			input := base
+			if count > 1 {
+				if variant.instruction != "" {
+					input.Text += "【回答の切り口】\n" + variant.instruction + "\n"
+				}
+				input.Params.Temperature = variantTemperature(base.Params.Temperature, variant)
+			}
+
+			resp, err := provider.Generate(ctx, input)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			usage.add(model.LLMUsagePurposeGeneration, llmModel, resp)
+
+			answer := u.fitCharLimit(ctx, provider, llmModel, question, resp.Text, charLimit, usage)
+			results[i] = &model.LLMAnswerCandidate{
+				Answer:    answer,
+				CharCount: countChars(answer),
+				Angle:     variant.angle,
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	candidates := make([]model.LLMAnswerCandidate, 0, count)
+	for _, c := range results {
+		if c != nil {
+			candidates = append(candidates, *c)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("質問「%s」の回答候補%dの生成に失敗: %v", question, i+1, err)
+		}
+	}
+	return candidates, nil
+}
+
+// answerScoresSchema - 回答候補の採点結果のスキーマ
+var answerScoresSchema = &model.LLMSchema{
+	Type: model.LLMSchemaTypeObject,
+	Properties: map[string]*model.LLMSchema{
+		"scores": {
+			Type: model.LLMSchemaTypeArray,
+			Items: &model.LLMSchema{
+				Type: model.LLMSchemaTypeObject,
+				Properties: map[string]*model.LLMSchema{
+					"index":       {Type: model.LLMSchemaTypeInteger, Description: "回答候補の番号(1から)"},
+					"specificity": {Type: model.LLMSchemaTypeInteger, Description: "具体性(1〜5)"},
+					"companyFit":  {Type: model.LLMSchemaTypeInteger, Description: "企業との合致(1〜5)"},
+					"charLimit":   {Type: model.LLMSchemaTypeInteger, Description: "文字数制限の遵守(1〜5)"},
+					"comment":     {Type: model.LLMSchemaTypeString, Description: "短い講評"},
+				},
+				Required: []string{"index", "specificity", "companyFit", "charLimit"},
+			},
+		},
+	},
+	Required: []string{"scores"},
+}
+
+// answerScore - 採点結果の1候補分。Indexは1から始まる候補の番号
+type answerScore struct {
+	Index       int    `json:"index"`
+	Specificity int    `json:"specificity"`
+	CompanyFit  int    `json:"companyFit"`
+	CharLimit   int    `json:"charLimit"`
+	Comment     string `json:"comment"`
+}
+
+// rankAnswerCandidates - 回答候補をLLMに採点させ、合計点の高い順に並べ替える
+// 採点に失敗した場合は、採点なしで生成した順番のまま返す
+func (u *llmGenerateUsecase) rankAnswerCandidates(ctx context.Context, provider llm.LLMProvider, llmModel model.LLMModel, question string, charLimit int, companyInfo *model.CompanyInfo, candidates []model.LLMAnswerCandidate, usage *usageRecorder) []model.LLMAnswerCandidate {
+	prompt, err := u.buildJudgePrompt(ctx, question, charLimit, companyInfo, candidates)
+	if err != nil {
+		log.Printf("質問「%s」の回答候補を採点できません: %v", question, err)
+		return candidates
+	}
+	resp, err := provider.Generate(ctx, model.LLMInput{
+		Model:          llmModel,
+		Text:           prompt,
+		ResponseSchema: answerScoresSchema,
+		Params:         u.params.forPurpose(model.LLMUsagePurposeJudge),
+	})
+	if err != nil {
+		log.Printf("質問「%s」の回答候補の採点に失敗: %v", question, err)
+		return candidates
+	}
+	usage.add(model.LLMUsagePurposeJudge, llmModel, resp)
+
+	scores, err := parseAnswerScores(resp.Text)
+	if err != nil {
+		log.Printf("質問「%s」の回答候補の採点結果を解析できませんでした: %v", question, err)
+		return candidates
+	}
+
+	ranked := append([]model.LLMAnswerCandidate{}, candidates...)
+	for _, s := range scores {
+		i := s.Index - 1
+		if i < 0 || i >= len(ranked) {
+			continue
+		}
+		score := &model.LLMAnswerScore{
+			Specificity: clampScore(s.Specificity),
+			CompanyFit:  clampScore(s.CompanyFit),
+			CharLimit:   clampScore(s.CharLimit),
+			Comment:     strings.TrimSpace(s.Comment),
+		}
+		score.Total = score.Specificity + score.CompanyFit + score.CharLimit
+		ranked[i].Score = score
+	}
+
+	// 採点されなかった候補は最後に回す
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return candidateTotal(ranked[i]) > candidateTotal(ranked[j])
+	})
+	return ranked
+}
+
+func candidateTotal(c model.LLMAnswerCandidate) int {
+	if c.Score == nil {
+		return -1
+	}
+	return c.Score.Total
+}
+
+// clampScore - 採点を1〜5点に収める
+func clampScore(score int) int {
+	switch {
+	case score < 1:
+		return 1
+	case score > 5:
+		return 5
+	}
+	return score
+}
+
+// parseAnswerScores - 採点結果を解析する
+func parseAnswerScores(text string) ([]answerScore, error) {
+	var result struct {
+		Scores []answerScore `json:"scores"`
+	}
+	if err := unmarshalLLMJSON(text, &result); err != nil || result.Scores == nil {
+		return nil, errors.New("採点結果のJSONが不正です")
+	}
+	return result.Scores, nil
+}
+
+// unmarshalLLMJSON - LLMが返したJSONオブジェクトを解析する。コードブロックや前置きの文章が付いている場合も受け付ける
+func unmarshalLLMJSON(text string, v interface{}) error {
+	candidates := []string{text}
+	if m := codeFencePattern.FindStringSubmatch(text); m != nil {
+		candidates = append(candidates, m[1])
+	}
+	if start, end := strings.Index(text, "{"), strings.LastIndex(text, "}"); start >= 0 && end > start {
+		candidates = append(candidates, text[start:end+1])
+	}
+
+	var err error
+	for _, c := range candidates {
+		if err = json.Unmarshal([]byte(c), v); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// buildJudgePrompt - 回答候補を採点させるプロンプト
+func (u *llmGenerateUsecase) buildJudgePrompt(ctx context.Context, question string, charLimit int, companyInfo *model.CompanyInfo, candidates []model.LLMAnswerCandidate) (string, error) {
+	promptTemplate, err := u.prompts.load(ctx, model.PromptTemplateJudgeAnswers)
+	if err != nil {
+		return "", err
+	}
+
+	limit := "なし"
+	if charLimit > 0 {
+		limit = fmt.Sprintf("改行を除いて%d字以上%d字以内", minChars(charLimit), charLimit)
+	}
+
+	companyText := u.companyContext(companyInfo)
+	if companyText == "" {
+		companyText = "なし"
+	}
+
+	var list strings.Builder
+	for i, c := range candidates {
+		list.WriteString(fmt.Sprintf("[%d] (%d字)\n%s\n\n", i+1, c.CharCount, c.Answer))
+	}
+
+	return promptTemplate.render(promptJudgeData{
+		Question:    question,
+		CharLimit:   limit,
+		CompanyInfo: companyText,
+		Candidates:  strings.TrimSpace(list.String()),
+	})
+}
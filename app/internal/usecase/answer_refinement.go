@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"es-api/app/internal/entity/model"
+)
+
+// RefineAnswer - 生成済みの回答を、ユーザーの修正指示に従って書き直す
+// 最初の生成と同じプロンプト・回答・これまでの修正指示を会話の履歴として渡し、続けて今回の修正指示を送る
+func (u *llmGenerateUsecase) RefineAnswer(ctx context.Context, req model.LLMRefineRequest) (*model.LLMRefineResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	llmModel := modelFromEnv("LLM_DEFAULT_MODEL", model.GeminiFlashLite)
+	if model.LLMModel(req.Model) != "" {
+		llmModel = model.LLMModel(req.Model)
+	}
+	provider, err := u.llmRegistry.Resolve(llmModel)
+	if err != nil {
+		return nil, err
+	}
+
+	experience, err := u.loadExperience(ctx, req.ProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := newUsageRecorder()
+	defer u.saveUsage(ctx, usage)
+
+	charLimit := req.CharLimit
+	if charLimit <= 0 {
+		charLimit = parseCharLimit(req.Question)
+	}
+
+	companyInfo, err := u.researcher.companyInfo(ctx, req.CompanyID, req.CompanyName)
+	if err != nil {
+		// 企業情報がなくても修正はできるので、エラーはログに記録するのみ
+		log.Printf("企業情報の取得に失敗しました: %v", err)
+	}
+	companyInfo = u.withCompanyDetails(ctx, companyInfo, req.CompanyID, req.CompanyName)
+
+	// 最初の生成時と同じ文脈になるよう、回答生成のプロンプトを履歴の先頭に置く
+	generationPrompt, err := u.prompts.load(ctx, model.PromptTemplateESGeneration)
+	if err != nil {
+		return nil, err
+	}
+	chunks := u.selectExperienceChunks(ctx, splitExperience(&experience), []string{req.Question})[0]
+	prompt, err := u.buildPrompt(generationPrompt, req.Question, charLimit, companyInfo, &experience, chunks, req.CompanyName)
+	if err != nil {
+		return nil, err
+	}
+	history := []model.LLMMessage{
+		{Role: model.LLMMessageRoleUser, Text: prompt},
+		{Role: model.LLMMessageRoleModel, Text: req.Answer},
+	}
+	refinePrompt, err := u.prompts.load(ctx, model.PromptTemplateRefineAnswer)
+	if err != nil {
+		return nil, err
+	}
+	for _, turn := range req.History {
+		turnPrompt, err := buildRefinePrompt(refinePrompt, turn.Instruction, charLimit)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history,
+			model.LLMMessage{Role: model.LLMMessageRoleUser, Text: turnPrompt},
+			model.LLMMessage{Role: model.LLMMessageRoleModel, Text: turn.Answer},
+		)
+	}
+	instructionPrompt, err := buildRefinePrompt(refinePrompt, req.Instruction, charLimit)
+	if err != nil {
+		return nil, err
+	}
+	systemInstruction, _ := u.systemInstruction(ctx)
+
+	resp, err := provider.Generate(ctx, model.LLMInput{
+		Model:             llmModel,
+		Text:              instructionPrompt,
+		History:           history,
+		SystemInstruction: systemInstruction,
+		Params:            u.params.forPurpose(model.LLMUsagePurposeRefine),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("回答の修正に失敗しました: %w", err)
+	}
+	usage.add(model.LLMUsagePurposeRefine, llmModel, resp)
+
+	answer := u.fitCharLimit(ctx, provider, llmModel, req.Question, resp.Text, charLimit, usage)
+	return &model.LLMRefineResponse{
+		Question:  req.Question,
+		Answer:    answer,
+		CharLimit: charLimit,
+		CharCount: countChars(answer),
+	}, nil
+}
+
+// buildRefinePrompt - 会話の続きとして送る修正指示のプロンプト
+func buildRefinePrompt(promptTemplate *promptTemplate, instruction string, limit int) (string, error) {
+	limitInstruction := ""
+	if limit > 0 {
+		limitInstruction = charLimitInstruction(limit)
+	}
+	return promptTemplate.render(promptRefineData{
+		Instruction:          instruction,
+		CharLimitInstruction: limitInstruction,
+	})
+}
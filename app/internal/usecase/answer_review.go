@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"es-api/app/internal/entity/model"
+)
+
+// reviewAxis - 添削の評価軸の定義
+type reviewAxis struct {
+	axis     model.LLMReviewAxis
+	label    string
+	criteria string // プロンプトに含める採点基準
+}
+
+// reviewAxes - 評価軸。添削結果の点数はこの順番で返す
+var reviewAxes = []reviewAxis{
+	{model.LLMReviewAxisSpecificity, "具体性", "具体的なエピソード・行動・数値が含まれ、応募者ならではの内容になっているか"},
+	{model.LLMReviewAxisCompanyFit, "企業との合致", "企業の理念や求める人材像と結びついた内容になっているか"},
+	{model.LLMReviewAxisStructure, "論理構成", "結論から述べ、理由・根拠・学びが一貫した流れになっているか"},
+	{model.LLMReviewAxisExperience, "経験の活かし方", "応募者の経歴情報と矛盾せず、強みが十分に活かされているか"},
+	{model.LLMReviewAxisExpression, "表現", "冗長な表現や重複がなく、簡潔で読みやすいか"},
+}
+
+// commonFillerPhrases - 添削時に必ず検出する冗長な表現
+var commonFillerPhrases = []string{
+	"非常に", "とても", "すごく", "様々な", "さまざまな", "色々な", "いろいろな",
+	"しっかりと", "やはり", "基本的に", "と思います", "させていただ", "することができ", "ということ",
+}
+
+// reviewResultSchema - 添削結果のスキーマ
+var reviewResultSchema = func() *model.LLMSchema {
+	axes := make([]string, len(reviewAxes))
+	for i, a := range reviewAxes {
+		axes[i] = string(a.axis)
+	}
+	return &model.LLMSchema{
+		Type: model.LLMSchemaTypeObject,
+		Properties: map[string]*model.LLMSchema{
+			"summary": {Type: model.LLMSchemaTypeString, Description: "総評"},
+			"scores": {
+				Type: model.LLMSchemaTypeArray,
+				Items: &model.LLMSchema{
+					Type: model.LLMSchemaTypeObject,
+					Properties: map[string]*model.LLMSchema{
+						"axis":    {Type: model.LLMSchemaTypeString, Enum: axes},
+						"score":   {Type: model.LLMSchemaTypeInteger, Description: "1〜5点"},
+						"comment": {Type: model.LLMSchemaTypeString, Description: "点数の理由"},
+					},
+					Required: []string{"axis", "score", "comment"},
+				},
+			},
+			"suggestions":   {Type: model.LLMSchemaTypeArray, Items: &model.LLMSchema{Type: model.LLMSchemaTypeString}, Description: "具体的な改善案"},
+			"fillerPhrases": {Type: model.LLMSchemaTypeArray, Items: &model.LLMSchema{Type: model.LLMSchemaTypeString}, Description: "回答中の冗長な表現"},
+		},
+		Required: []string{"summary", "scores", "suggestions", "fillerPhrases"},
+	}
+}()
+
+// reviewResult - LLMが返す添削結果
+type reviewResult struct {
+	Summary string `json:"summary"`
+	Scores  []struct {
+		Axis    model.LLMReviewAxis `json:"axis"`
+		Score   int                 `json:"score"`
+		Comment string              `json:"comment"`
+	} `json:"scores"`
+	Suggestions   []string `json:"suggestions"`
+	FillerPhrases []string `json:"fillerPhrases"`
+}
+
+// ReviewAnswer - ユーザーが書いた回答を、回答生成と同じ企業情報・経験情報をもとに添削する
+// 文字数制限と冗長な表現の検出はLLMに頼らずに判定する
+func (u *llmGenerateUsecase) ReviewAnswer(ctx context.Context, req model.LLMReviewRequest) (*model.LLMReviewResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	llmModel := modelFromEnv("LLM_DEFAULT_MODEL", model.GeminiFlashLite)
+	if model.LLMModel(req.Model) != "" {
+		llmModel = model.LLMModel(req.Model)
+	}
+	provider, err := u.llmRegistry.Resolve(llmModel)
+	if err != nil {
+		return nil, err
+	}
+
+	experience, err := u.loadExperience(ctx, req.ProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := newUsageRecorder()
+	defer u.saveUsage(ctx, usage)
+
+	charLimit := req.CharLimit
+	if charLimit <= 0 {
+		charLimit = parseCharLimit(req.Question)
+	}
+	limitCheck := checkCharLimit(req.Draft, charLimit)
+
+	companyInfo, err := u.researcher.companyInfo(ctx, req.CompanyID, req.CompanyName)
+	if err != nil {
+		// 企業情報がなくても添削はできるので、エラーはログに記録するのみ
+		log.Printf("企業情報の取得に失敗しました: %v", err)
+	}
+	companyInfo = u.withCompanyDetails(ctx, companyInfo, req.CompanyID, req.CompanyName)
+
+	chunks := u.selectExperienceChunks(ctx, splitExperience(&experience), []string{req.Question})[0]
+	var experienceText strings.Builder
+	writeExperience(&experienceText, &experience, chunks)
+
+	prompt, err := u.buildReviewPrompt(ctx, req.Question, req.Draft, limitCheck, companyInfo, strings.TrimSpace(experienceText.String()))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := provider.Generate(ctx, model.LLMInput{
+		Model:          llmModel,
+		Text:           prompt,
+		ResponseSchema: reviewResultSchema,
+		Params:         u.params.forPurpose(model.LLMUsagePurposeReview),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("回答の添削に失敗しました: %w", err)
+	}
+	usage.add(model.LLMUsagePurposeReview, llmModel, resp)
+
+	var result reviewResult
+	if err := unmarshalLLMJSON(resp.Text, &result); err != nil {
+		return nil, fmt.Errorf("添削結果を解析できませんでした: %w", err)
+	}
+	scores := toReviewScores(result)
+	if len(scores) == 0 {
+		return nil, errors.New("添削結果に評価軸の点数が含まれていませんでした")
+	}
+
+	total := 0
+	for _, s := range scores {
+		total += s.Score
+	}
+	suggestions := make([]string, 0, len(result.Suggestions))
+	for _, s := range result.Suggestions {
+		if s = strings.TrimSpace(s); s != "" {
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	return &model.LLMReviewResponse{
+		Question:      req.Question,
+		Scores:        scores,
+		Total:         total,
+		Summary:       strings.TrimSpace(result.Summary),
+		Suggestions:   suggestions,
+		FillerPhrases: detectFillerPhrases(req.Draft, result.FillerPhrases),
+		CharLimit:     limitCheck,
+	}, nil
+}
+
+// toReviewScores - LLMの採点を評価軸の定義順に並べる。定義にない評価軸と重複は無視する
+func toReviewScores(result reviewResult) []model.LLMReviewScore {
+	scores := make([]model.LLMReviewScore, 0, len(reviewAxes))
+	for _, a := range reviewAxes {
+		for _, s := range result.Scores {
+			if s.Axis == a.axis {
+				scores = append(scores, model.LLMReviewScore{
+					Axis:    a.axis,
+					Label:   a.label,
+					Score:   clampScore(s.Score),
+					Comment: strings.TrimSpace(s.Comment),
+				})
+				break
+			}
+		}
+	}
+	return scores
+}
+
+// checkCharLimit - 回答が文字数制限を満たしているかを判定する
+func checkCharLimit(draft string, limit int) model.LLMCharLimitCheck {
+	count := countChars(draft)
+	if limit <= 0 {
+		return model.LLMCharLimitCheck{Count: count, Status: model.LLMCharLimitStatusNone}
+	}
+
+	status := model.LLMCharLimitStatusOK
+	switch {
+	case count > limit:
+		status = model.LLMCharLimitStatusTooLong
+	case count < minChars(limit):
+		status = model.LLMCharLimitStatusTooShort
+	}
+	return model.LLMCharLimitCheck{
+		Limit:  limit,
+		Min:    minChars(limit),
+		Count:  count,
+		Status: status,
+	}
+}
+
+// detectFillerPhrases - 決まった冗長な表現と、LLMが挙げた表現のうち実際に回答に含まれるものを、最初に現れる順に返す
+func detectFillerPhrases(draft string, llmPhrases []string) []model.LLMFillerPhrase {
+	seen := make(map[string]bool)
+	phrases := make([]model.LLMFillerPhrase, 0)
+	for _, p := range append(append([]string{}, commonFillerPhrases...), llmPhrases...) {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		if count := strings.Count(draft, p); count > 0 {
+			phrases = append(phrases, model.LLMFillerPhrase{Phrase: p, Count: count})
+		}
+	}
+	sort.SliceStable(phrases, func(i, j int) bool {
+		return strings.Index(draft, phrases[i].Phrase) < strings.Index(draft, phrases[j].Phrase)
+	})
+	return phrases
+}
+
+// buildReviewPrompt - 添削のプロンプト
+func (u *llmGenerateUsecase) buildReviewPrompt(ctx context.Context, question string, draft string, limitCheck model.LLMCharLimitCheck, companyInfo *model.CompanyInfo, experience string) (string, error) {
+	promptTemplate, err := u.prompts.load(ctx, model.PromptTemplateReviewAnswer)
+	if err != nil {
+		return "", err
+	}
+
+	limit := fmt.Sprintf("%d字(文字数制限なし)", limitCheck.Count)
+	if limitCheck.Limit > 0 {
+		limit = fmt.Sprintf("%d字(改行を除いて%d字以上%d字以内の制限)", limitCheck.Count, limitCheck.Min, limitCheck.Limit)
+	}
+
+	var axes strings.Builder
+	for _, a := range reviewAxes {
+		axes.WriteString(fmt.Sprintf("- %s(%s): %s\n", a.axis, a.label, a.criteria))
+	}
+
+	companyText := u.companyContext(companyInfo)
+	if companyText == "" {
+		companyText = "なし"
+	}
+	if experience == "" {
+		experience = "なし"
+	}
+
+	return promptTemplate.render(promptReviewData{
+		Question:    question,
+		Draft:       draft,
+		CharLimit:   limit,
+		Axes:        strings.TrimSpace(axes.String()),
+		CompanyInfo: companyText,
+		Experience:  experience,
+	})
+}
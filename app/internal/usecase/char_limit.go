@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"es-api/app/internal/entity/model"
+	llm "es-api/app/internal/repository/llm"
+)
+
+const defaultMaxRewrites = 2
+
+// charLimitPattern - 「（400字以内）」「400文字まで」「最大４００字」などの数字部分
+var charLimitPattern = regexp.MustCompile(`([0-9０-９][0-9０-９,，]*)\s*文?字`)
+
+// parseCharLimit - 質問文から文字数制限を読み取る。複数ある場合は最後のものを使い、ない場合は0を返す
+func parseCharLimit(question string) int {
+	matches := charLimitPattern.FindAllStringSubmatch(question, -1)
+	if len(matches) == 0 {
+		return 0
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９':
+			return '0' + (r - '０')
+		case r == ',' || r == '，':
+			return -1
+		}
+		return r
+	}, matches[len(matches)-1][1])
+
+	limit, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	return limit
+}
+
+// countChars - 回答の文字数。日本語のESの数え方に合わせて、バイト数ではなく文字(rune)数で数え、改行は含めない
+func countChars(text string) int {
+	return utf8.RuneCountInString(text) - strings.Count(text, "\n") - strings.Count(text, "\r")
+}
+
+// minChars - 文字数制限に対して、少なすぎると判断する下限(制限の80%)
+func minChars(limit int) int {
+	return (limit*8 + 9) / 10
+}
+
+// withinCharLimit - 文字数が制限の80%以上、制限以内かどうか
+func withinCharLimit(count, limit int) bool {
+	return count >= minChars(limit) && count <= limit
+}
+
+// maxRewrites - 文字数制限を満たさない回答を書き直す最大回数
+func maxRewrites() int {
+	if v, err := strconv.Atoi(os.Getenv("CHAR_LIMIT_MAX_REWRITES")); err == nil && v >= 0 {
+		return v
+	}
+	return defaultMaxRewrites
+}
+
+// charLimitInstruction - 回答生成のプロンプトに追加する文字数の指示
+func charLimitInstruction(limit int) string {
+	return fmt.Sprintf("【文字数制限】\n改行を除いて%d字以上%d字以内で回答してください。\n\n", minChars(limit), limit)
+}
+
+// fitCharLimit - 回答が文字数制限を満たすまで、最大maxRewrites回書き直しを依頼する
+// 書き直しに失敗した場合や回数を使い切った場合は、それまでで最も制限に近い回答を返す
+func (u *llmGenerateUsecase) fitCharLimit(ctx context.Context, provider llm.LLMProvider, llmModel model.LLMModel, question, answer string, limit int, usage *usageRecorder) string {
+	if limit <= 0 {
+		return answer
+	}
+
+	if withinCharLimit(countChars(answer), limit) {
+		return answer
+	}
+	promptTemplate, err := u.prompts.load(ctx, model.PromptTemplateRewriteAnswer)
+	if err != nil {
+		log.Printf("質問「%s」の回答を書き直せません: %v", question, err)
+		return answer
+	}
+
+	best := answer
+	for i := 0; i < maxRewrites(); i++ {
+		count := countChars(answer)
+		if withinCharLimit(count, limit) {
+			return answer
+		}
+
+		prompt, err := promptTemplate.render(promptRewriteData{
+			Question:  question,
+			Answer:    answer,
+			CharCount: count,
+			MinChars:  minChars(limit),
+			CharLimit: limit,
+		})
+		if err != nil {
+			log.Printf("質問「%s」の回答を書き直せません: %v", question, err)
+			return best
+		}
+		resp, err := provider.Generate(ctx, model.LLMInput{
+			Model:  llmModel,
+			Text:   prompt,
+			Params: u.params.forPurpose(model.LLMUsagePurposeRewrite),
+		})
+		if err != nil {
+			log.Printf("質問「%s」の回答の書き直しに失敗: %v", question, err)
+			return best
+		}
+		usage.add(model.LLMUsagePurposeRewrite, llmModel, resp)
+
+		answer = resp.Text
+		if closerToCharLimit(countChars(answer), countChars(best), limit) {
+			best = answer
+		}
+	}
+
+	if count := countChars(best); !withinCharLimit(count, limit) {
+		log.Printf("質問「%s」の回答が文字数制限を満たしませんでした: %d字(制限%d字)", question, count, limit)
+	}
+	return best
+}
+
+// closerToCharLimit - 文字数aの回答がbより制限に近いか。制限を超えた回答は、制限以内の回答より常に劣るとみなす
+func closerToCharLimit(a, b, limit int) bool {
+	switch {
+	case a <= limit && b <= limit:
+		return a > b
+	case a <= limit:
+		return true
+	case b <= limit:
+		return false
+	}
+	return a < b
+}
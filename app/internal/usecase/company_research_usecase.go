@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"es-api/app/internal/entity/model"
+	db "es-api/app/internal/repository/db"
+	"es-api/app/internal/repository/search"
+)
+
+const (
+	// defaultCompanyResearchTTL - 企業情報のキャッシュを新しいとみなす期間
+	defaultCompanyResearchTTL = 30 * 24 * time.Hour
+	// defaultCompanyResearchRetryInterval - 空の項目がある企業情報を検索し直すまでの間隔
+	defaultCompanyResearchRetryInterval = 6 * time.Hour
+	// companyResearchTimeout - 企業情報の検索全体のタイムアウト
+	companyResearchTimeout = 20 * time.Second
+	// maxCitationRunes - 出典として保存する検索結果の抜粋の最大文字数
+	maxCitationRunes = 500
+)
+
+var (
+	ErrCompanyResearchNotFound = errors.New("企業情報のキャッシュが見つかりません")
+	ErrCompanyNameRequired     = errors.New("キャッシュがない企業の情報を取得するには企業名が必要です")
+)
+
+type CompanyResearchUsecase interface {
+	GetResearch(ctx context.Context, companyID string) (*model.CompanyResearch, error)
+	RefreshResearch(ctx context.Context, companyID string, companyName string) (*model.CompanyResearch, error)
+	DeleteResearch(ctx context.Context, companyID string) error
+}
+
+type companyResearchUsecase struct {
+	researcher *CompanyResearcher
+}
+
+// NewCompanyResearchUsecase - researcherは回答生成と共有し、同じ企業のバックグラウンド更新が重複しないようにする
+func NewCompanyResearchUsecase(researcher *CompanyResearcher) CompanyResearchUsecase {
+	return &companyResearchUsecase{
+		researcher: researcher,
+	}
+}
+
+// GetResearch - キャッシュされている企業情報を出典と合わせて返す
+func (u *companyResearchUsecase) GetResearch(ctx context.Context, companyID string) (*model.CompanyResearch, error) {
+	research, err := u.researcher.companyResearchRepo.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("企業情報のキャッシュ検索中にエラーが発生しました: %w", err)
+	}
+	if research == nil {
+		return nil, ErrCompanyResearchNotFound
+	}
+	return research, nil
+}
+
+// RefreshResearch - キャッシュの有効期限に関わらず企業情報を検索し直す
+// 企業名が指定されていない場合はキャッシュの企業名を使う
+func (u *companyResearchUsecase) RefreshResearch(ctx context.Context, companyID string, companyName string) (*model.CompanyResearch, error) {
+	cached, err := u.researcher.companyResearchRepo.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("企業情報のキャッシュ検索中にエラーが発生しました: %w", err)
+	}
+	if companyName == "" && cached != nil {
+		companyName = cached.CompanyName
+	}
+	if companyName == "" {
+		return nil, ErrCompanyNameRequired
+	}
+	return u.researcher.refresh(ctx, companyID, companyName, cached)
+}
+
+// DeleteResearch - 企業情報のキャッシュを削除する。次に回答を生成する際に検索し直される
+func (u *companyResearchUsecase) DeleteResearch(ctx context.Context, companyID string) error {
+	err := u.researcher.companyResearchRepo.DeleteByCompanyID(ctx, companyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCompanyResearchNotFound
+	}
+	return err
+}
+
+// CompanyResearcher - Web検索での企業情報の調査と、その結果のキャッシュを管理する
+// 更新中の企業を記録しているので、アプリケーション全体で1つだけ作成して各ユースケースに渡す
+type CompanyResearcher struct {
+	searchProvider      search.SearchProvider
+	companyResearchRepo db.CompanyResearchRepository
+	dimensions          []researchDimension
+	ttl                 time.Duration
+	retryInterval       time.Duration
+	refreshing          sync.Map // バックグラウンドで更新中の法人番号
+}
+
+func NewCompanyResearcher(searchProvider search.SearchProvider, companyResearchRepo db.CompanyResearchRepository) *CompanyResearcher {
+	return &CompanyResearcher{
+		searchProvider:      searchProvider,
+		companyResearchRepo: companyResearchRepo,
+		dimensions:          loadResearchDimensions(),
+		ttl:                 durationFromEnv("COMPANY_RESEARCH_TTL", defaultCompanyResearchTTL),
+		retryInterval:       durationFromEnv("COMPANY_RESEARCH_RETRY_INTERVAL", defaultCompanyResearchRetryInterval),
+	}
+}
+
+// durationFromEnv - 環境変数から期間(例: 720h)を読み取る。未設定や不正な値の場合はdefaultValueを返す
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return defaultValue
+}
+
+// companyInfo - 企業情報を取得する
+// 揃っているキャッシュは期限切れでもそのまま返し、バックグラウンドで更新する(stale-while-revalidate)
+// 空の項目があるキャッシュは、最後の検索からretryInterval以上経過していれば検索し直し、検索に失敗した場合はキャッシュの内容を返す
+func (r *CompanyResearcher) companyInfo(ctx context.Context, companyID string, companyName string) (*model.CompanyInfo, error) {
+	// キャッシュから企業情報を検索
+	research, err := r.companyResearchRepo.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("企業情報のキャッシュ検索中にエラーが発生しました: %w", err)
+	}
+
+	if research != nil && research.IsComplete(researchSections(r.dimensions)) {
+		if research.IsStale(r.ttl, time.Now()) {
+			r.refreshInBackground(ctx, research)
+		}
+		log.Printf("企業情報のキャッシュを利用します: %s", research.CompanyName)
+		return research.ToCompanyInfo(), nil
+	}
+
+	if research != nil {
+		if research.SearchedWithin(r.retryInterval, time.Now()) {
+			log.Printf("企業情報のキャッシュに空の項目がありますが、最近検索したためそのまま利用します: %s", research.CompanyName)
+			return research.ToCompanyInfo(), nil
+		}
+		log.Printf("企業情報のキャッシュに空の項目があるため検索し直します: %s", research.CompanyName)
+	}
+	refreshed, err := r.refresh(ctx, companyID, companyName, research)
+	if err != nil {
+		if research != nil {
+			log.Printf("企業情報の再検索に失敗したため、キャッシュを利用します: %v", err)
+			// 失敗した場合も検索した日時を記録し、retryIntervalが経過するまで検索し直さない
+			if err := r.companyResearchRepo.MarkSearched(ctx, companyID, time.Now()); err != nil {
+				log.Printf("企業情報の検索日時の保存中にエラーが発生しました: %v", err)
+			}
+			return research.ToCompanyInfo(), nil
+		}
+		return nil, err
+	}
+	return refreshed.ToCompanyInfo(), nil
+}
+
+// refresh - 企業情報を検索してキャッシュに保存する。検索で得られなかった項目はcachedの内容を残す
+func (r *CompanyResearcher) refresh(ctx context.Context, companyID string, companyName string, cached *model.CompanyResearch) (*model.CompanyResearch, error) {
+	// 企業情報を検索
+	ctx, cancel := context.WithTimeout(ctx, companyResearchTimeout)
+	defer cancel()
+
+	companyInfo, err := r.searchCompanyInfoParallel(ctx, companyName)
+	if err != nil {
+		return nil, fmt.Errorf("企業情報の検索中にエラーが発生しました: %w", err)
+	}
+
+	research := &model.CompanyResearch{
+		CompanyID:   companyID,
+		CompanyName: companyName,
+		Contents:    companyInfo.Contents,
+		Citations:   companyInfo.Citations,
+		SearchedAt:  time.Now(),
+	}
+	if cached != nil {
+		// 検索で得られなかった項目は、内容と出典をキャッシュから引き継ぐ
+		for _, d := range r.dimensions {
+			if research.Contents[d.Name] == "" && cached.Contents[d.Name] != "" {
+				research.Contents[d.Name] = cached.Contents[d.Name]
+				research.Citations = append(research.Citations, cached.SectionCitations(d.Name)...)
+			}
+		}
+	}
+
+	// 検索結果をキャッシュに保存
+	if err := r.companyResearchRepo.Upsert(ctx, research); err != nil {
+		log.Printf("企業情報のキャッシュ保存中にエラーが発生しました: %v", err)
+	}
+
+	return research, nil
+}
+
+// refreshInBackground - 期限切れのキャッシュをバックグラウンドで更新する。同じ企業の更新が実行中の場合は何もしない
+func (r *CompanyResearcher) refreshInBackground(ctx context.Context, cached *model.CompanyResearch) {
+	if _, running := r.refreshing.LoadOrStore(cached.CompanyID, struct{}{}); running {
+		return
+	}
+
+	// リクエストが終わってもキャンセルされないようにする。DB接続の選択に使う値はそのまま引き継ぐ
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer r.refreshing.Delete(cached.CompanyID)
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("企業情報のバックグラウンド更新中にパニックが発生: %v", rec)
+			}
+		}()
+
+		log.Printf("企業情報のキャッシュが期限切れのため、バックグラウンドで更新します: %s", cached.CompanyName)
+		if _, err := r.refresh(ctx, cached.CompanyID, cached.CompanyName, cached); err != nil {
+			log.Printf("企業情報のバックグラウンド更新に失敗しました: %v", err)
+		}
+	}()
+}
+
+// citationsFromResult - 検索結果を出典として保存する形式に変換する
+func citationsFromResult(section model.ResearchSection, result *model.SearchResult) []model.CompanyResearchCitations {
+	citations := make([]model.CompanyResearchCitations, 0, len(result.Results))
+	for _, r := range result.Results {
+		if r.URL == "" {
+			continue
+		}
+		citations = append(citations, model.CompanyResearchCitations{
+			Section: section,
+			Title:   r.Title,
+			URL:     r.URL,
+			Content: truncateRunes(normalizeSpace(r.Content), maxCitationRunes),
+		})
+	}
+	return citations
+}
+
+// searchCompanyInfoParallel - 調査項目ごとに並行して検索する
+// 検索に失敗した項目は空のまま、得られた項目だけを返す。全ての項目の検索に失敗した場合はエラーを返す
+func (r *CompanyResearcher) searchCompanyInfoParallel(ctx context.Context, companyName string) (*model.CompanyInfo, error) {
+	// 調査項目ごとの検索結果とエラー。各goroutineは自分の項目だけに書き込む
+	results := make([]*model.SearchResult, len(r.dimensions))
+	errs := make([]error, len(r.dimensions))
+
+	var wg sync.WaitGroup
+	for i, d := range r.dimensions {
+		wg.Add(1)
+		go func(i int, d researchDimension) {
+			defer wg.Done()
+
+			result, err := r.searchDimension(ctx, companyName, d)
+			if err != nil {
+				log.Printf("%sの検索エラー(%s): %v", d.Label, r.searchProvider.Name(), err)
+				errs[i] = fmt.Errorf("%sの検索エラー: %w", d.Label, err)
+				return
+			}
+			results[i] = result
+		}(i, d)
+	}
+
+	// 全ての検索が完了するのを待機
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(r.dimensions) {
+		return nil, errors.Join(errs...)
+	}
+
+	info := &model.CompanyInfo{
+		Name:     companyName,
+		Contents: make(map[model.ResearchSection]string, len(r.dimensions)),
+	}
+	for i, d := range r.dimensions {
+		if results[i] == nil {
+			continue
+		}
+		info.Contents[d.Name] = results[i].Answer
+		info.Citations = append(info.Citations, citationsFromResult(d.Name, results[i])...)
+	}
+
+	// エラーがあっても部分的な結果を返す
+	return info, nil
+}
+
+// searchDimension - 調査項目の検索クエリを順に試し、回答が得られた最初の検索結果を返す
+// どのクエリでも回答が得られなかった場合はnilを返す
+func (r *CompanyResearcher) searchDimension(ctx context.Context, companyName string, d researchDimension) (*model.SearchResult, error) {
+	var lastErr error
+	for _, query := range d.queries(companyName) {
+		result, err := r.searchProvider.SearchWithAnswer(ctx, query)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if result != nil && result.Answer != "" {
+			return result, nil
+		}
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,366 @@
+package usecase_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/repository/search"
+	"es-api/app/internal/usecase"
+	"es-api/app/test"
+	mock "es-api/app/test/mock/repository"
+)
+
+func TestCompanyResearchUsecase_GetResearch(t *testing.T) {
+	t.Run("正常系:企業情報を出典と合わせて返す", func(t *testing.T) {
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+			CompanyID: "1234567890123",
+			Citations: []model.CompanyResearchCitations{{Section: model.ResearchSectionPhilosophy, URL: "https://example.com/about"}},
+		}, nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(new(mock.SearchProviderMock), researchMock))
+		research, err := uc.GetResearch(test.SetupContextContext("test-user"), "1234567890123")
+
+		assert.NoError(t, err)
+		assert.Len(t, research.Citations, 1)
+	})
+
+	t.Run("異常系:キャッシュが存在しない場合", func(t *testing.T) {
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(nil, nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(new(mock.SearchProviderMock), researchMock))
+		research, err := uc.GetResearch(test.SetupContextContext("test-user"), "1234567890123")
+
+		assert.ErrorIs(t, err, usecase.ErrCompanyResearchNotFound)
+		assert.Nil(t, research)
+	})
+}
+
+func TestCompanyResearchUsecase_RefreshResearch(t *testing.T) {
+	t.Run("正常系:キャッシュの企業名で検索し直し、得られなかった項目はキャッシュの内容を残す", func(t *testing.T) {
+		searchMock := new(mock.SearchProviderMock)
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+			CompanyID:   "1234567890123",
+			CompanyName: "株式会社テスト",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy:  "古い企業理念",
+				model.ResearchSectionCareerPath:  "古いキャリアパス",
+				model.ResearchSectionTalentNeeds: "古い求める人材像",
+			},
+			Citations: []model.CompanyResearchCitations{
+				{ID: 1, Section: model.ResearchSectionPhilosophy, Title: "古い記事", URL: "https://example.com/old-philosophy"},
+				{ID: 2, Section: model.ResearchSectionTalentNeeds, Title: "採用ページ", URL: "https://example.com/recruit"},
+			},
+		}, nil)
+		result := &model.SearchResult{
+			Answer:  "新しい情報",
+			Results: []model.SearchResultItem{{Title: "企業情報", URL: "https://example.com/about", Content: "  会社概要の\n抜粋  "}},
+		}
+		searchMock.On("SearchWithAnswer", testifymock.Anything, testifymock.MatchedBy(func(query string) bool {
+			return strings.HasPrefix(query, "株式会社テスト") && !strings.Contains(query, "人材") && !strings.Contains(query, "採用")
+		})).Return(result, nil)
+		searchMock.On("SearchWithAnswer", testifymock.Anything, testifymock.Anything).Return(nil, errors.New("search error"))
+		researchMock.On("Upsert", testifymock.Anything, testifymock.Anything).Return(nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(searchMock, researchMock))
+		research, err := uc.RefreshResearch(test.SetupContextContext("admin-user"), "1234567890123", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "株式会社テスト", research.CompanyName)
+		assert.Equal(t, "新しい情報", research.Contents[model.ResearchSectionPhilosophy])
+		assert.Equal(t, "新しい情報", research.Contents[model.ResearchSectionCareerPath])
+		assert.Equal(t, "古い求める人材像", research.Contents[model.ResearchSectionTalentNeeds])
+		if assert.Len(t, research.Citations, 3) {
+			assert.Equal(t, model.CompanyResearchCitations{Section: model.ResearchSectionPhilosophy, Title: "企業情報", URL: "https://example.com/about", Content: "会社概要の 抜粋"}, research.Citations[0])
+			assert.Equal(t, model.ResearchSectionCareerPath, research.Citations[1].Section)
+			assert.Equal(t, "https://example.com/recruit", research.Citations[2].URL)
+		}
+		researchMock.AssertCalled(t, "Upsert", testifymock.Anything, research)
+	})
+
+	t.Run("異常系:キャッシュがなく企業名も指定されていない場合", func(t *testing.T) {
+		searchMock := new(mock.SearchProviderMock)
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(nil, nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(searchMock, researchMock))
+		research, err := uc.RefreshResearch(test.SetupContextContext("admin-user"), "1234567890123", "")
+
+		assert.ErrorIs(t, err, usecase.ErrCompanyNameRequired)
+		assert.Nil(t, research)
+		searchMock.AssertNotCalled(t, "SearchWithAnswer", testifymock.Anything, testifymock.Anything)
+	})
+}
+
+func TestCompanyResearchUsecase_FixtureSearchProvider(t *testing.T) {
+	t.Run("正常系:記録した検索結果で企業情報を調査する", func(t *testing.T) {
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(nil, nil)
+		researchMock.On("Upsert", testifymock.Anything, testifymock.Anything).Return(nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(search.NewFixtureSearchProvider("../../test/fixture/search"), researchMock))
+		research, err := uc.RefreshResearch(test.SetupContextContext("admin-user"), "1234567890123", "株式会社テスト")
+
+		assert.NoError(t, err)
+		assert.True(t, research.IsComplete([]model.ResearchSection{model.ResearchSectionPhilosophy, model.ResearchSectionCareerPath, model.ResearchSectionTalentNeeds}))
+		assert.Contains(t, research.Contents[model.ResearchSectionPhilosophy], "挑戦を楽しむ")
+		assert.Len(t, research.Citations, 3)
+	})
+
+	t.Run("異常系:記録がない企業は全ての項目の検索に失敗する", func(t *testing.T) {
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("FindByCompanyID", testifymock.Anything, "9999999999999").Return(nil, nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(search.NewFixtureSearchProvider("../../test/fixture/search"), researchMock))
+		research, err := uc.RefreshResearch(test.SetupContextContext("admin-user"), "9999999999999", "株式会社サンプル")
+
+		assert.ErrorIs(t, err, search.ErrFixtureNotFound)
+		assert.Nil(t, research)
+		researchMock.AssertNotCalled(t, "Upsert", testifymock.Anything, testifymock.Anything)
+	})
+}
+
+func TestCompanyResearchUsecase_ResearchDimensions(t *testing.T) {
+	writeDimensions := func(t *testing.T, content string) {
+		path := filepath.Join(t.TempDir(), "research_dimensions.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("COMPANY_RESEARCH_DIMENSIONS_FILE", path)
+	}
+
+	t.Run("正常系:設定ファイルで定義した調査項目を検索し、回答がなければ代わりのクエリを使う", func(t *testing.T) {
+		writeDimensions(t, `[
+			{"name": "philosophy", "label": "企業理念", "queries": ["{{.CompanyName}} 理念"]},
+			{"name": "news", "label": "最近のニュース", "queries": ["{{.CompanyName}} ニュース"], "fallbackQueries": ["{{.CompanyName}} プレスリリース"]}
+		]`)
+		searchMock := new(mock.SearchProviderMock)
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(nil, nil)
+		searchMock.On("SearchWithAnswer", testifymock.Anything, "株式会社テスト 理念").Return(&model.SearchResult{Answer: "理念の情報"}, nil)
+		searchMock.On("SearchWithAnswer", testifymock.Anything, "株式会社テスト ニュース").Return(&model.SearchResult{}, nil)
+		searchMock.On("SearchWithAnswer", testifymock.Anything, "株式会社テスト プレスリリース").Return(&model.SearchResult{
+			Answer:  "新製品を発表",
+			Results: []model.SearchResultItem{{Title: "プレスリリース", URL: "https://example.com/news"}},
+		}, nil)
+		researchMock.On("Upsert", testifymock.Anything, testifymock.Anything).Return(nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(searchMock, researchMock))
+		research, err := uc.RefreshResearch(test.SetupContextContext("admin-user"), "1234567890123", "株式会社テスト")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[model.ResearchSection]string{
+			model.ResearchSectionPhilosophy: "理念の情報",
+			"news":                          "新製品を発表",
+		}, research.Contents)
+		assert.Equal(t, []model.CompanyResearchCitations{
+			{Section: "news", Title: "プレスリリース", URL: "https://example.com/news"},
+		}, research.Citations)
+		searchMock.AssertNumberOfCalls(t, "SearchWithAnswer", 3)
+	})
+
+	t.Run("異常系:設定ファイルが不正な場合はデフォルトの調査項目を使う", func(t *testing.T) {
+		writeDimensions(t, `[{"name": "news", "label": "最近のニュース", "queries": []}]`)
+		searchMock := new(mock.SearchProviderMock)
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(nil, nil)
+		searchMock.On("SearchWithAnswer", testifymock.Anything, testifymock.Anything).Return(&model.SearchResult{Answer: "情報"}, nil)
+		researchMock.On("Upsert", testifymock.Anything, testifymock.Anything).Return(nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(searchMock, researchMock))
+		research, err := uc.RefreshResearch(test.SetupContextContext("admin-user"), "1234567890123", "株式会社テスト")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[model.ResearchSection]string{
+			model.ResearchSectionPhilosophy:  "情報",
+			model.ResearchSectionCareerPath:  "情報",
+			model.ResearchSectionTalentNeeds: "情報",
+		}, research.Contents)
+	})
+}
+
+func TestCompanyResearchUsecase_DeleteResearch(t *testing.T) {
+	t.Run("正常系:キャッシュを削除する", func(t *testing.T) {
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("DeleteByCompanyID", testifymock.Anything, "1234567890123").Return(nil)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(new(mock.SearchProviderMock), researchMock))
+		err := uc.DeleteResearch(test.SetupContextContext("admin-user"), "1234567890123")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("異常系:キャッシュが存在しない場合", func(t *testing.T) {
+		researchMock := new(mock.CompanyResearchRepositoryMock)
+		researchMock.On("DeleteByCompanyID", testifymock.Anything, "1234567890123").Return(gorm.ErrRecordNotFound)
+
+		uc := usecase.NewCompanyResearchUsecase(usecase.NewCompanyResearcher(new(mock.SearchProviderMock), researchMock))
+		err := uc.DeleteResearch(test.SetupContextContext("admin-user"), "1234567890123")
+
+		assert.ErrorIs(t, err, usecase.ErrCompanyResearchNotFound)
+	})
+}
+
+func TestLLMGenerateUsecase_CompanyResearchCache(t *testing.T) {
+	t.Run("正常系:期限切れのキャッシュはそのまま使い、バックグラウンドで更新する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.research.ExpectedCalls = nil
+		m.research.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+			CompanyID:   "1234567890123",
+			CompanyName: "株式会社テスト",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy:  "古い企業理念",
+				model.ResearchSectionCareerPath:  "古いキャリアパス",
+				model.ResearchSectionTalentNeeds: "古い求める人材像",
+			},
+			UpdatedAt: time.Now().Add(-31 * 24 * time.Hour),
+		}, nil)
+		m.search.On("SearchWithAnswer", testifymock.Anything, testifymock.Anything).Return(&model.SearchResult{Answer: "新しい情報"}, nil)
+		upserted := make(chan *model.CompanyResearch, 1)
+		m.research.On("Upsert", testifymock.Anything, testifymock.Anything).Run(func(args testifymock.Arguments) {
+			upserted <- args.Get(1).(*model.CompanyResearch)
+		}).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機")(input) && strings.Contains(input.Text, "古い企業理念")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		select {
+		case research := <-upserted:
+			assert.Equal(t, "新しい情報", research.Contents[model.ResearchSectionPhilosophy])
+		case <-time.After(time.Second):
+			t.Fatal("バックグラウンドでの更新が行われませんでした")
+		}
+	})
+
+	t.Run("正常系:空の項目があるキャッシュは検索し直す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.research.ExpectedCalls = nil
+		m.research.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+			CompanyID:   "1234567890123",
+			CompanyName: "株式会社テスト",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy: "テスト企業理念",
+			},
+			SearchedAt: time.Now().Add(-7 * time.Hour),
+			UpdatedAt:  time.Now().Add(-7 * time.Hour),
+		}, nil)
+		m.search.On("SearchWithAnswer", testifymock.Anything, testifymock.Anything).Return(&model.SearchResult{Answer: "新しい情報"}, nil)
+		m.research.On("Upsert", testifymock.Anything, testifymock.Anything).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機")(input) && strings.Contains(input.Text, "新しい情報")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		m.research.AssertCalled(t, "Upsert", testifymock.Anything, testifymock.MatchedBy(func(r *model.CompanyResearch) bool {
+			return r.IsComplete([]model.ResearchSection{model.ResearchSectionPhilosophy, model.ResearchSectionCareerPath, model.ResearchSectionTalentNeeds})
+		}))
+	})
+
+	t.Run("正常系:検索し直せない場合は空の項目があるキャッシュを使う", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.research.ExpectedCalls = nil
+		m.research.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+			CompanyID:   "1234567890123",
+			CompanyName: "株式会社テスト",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy: "テスト企業理念",
+			},
+			SearchedAt: time.Now().Add(-7 * time.Hour),
+			UpdatedAt:  time.Now().Add(-7 * time.Hour),
+		}, nil)
+		m.search.On("SearchWithAnswer", testifymock.Anything, testifymock.Anything).Return(nil, errors.New("TAVILY_API_KEYが設定されていません"))
+		m.research.On("MarkSearched", testifymock.Anything, "1234567890123", testifymock.Anything).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機")(input) && strings.Contains(input.Text, "テスト企業理念")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		m.research.AssertNotCalled(t, "Upsert", testifymock.Anything, testifymock.Anything)
+		m.research.AssertCalled(t, "MarkSearched", testifymock.Anything, "1234567890123", testifymock.Anything)
+	})
+
+	t.Run("正常系:最近検索した空の項目があるキャッシュは検索し直さない", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.research.ExpectedCalls = nil
+		m.research.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+			CompanyID:   "1234567890123",
+			CompanyName: "株式会社テスト",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy: "テスト企業理念",
+			},
+			SearchedAt: time.Now().Add(-time.Hour),
+			UpdatedAt:  time.Now().Add(-time.Hour),
+		}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機")(input) && strings.Contains(input.Text, "テスト企業理念")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		m.search.AssertNotCalled(t, "SearchWithAnswer", testifymock.Anything, testifymock.Anything)
+	})
+}
+
+func TestLLMGenerateUsecase_ResearchDimensions(t *testing.T) {
+	t.Run("正常系:調査項目の見出しと設定の順番でプロンプトに含める", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "research_dimensions.json")
+		content := `[
+			{"name": "news", "label": "最近のニュース", "queries": ["{{.CompanyName}} ニュース"]},
+			{"name": "philosophy", "label": "企業理念", "queries": ["{{.CompanyName}} 理念"]}
+		]`
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("COMPANY_RESEARCH_DIMENSIONS_FILE", path)
+		m := newLLMGenerateMocks()
+		m.research.ExpectedCalls = nil
+		m.research.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+			CompanyID:   "1234567890123",
+			CompanyName: "株式会社テスト",
+			Contents: map[model.ResearchSection]string{
+				model.ResearchSectionPhilosophy: "テスト企業理念",
+				"news":                          "新製品を発表",
+			},
+			UpdatedAt: time.Now(),
+		}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			news := strings.Index(input.Text, "■最近のニュース\n新製品を発表")
+			philosophy := strings.Index(input.Text, "■企業理念\nテスト企業理念")
+			return isAnswerInput("志望動機")(input) && news >= 0 && philosophy > news
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.LLMAnswerStatusSucceeded, res[0].Status)
+		m.search.AssertNotCalled(t, "SearchWithAnswer", testifymock.Anything, testifymock.Anything)
+	})
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"es-api/app/internal/entity/model"
+)
+
+// companyLegalForms - 検索時に無視する法人格(NFKCで正規化した後の表記)
+var companyLegalForms = []string{
+	"株式会社", "有限会社", "合同会社", "合資会社", "合名会社",
+	"一般社団法人", "一般財団法人", "公益社団法人", "公益財団法人",
+	"(株)", "(有)", "(同)",
+}
+
+// normalizeCompanyName - 法人名を検索用に正規化する
+// 全角英数字・半角カナをNFKCで統一し、カタカナをひらがなに、英字を小文字にして、法人格・空白・記号を除く
+func normalizeCompanyName(name string) string {
+	s := norm.NFKC.String(name)
+	for _, form := range companyLegalForms {
+		s = strings.ReplaceAll(s, form, "")
+	}
+	s = strings.ToLower(s)
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'ァ' && r <= 'ヶ':
+			b.WriteRune(r - 0x60)
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// toCompanyIndex - gBizINFOの検索結果を、ローカルインデックスに保存する形に変換する
+func toCompanyIndex(companies []model.CompanyBasicInfo) []model.Companies {
+	index := make([]model.Companies, 0, len(companies))
+	for _, c := range companies {
+		if c.CompanyID == "" {
+			continue
+		}
+		index = append(index, model.Companies{
+			CorporateNumber: c.CompanyID,
+			Name:            c.CompanyName,
+			NormalizedName:  normalizeCompanyName(c.CompanyName),
+		})
+	}
+	return index
+}
+
+// mergeCompanies - ローカルの検索結果の後ろに、含まれていないgBizINFOの検索結果を順位順に追加し、limitとoffsetの範囲を返す
+// localは先頭からの検索結果で、offset+limit件に満たない(ローカルの検索結果が全て含まれている)こと
+func mergeCompanies(normalizedQuery string, local []model.Companies, remote []model.CompanyBasicInfo, limit int, offset int) []model.CompanyBasicInfo {
+	merged := make([]model.CompanyBasicInfo, 0, offset+limit)
+	seen := make(map[string]bool, len(local))
+	for _, c := range local {
+		merged = append(merged, c.ToBasicInfo())
+		seen[c.CorporateNumber] = true
+	}
+
+	ranked := make([]model.CompanyBasicInfo, 0, len(remote))
+	for _, c := range remote {
+		if !seen[c.CompanyID] {
+			ranked = append(ranked, c)
+			seen[c.CompanyID] = true
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return compareCompanyRank(normalizedQuery, ranked[i].CompanyName, ranked[j].CompanyName) < 0
+	})
+
+	for _, c := range ranked {
+		if len(merged) >= offset+limit {
+			break
+		}
+		merged = append(merged, c)
+	}
+	if offset >= len(merged) {
+		return []model.CompanyBasicInfo{}
+	}
+	return merged[offset:]
+}
+
+// compareCompanyRank - ローカル検索と同じ基準(完全一致・前方一致・部分一致、一致位置、名前の長さ)で順位を比較する
+func compareCompanyRank(normalizedQuery string, a string, b string) int {
+	ka, kb := companyRankKey(normalizedQuery, a), companyRankKey(normalizedQuery, b)
+	for i := range ka {
+		if ka[i] != kb[i] {
+			return ka[i] - kb[i]
+		}
+	}
+	return 0
+}
+
+func companyRankKey(normalizedQuery string, name string) [3]int {
+	normalized := normalizeCompanyName(name)
+	length := len([]rune(normalized))
+	switch {
+	case normalized == normalizedQuery:
+		return [3]int{0, 0, length}
+	case strings.HasPrefix(normalized, normalizedQuery):
+		return [3]int{1, 0, length}
+	}
+	pos := strings.Index(normalized, normalizedQuery)
+	if pos < 0 {
+		return [3]int{3, 0, length}
+	}
+	return [3]int{2, len([]rune(normalized[:pos])), length}
+}
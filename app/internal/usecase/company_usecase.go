@@ -2,25 +2,209 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
 
 	"es-api/app/internal/entity/model"
+	db "es-api/app/internal/repository/db"
 	"es-api/app/internal/repository/gbiz"
 )
 
+const (
+	// defaultCompanyDetailTTL - gBizINFOの法人情報のキャッシュを新しいとみなす期間
+	defaultCompanyDetailTTL = 30 * 24 * time.Hour
+	// companyDetailTimeout - gBizINFOへの問い合わせのタイムアウト
+	companyDetailTimeout = 5 * time.Second
+	// defaultCompanySearchTTL - 同じキーワードでgBizINFOに再度問い合わせるまでの期間
+	defaultCompanySearchTTL   = 24 * time.Hour
+	defaultCompanySearchLimit = 20
+	maxCompanySearchLimit     = 100
+)
+
+var (
+	ErrCompanyNotFound        = errors.New("法人が見つかりません")
+	ErrInvalidCorporateNumber = errors.New("法人番号は13桁の数字で指定してください")
+	corporateNumberPattern    = regexp.MustCompile(`^[0-9]{13}$`)
+)
+
 type CompanyUsecase interface {
-	SearchCompanies(ctx context.Context, keyword string) ([]model.CompanyBasicInfo, error)
+	SearchCompanies(ctx context.Context, keyword string, limit int, offset int) ([]model.CompanyBasicInfo, error)
+	GetCompany(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error)
 }
 
 type companyUsecase struct {
-	gbizRepo gbiz.GBizInfoRepository
+	gbizRepo    gbiz.GBizInfoRepository
+	companyRepo db.CompanyRepository
+	details     *companyDetailLoader
+	searchTTL   time.Duration
 }
 
-func NewCompanyUsecase(gbizRepo gbiz.GBizInfoRepository) CompanyUsecase {
+func NewCompanyUsecase(gbizRepo gbiz.GBizInfoRepository, companyDetailRepo db.CompanyDetailRepository, companyRepo db.CompanyRepository) CompanyUsecase {
 	return &companyUsecase{
-		gbizRepo: gbizRepo,
+		gbizRepo:    gbizRepo,
+		companyRepo: companyRepo,
+		details:     newCompanyDetailLoader(gbizRepo, companyDetailRepo),
+		searchTTL:   durationFromEnv("COMPANY_SEARCH_TTL", defaultCompanySearchTTL),
+	}
+}
+
+// SearchCompanies - 法人名で企業を検索する
+// ローカルのインデックスを優先し、ページが埋まらずgBizINFOで最近検索していないキーワードの場合だけgBizINFOに問い合わせる
+// gBizINFOの検索結果はインデックスに保存し、ローカルの検索結果と合わせて返す
+func (u *companyUsecase) SearchCompanies(ctx context.Context, keyword string, limit int, offset int) ([]model.CompanyBasicInfo, error) {
+	if limit <= 0 {
+		limit = defaultCompanySearchLimit
+	}
+	if limit > maxCompanySearchLimit {
+		limit = maxCompanySearchLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := normalizeCompanyName(keyword)
+	if query == "" {
+		// 法人格や記号だけのキーワードはインデックスで検索できないので、gBizINFOの結果をそのまま返す
+		return u.searchRemote(ctx, keyword, limit, offset)
+	}
+
+	local, err := u.companyRepo.Search(ctx, query, limit, offset)
+	if err != nil {
+		log.Printf("企業インデックスの検索に失敗したため、gBizINFOで検索します: %v", err)
+		return u.searchRemote(ctx, keyword, limit, offset)
+	}
+	if len(local) >= limit || u.recentlySearched(ctx, query) {
+		return toBasicInfos(local), nil
+	}
+
+	remote, err := u.gbizRepo.SearchCompanies(ctx, keyword)
+	if err != nil {
+		if len(local) > 0 {
+			log.Printf("gBizINFOでの企業検索に失敗したため、インデックスの検索結果を返します: %v", err)
+			return toBasicInfos(local), nil
+		}
+		return nil, err
+	}
+
+	if err := u.companyRepo.UpsertMany(ctx, toCompanyIndex(remote)); err != nil {
+		log.Printf("企業インデックスの保存中にエラーが発生しました: %v", err)
+		return u.mergeWithIndex(ctx, query, local, remote, limit, offset)
+	}
+	if err := u.companyRepo.SaveSearchQuery(ctx, query); err != nil {
+		log.Printf("検索済みキーワードの保存中にエラーが発生しました: %v", err)
+	}
+
+	// 保存した検索結果を含めて、同じ順位付けでページを取り直す
+	indexed, err := u.companyRepo.Search(ctx, query, limit, offset)
+	if err != nil {
+		log.Printf("企業インデックスの検索中にエラーが発生しました: %v", err)
+		return u.mergeWithIndex(ctx, query, local, remote, limit, offset)
+	}
+	return toBasicInfos(indexed), nil
+}
+
+// mergeWithIndex - gBizINFOの検索結果をインデックスから検索できない場合に、ローカルの検索結果と合わせてページを作る
+// pageはoffsetからのローカルの検索結果。2ページ目以降は、前のページに含まれる企業を除くために先頭から検索し直す
+func (u *companyUsecase) mergeWithIndex(ctx context.Context, query string, page []model.Companies, remote []model.CompanyBasicInfo, limit int, offset int) ([]model.CompanyBasicInfo, error) {
+	local := page
+	if offset > 0 {
+		var err error
+		local, err = u.companyRepo.Search(ctx, query, offset+limit, 0)
+		if err != nil {
+			return nil, fmt.Errorf("企業インデックスの検索中にエラーが発生しました: %w", err)
+		}
+	}
+	return mergeCompanies(query, local, remote, limit, offset), nil
+}
+
+// recentlySearched - キーワードをsearchTTL以内にgBizINFOで検索したかどうか
+func (u *companyUsecase) recentlySearched(ctx context.Context, query string) bool {
+	searched, err := u.companyRepo.FindSearchQuery(ctx, query)
+	if err != nil {
+		log.Printf("検索済みキーワードの取得中にエラーが発生しました: %v", err)
+		return false
+	}
+	return searched != nil && !searched.IsStale(u.searchTTL, time.Now())
+}
+
+// searchRemote - gBizINFOだけで検索し、limitとoffsetの範囲を返す
+func (u *companyUsecase) searchRemote(ctx context.Context, keyword string, limit int, offset int) ([]model.CompanyBasicInfo, error) {
+	companies, err := u.gbizRepo.SearchCompanies(ctx, keyword)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= len(companies) {
+		return []model.CompanyBasicInfo{}, nil
+	}
+	companies = companies[offset:]
+	if len(companies) > limit {
+		companies = companies[:limit]
+	}
+	return companies, nil
+}
+
+func toBasicInfos(companies []model.Companies) []model.CompanyBasicInfo {
+	infos := make([]model.CompanyBasicInfo, 0, len(companies))
+	for _, c := range companies {
+		infos = append(infos, c.ToBasicInfo())
+	}
+	return infos
+}
+
+// GetCompany - 法人番号で法人情報(所在地・資本金・従業員数・設立年月日・事業概要・URL)を取得する
+func (u *companyUsecase) GetCompany(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error) {
+	if !corporateNumberPattern.MatchString(corporateNumber) {
+		return nil, ErrInvalidCorporateNumber
 	}
+	return u.details.load(ctx, corporateNumber)
 }
 
-func (u *companyUsecase) SearchCompanies(ctx context.Context, keyword string) ([]model.CompanyBasicInfo, error) {
-	return u.gbizRepo.SearchCompanies(ctx, keyword)
+// companyDetailLoader - gBizINFOの法人情報を、ローカルのキャッシュを優先して取得する
+type companyDetailLoader struct {
+	gbizRepo          gbiz.GBizInfoRepository
+	companyDetailRepo db.CompanyDetailRepository
+	ttl               time.Duration
+}
+
+func newCompanyDetailLoader(gbizRepo gbiz.GBizInfoRepository, companyDetailRepo db.CompanyDetailRepository) *companyDetailLoader {
+	return &companyDetailLoader{
+		gbizRepo:          gbizRepo,
+		companyDetailRepo: companyDetailRepo,
+		ttl:               durationFromEnv("COMPANY_DETAIL_TTL", defaultCompanyDetailTTL),
+	}
+}
+
+// load - キャッシュが新しければそれを返し、古いかない場合はgBizINFOから取得して保存する
+// gBizINFOへの問い合わせに失敗した場合は、古いキャッシュがあればそれを返す
+func (l *companyDetailLoader) load(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error) {
+	cached, err := l.companyDetailRepo.FindByCorporateNumber(ctx, corporateNumber)
+	if err != nil {
+		return nil, fmt.Errorf("法人情報のキャッシュ検索中にエラーが発生しました: %w", err)
+	}
+	if cached != nil && !cached.IsStale(l.ttl, time.Now()) {
+		return cached, nil
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, companyDetailTimeout)
+	defer cancel()
+
+	details, err := l.gbizRepo.GetCompany(fetchCtx, corporateNumber)
+	if err != nil {
+		if errors.Is(err, gbiz.ErrCorporationNotFound) {
+			return nil, ErrCompanyNotFound
+		}
+		if cached != nil {
+			log.Printf("法人情報の取得に失敗したため、キャッシュを利用します: %v", err)
+			return cached, nil
+		}
+		return nil, fmt.Errorf("法人情報の取得中にエラーが発生しました: %w", err)
+	}
+
+	if err := l.companyDetailRepo.Upsert(ctx, details); err != nil {
+		log.Printf("法人情報のキャッシュ保存中にエラーが発生しました: %v", err)
+	}
+	return details, nil
 }
@@ -1,77 +1,254 @@
 package usecase_test
 
 import (
-	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	testifymock "github.com/stretchr/testify/mock"
 
 	"es-api/app/internal/entity/model"
+	"es-api/app/internal/repository/gbiz"
 	"es-api/app/internal/usecase"
+	"es-api/app/test"
 	mock "es-api/app/test/mock/repository"
 )
 
 func TestCompanyUsecase_SearchCompanies(t *testing.T) {
-	t.Run("正常系:検索結果がある場合", func(t *testing.T) {
-		mockRepo := new(mock.GBizInfoRepositoryMock)
-
-		expectedCompanies := []model.CompanyBasicInfo{
-			{
-				CompanyID:   "1234567890123",
-				CompanyName: "株式会社テスト",
-			},
-		}
+	t.Run("正常系:インデックスでページが埋まる場合はgBizINFOに問い合わせない", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		companyMock := new(mock.CompanyRepositoryMock)
+		companyMock.On("Search", testifymock.Anything, "てすと", 1, 0).Return([]model.Companies{
+			{CorporateNumber: "1234567890123", Name: "株式会社テスト", NormalizedName: "てすと"},
+		}, nil)
 
-		ctx := context.Background()
-		keyword := "株式会社テスト"
+		uc := usecase.NewCompanyUsecase(gbizMock, new(mock.CompanyDetailRepositoryMock), companyMock)
+		res, err := uc.SearchCompanies(test.SetupContextContext("test-user"), "テスト", 1, 0)
 
-		mockRepo.On("SearchCompanies", testifymock.Anything, keyword).Return(expectedCompanies, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CompanyBasicInfo{{CompanyID: "1234567890123", CompanyName: "株式会社テスト"}}, res)
+		gbizMock.AssertNotCalled(t, "SearchCompanies", testifymock.Anything, testifymock.Anything)
+	})
 
-		uc := usecase.NewCompanyUsecase(mockRepo)
+	t.Run("正常系:キーワードは全角半角・カタカナ・法人格を正規化して検索する", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		companyMock := new(mock.CompanyRepositoryMock)
+		companyMock.On("Search", testifymock.Anything, "abcてすと", 20, 0).Return([]model.Companies{}, nil)
+		companyMock.On("FindSearchQuery", testifymock.Anything, "abcてすと").Return(&model.CompanySearchQueries{Query: "abcてすと", SearchedAt: time.Now()}, nil)
 
-		res, err := uc.SearchCompanies(ctx, keyword)
+		uc := usecase.NewCompanyUsecase(gbizMock, new(mock.CompanyDetailRepositoryMock), companyMock)
+		res, err := uc.SearchCompanies(test.SetupContextContext("test-user"), "ＡＢＣ ﾃｽﾄ（株）", 0, 0)
 
 		assert.NoError(t, err)
-		assert.NotNil(t, res)
-		assert.Equal(t, expectedCompanies[0].CompanyID, res[0].CompanyID)
-		assert.Equal(t, expectedCompanies[0].CompanyName, res[0].CompanyName)
-		mockRepo.AssertExpectations(t)
+		assert.Empty(t, res)
+		companyMock.AssertExpectations(t)
+		gbizMock.AssertNotCalled(t, "SearchCompanies", testifymock.Anything, testifymock.Anything)
 	})
 
-	t.Run("正常系:検索結果が空の場合", func(t *testing.T) {
-		mockRepo := new(mock.GBizInfoRepositoryMock)
-		ctx := context.Background()
-		keyword := "存在しない会社"
+	t.Run("正常系:インデックスで足りない場合はgBizINFOの結果を保存して検索し直す", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		companyMock := new(mock.CompanyRepositoryMock)
+		local := model.Companies{CorporateNumber: "1234567890123", Name: "株式会社テスト", NormalizedName: "てすと"}
+		remote := []model.CompanyBasicInfo{
+			{CompanyID: "1234567890124", CompanyName: "テスト商事株式会社"},
+			{CompanyID: "1234567890123", CompanyName: "株式会社テスト"},
+		}
+		companyMock.On("Search", testifymock.Anything, "てすと", 20, 0).Return([]model.Companies{local}, nil).Once()
+		companyMock.On("FindSearchQuery", testifymock.Anything, "てすと").Return(nil, nil)
+		gbizMock.On("SearchCompanies", testifymock.Anything, "テスト").Return(remote, nil)
+		companyMock.On("UpsertMany", testifymock.Anything, []model.Companies{
+			{CorporateNumber: "1234567890124", Name: "テスト商事株式会社", NormalizedName: "てすと商事"},
+			{CorporateNumber: "1234567890123", Name: "株式会社テスト", NormalizedName: "てすと"},
+		}).Return(nil)
+		companyMock.On("SaveSearchQuery", testifymock.Anything, "てすと").Return(nil)
+		companyMock.On("Search", testifymock.Anything, "てすと", 20, 0).Return([]model.Companies{
+			local,
+			{CorporateNumber: "1234567890124", Name: "テスト商事株式会社", NormalizedName: "てすと商事"},
+		}, nil).Once()
+
+		uc := usecase.NewCompanyUsecase(gbizMock, new(mock.CompanyDetailRepositoryMock), companyMock)
+		res, err := uc.SearchCompanies(test.SetupContextContext("test-user"), "テスト", 0, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CompanyBasicInfo{
+			{CompanyID: "1234567890123", CompanyName: "株式会社テスト"},
+			{CompanyID: "1234567890124", CompanyName: "テスト商事株式会社"},
+		}, res)
+		companyMock.AssertExpectations(t)
+		gbizMock.AssertExpectations(t)
+	})
 
-		mockRepo.On("SearchCompanies", testifymock.Anything, keyword).Return([]model.CompanyBasicInfo{}, nil)
+	t.Run("正常系:インデックスに保存できない場合はgBizINFOの結果を順位順に後ろに追加する", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		companyMock := new(mock.CompanyRepositoryMock)
+		companyMock.On("Search", testifymock.Anything, "てすと", 20, 0).Return([]model.Companies{
+			{CorporateNumber: "1234567890123", Name: "株式会社テスト", NormalizedName: "てすと"},
+		}, nil)
+		companyMock.On("FindSearchQuery", testifymock.Anything, "てすと").Return(nil, nil)
+		gbizMock.On("SearchCompanies", testifymock.Anything, "テスト").Return([]model.CompanyBasicInfo{
+			{CompanyID: "1234567890125", CompanyName: "株式会社ベストテスト"},
+			{CompanyID: "1234567890123", CompanyName: "株式会社テスト"},
+			{CompanyID: "1234567890124", CompanyName: "テスト商事株式会社"},
+		}, nil)
+		companyMock.On("UpsertMany", testifymock.Anything, testifymock.Anything).Return(errors.New("db error"))
+
+		uc := usecase.NewCompanyUsecase(gbizMock, new(mock.CompanyDetailRepositoryMock), companyMock)
+		res, err := uc.SearchCompanies(test.SetupContextContext("test-user"), "テスト", 0, 0)
 
-		uc := usecase.NewCompanyUsecase(mockRepo)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CompanyBasicInfo{
+			{CompanyID: "1234567890123", CompanyName: "株式会社テスト"},
+			{CompanyID: "1234567890124", CompanyName: "テスト商事株式会社"},
+			{CompanyID: "1234567890125", CompanyName: "株式会社ベストテスト"},
+		}, res)
+	})
 
-		res, err := uc.SearchCompanies(ctx, keyword)
+	t.Run("正常系:インデックスに保存できない場合も2ページ目以降はoffsetを適用する", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		companyMock := new(mock.CompanyRepositoryMock)
+		companyMock.On("Search", testifymock.Anything, "てすと", 2, 2).Return([]model.Companies{}, nil)
+		companyMock.On("Search", testifymock.Anything, "てすと", 4, 0).Return([]model.Companies{
+			{CorporateNumber: "1234567890123", Name: "株式会社テスト", NormalizedName: "てすと"},
+		}, nil)
+		companyMock.On("FindSearchQuery", testifymock.Anything, "てすと").Return(nil, nil)
+		gbizMock.On("SearchCompanies", testifymock.Anything, "テスト").Return([]model.CompanyBasicInfo{
+			{CompanyID: "1234567890126", CompanyName: "株式会社テストシステムズ"},
+			{CompanyID: "1234567890125", CompanyName: "株式会社ベストテスト"},
+			{CompanyID: "1234567890123", CompanyName: "株式会社テスト"},
+			{CompanyID: "1234567890124", CompanyName: "テスト商事株式会社"},
+		}, nil)
+		companyMock.On("UpsertMany", testifymock.Anything, testifymock.Anything).Return(errors.New("db error"))
+
+		uc := usecase.NewCompanyUsecase(gbizMock, new(mock.CompanyDetailRepositoryMock), companyMock)
+		res, err := uc.SearchCompanies(test.SetupContextContext("test-user"), "テスト", 2, 2)
 
 		assert.NoError(t, err)
-		assert.Empty(t, res)
-		mockRepo.AssertExpectations(t)
+		assert.Equal(t, []model.CompanyBasicInfo{
+			{CompanyID: "1234567890126", CompanyName: "株式会社テストシステムズ"},
+			{CompanyID: "1234567890125", CompanyName: "株式会社ベストテスト"},
+		}, res)
 	})
 
-	t.Run("異常系:リポジトリでエラーが発生した場合", func(t *testing.T) {
-		mockRepo := new(mock.GBizInfoRepositoryMock)
+	t.Run("正常系:インデックスを検索できない場合はgBizINFOの結果をページングして返す", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		companyMock := new(mock.CompanyRepositoryMock)
+		companyMock.On("Search", testifymock.Anything, "てすと", 1, 1).Return(nil, errors.New("db error"))
+		gbizMock.On("SearchCompanies", testifymock.Anything, "テスト").Return([]model.CompanyBasicInfo{
+			{CompanyID: "1234567890123", CompanyName: "株式会社テスト"},
+			{CompanyID: "1234567890124", CompanyName: "テスト商事株式会社"},
+		}, nil)
 
-		ctx := context.Background()
-		keyword := "エラーケース"
-		expectedErr := errors.New("repository error")
+		uc := usecase.NewCompanyUsecase(gbizMock, new(mock.CompanyDetailRepositoryMock), companyMock)
+		res, err := uc.SearchCompanies(test.SetupContextContext("test-user"), "テスト", 1, 1)
 
-		mockRepo.On("SearchCompanies", testifymock.Anything, keyword).Return(nil, expectedErr)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CompanyBasicInfo{{CompanyID: "1234567890124", CompanyName: "テスト商事株式会社"}}, res)
+	})
+
+	t.Run("正常系:gBizINFOでの検索に失敗してもインデックスの結果があれば返す", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		companyMock := new(mock.CompanyRepositoryMock)
+		companyMock.On("Search", testifymock.Anything, "てすと", 20, 0).Return([]model.Companies{
+			{CorporateNumber: "1234567890123", Name: "株式会社テスト", NormalizedName: "てすと"},
+		}, nil)
+		companyMock.On("FindSearchQuery", testifymock.Anything, "てすと").Return(nil, nil)
+		gbizMock.On("SearchCompanies", testifymock.Anything, "テスト").Return(nil, errors.New("API returned non-200 status code: 429"))
+
+		uc := usecase.NewCompanyUsecase(gbizMock, new(mock.CompanyDetailRepositoryMock), companyMock)
+		res, err := uc.SearchCompanies(test.SetupContextContext("test-user"), "テスト", 0, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CompanyBasicInfo{{CompanyID: "1234567890123", CompanyName: "株式会社テスト"}}, res)
+	})
 
-		uc := usecase.NewCompanyUsecase(mockRepo)
+	t.Run("異常系:インデックスにもなくgBizINFOでの検索に失敗した場合", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		companyMock := new(mock.CompanyRepositoryMock)
+		expectedErr := errors.New("repository error")
+		companyMock.On("Search", testifymock.Anything, "えらーけーす", 20, 0).Return([]model.Companies{}, nil)
+		companyMock.On("FindSearchQuery", testifymock.Anything, "えらーけーす").Return(nil, nil)
+		gbizMock.On("SearchCompanies", testifymock.Anything, "エラーケース").Return(nil, expectedErr)
 
-		res, err := uc.SearchCompanies(ctx, keyword)
+		uc := usecase.NewCompanyUsecase(gbizMock, new(mock.CompanyDetailRepositoryMock), companyMock)
+		res, err := uc.SearchCompanies(test.SetupContextContext("test-user"), "エラーケース", 0, 0)
 
 		assert.Error(t, err)
 		assert.Nil(t, res)
 		assert.Equal(t, expectedErr, err)
-		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestCompanyUsecase_GetCompany(t *testing.T) {
+	const corporateNumber = "1234567890123"
+
+	t.Run("正常系:新しいキャッシュがある場合はgBizINFOに問い合わせない", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		detailMock := new(mock.CompanyDetailRepositoryMock)
+		cached := &model.CompanyDetails{CorporateNumber: corporateNumber, Name: "株式会社テスト", UpdatedAt: time.Now()}
+		detailMock.On("FindByCorporateNumber", testifymock.Anything, corporateNumber).Return(cached, nil)
+
+		uc := usecase.NewCompanyUsecase(gbizMock, detailMock, new(mock.CompanyRepositoryMock))
+		res, err := uc.GetCompany(test.SetupContextContext("test-user"), corporateNumber)
+
+		assert.NoError(t, err)
+		assert.Equal(t, cached, res)
+		gbizMock.AssertNotCalled(t, "GetCompany", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("正常系:キャッシュがない場合はgBizINFOから取得して保存する", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		detailMock := new(mock.CompanyDetailRepositoryMock)
+		fetched := &model.CompanyDetails{CorporateNumber: corporateNumber, Name: "株式会社テスト", EmployeeCount: 500}
+		detailMock.On("FindByCorporateNumber", testifymock.Anything, corporateNumber).Return(nil, nil)
+		gbizMock.On("GetCompany", testifymock.Anything, corporateNumber).Return(fetched, nil)
+		detailMock.On("Upsert", testifymock.Anything, fetched).Return(nil)
+
+		uc := usecase.NewCompanyUsecase(gbizMock, detailMock, new(mock.CompanyRepositoryMock))
+		res, err := uc.GetCompany(test.SetupContextContext("test-user"), corporateNumber)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fetched, res)
+		detailMock.AssertExpectations(t)
+	})
+
+	t.Run("正常系:gBizINFOへの問い合わせに失敗した場合は古いキャッシュを返す", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		detailMock := new(mock.CompanyDetailRepositoryMock)
+		cached := &model.CompanyDetails{CorporateNumber: corporateNumber, Name: "株式会社テスト", UpdatedAt: time.Now().Add(-60 * 24 * time.Hour)}
+		detailMock.On("FindByCorporateNumber", testifymock.Anything, corporateNumber).Return(cached, nil)
+		gbizMock.On("GetCompany", testifymock.Anything, corporateNumber).Return(nil, errors.New("API returned non-200 status code: 500"))
+
+		uc := usecase.NewCompanyUsecase(gbizMock, detailMock, new(mock.CompanyRepositoryMock))
+		res, err := uc.GetCompany(test.SetupContextContext("test-user"), corporateNumber)
+
+		assert.NoError(t, err)
+		assert.Equal(t, cached, res)
+		detailMock.AssertNotCalled(t, "Upsert", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:法人が存在しない場合", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		detailMock := new(mock.CompanyDetailRepositoryMock)
+		detailMock.On("FindByCorporateNumber", testifymock.Anything, corporateNumber).Return(nil, nil)
+		gbizMock.On("GetCompany", testifymock.Anything, corporateNumber).Return(nil, gbiz.ErrCorporationNotFound)
+
+		uc := usecase.NewCompanyUsecase(gbizMock, detailMock, new(mock.CompanyRepositoryMock))
+		res, err := uc.GetCompany(test.SetupContextContext("test-user"), corporateNumber)
+
+		assert.ErrorIs(t, err, usecase.ErrCompanyNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("異常系:法人番号の形式が不正な場合", func(t *testing.T) {
+		gbizMock := new(mock.GBizInfoRepositoryMock)
+		detailMock := new(mock.CompanyDetailRepositoryMock)
+
+		uc := usecase.NewCompanyUsecase(gbizMock, detailMock, new(mock.CompanyRepositoryMock))
+		res, err := uc.GetCompany(test.SetupContextContext("test-user"), "12345")
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidCorporateNumber)
+		assert.Nil(t, res)
+		detailMock.AssertNotCalled(t, "FindByCorporateNumber", testifymock.Anything, testifymock.Anything)
 	})
 }
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+)
+
+var (
+	// ErrExperienceEntryNotFound - 指定したエピソード・スキル・実績が存在しない、または他のユーザーのもの
+	ErrExperienceEntryNotFound = errors.New("experience entry not found")
+	// ErrInvalidExperienceEntry - 入力値が不正
+	ErrInvalidExperienceEntry = errors.New("invalid experience entry")
+)
+
+type ExperienceEntryUsecase interface {
+	ListEpisodes(ctx context.Context, profileID string) ([]model.ExperienceEpisodes, error)
+	CreateEpisode(ctx context.Context, profileID string, input model.InputEpisode) (*model.ExperienceEpisodes, error)
+	UpdateEpisode(ctx context.Context, episodeID string, input model.InputEpisode) (*model.ExperienceEpisodes, error)
+	DeleteEpisode(ctx context.Context, episodeID string) error
+	ListSkills(ctx context.Context, profileID string) ([]model.ExperienceSkills, error)
+	CreateSkill(ctx context.Context, profileID string, input model.InputSkill) (*model.ExperienceSkills, error)
+	UpdateSkill(ctx context.Context, skillID string, input model.InputSkill) (*model.ExperienceSkills, error)
+	DeleteSkill(ctx context.Context, skillID string) error
+	ListAchievements(ctx context.Context, profileID string) ([]model.ExperienceAchievements, error)
+	CreateAchievement(ctx context.Context, profileID string, input model.InputAchievement) (*model.ExperienceAchievements, error)
+	UpdateAchievement(ctx context.Context, achievementID string, input model.InputAchievement) (*model.ExperienceAchievements, error)
+	DeleteAchievement(ctx context.Context, achievementID string) error
+}
+
+type experienceEntryUsecase struct {
+	er repository.ExperienceEntryRepository
+}
+
+func NewExperienceEntryUsecase(r repository.ExperienceEntryRepository) ExperienceEntryUsecase {
+	return &experienceEntryUsecase{er: r}
+}
+
+func (u *experienceEntryUsecase) ListEpisodes(ctx context.Context, profileID string) ([]model.ExperienceEpisodes, error) {
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
+	}
+	episodes, err := u.er.ListEpisodes(ctx, profileID)
+	if err != nil {
+		return nil, profileError("failed to list episodes", err)
+	}
+	return episodes, nil
+}
+
+func (u *experienceEntryUsecase) CreateEpisode(ctx context.Context, profileID string, input model.InputEpisode) (*model.ExperienceEpisodes, error) {
+	if err := validateEpisode(input); err != nil {
+		return nil, err
+	}
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
+	}
+	episode, err := u.er.CreateEpisode(ctx, profileID, input)
+	if err != nil {
+		return nil, profileError("failed to create episode", err)
+	}
+	return &episode, nil
+}
+
+func (u *experienceEntryUsecase) UpdateEpisode(ctx context.Context, episodeID string, input model.InputEpisode) (*model.ExperienceEpisodes, error) {
+	if err := validateEpisode(input); err != nil {
+		return nil, err
+	}
+	if !isValidUUID(episodeID) {
+		return nil, ErrExperienceEntryNotFound
+	}
+	episode, err := u.er.UpdateEpisode(ctx, episodeID, input)
+	if err != nil {
+		return nil, entryError("failed to update episode", err)
+	}
+	return &episode, nil
+}
+
+func (u *experienceEntryUsecase) DeleteEpisode(ctx context.Context, episodeID string) error {
+	if !isValidUUID(episodeID) {
+		return ErrExperienceEntryNotFound
+	}
+	if err := u.er.DeleteEpisode(ctx, episodeID); err != nil {
+		return entryError("failed to delete episode", err)
+	}
+	return nil
+}
+
+func (u *experienceEntryUsecase) ListSkills(ctx context.Context, profileID string) ([]model.ExperienceSkills, error) {
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
+	}
+	skills, err := u.er.ListSkills(ctx, profileID)
+	if err != nil {
+		return nil, profileError("failed to list skills", err)
+	}
+	return skills, nil
+}
+
+func (u *experienceEntryUsecase) CreateSkill(ctx context.Context, profileID string, input model.InputSkill) (*model.ExperienceSkills, error) {
+	if err := validateSkill(&input); err != nil {
+		return nil, err
+	}
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
+	}
+	skill, err := u.er.CreateSkill(ctx, profileID, input)
+	if err != nil {
+		return nil, profileError("failed to create skill", err)
+	}
+	return &skill, nil
+}
+
+func (u *experienceEntryUsecase) UpdateSkill(ctx context.Context, skillID string, input model.InputSkill) (*model.ExperienceSkills, error) {
+	if err := validateSkill(&input); err != nil {
+		return nil, err
+	}
+	if !isValidUUID(skillID) {
+		return nil, ErrExperienceEntryNotFound
+	}
+	skill, err := u.er.UpdateSkill(ctx, skillID, input)
+	if err != nil {
+		return nil, entryError("failed to update skill", err)
+	}
+	return &skill, nil
+}
+
+func (u *experienceEntryUsecase) DeleteSkill(ctx context.Context, skillID string) error {
+	if !isValidUUID(skillID) {
+		return ErrExperienceEntryNotFound
+	}
+	if err := u.er.DeleteSkill(ctx, skillID); err != nil {
+		return entryError("failed to delete skill", err)
+	}
+	return nil
+}
+
+func (u *experienceEntryUsecase) ListAchievements(ctx context.Context, profileID string) ([]model.ExperienceAchievements, error) {
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
+	}
+	achievements, err := u.er.ListAchievements(ctx, profileID)
+	if err != nil {
+		return nil, profileError("failed to list achievements", err)
+	}
+	return achievements, nil
+}
+
+func (u *experienceEntryUsecase) CreateAchievement(ctx context.Context, profileID string, input model.InputAchievement) (*model.ExperienceAchievements, error) {
+	if err := validateAchievement(&input); err != nil {
+		return nil, err
+	}
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
+	}
+	achievement, err := u.er.CreateAchievement(ctx, profileID, input)
+	if err != nil {
+		return nil, profileError("failed to create achievement", err)
+	}
+	return &achievement, nil
+}
+
+func (u *experienceEntryUsecase) UpdateAchievement(ctx context.Context, achievementID string, input model.InputAchievement) (*model.ExperienceAchievements, error) {
+	if err := validateAchievement(&input); err != nil {
+		return nil, err
+	}
+	if !isValidUUID(achievementID) {
+		return nil, ErrExperienceEntryNotFound
+	}
+	achievement, err := u.er.UpdateAchievement(ctx, achievementID, input)
+	if err != nil {
+		return nil, entryError("failed to update achievement", err)
+	}
+	return &achievement, nil
+}
+
+func (u *experienceEntryUsecase) DeleteAchievement(ctx context.Context, achievementID string) error {
+	if !isValidUUID(achievementID) {
+		return ErrExperienceEntryNotFound
+	}
+	if err := u.er.DeleteAchievement(ctx, achievementID); err != nil {
+		return entryError("failed to delete achievement", err)
+	}
+	return nil
+}
+
+func validateEpisode(input model.InputEpisode) error {
+	if strings.TrimSpace(input.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidExperienceEntry)
+	}
+	return nil
+}
+
+// validateSkill - 習熟度が未指定の場合は中級として扱う
+func validateSkill(input *model.InputSkill) error {
+	if strings.TrimSpace(input.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidExperienceEntry)
+	}
+	if input.Level == "" {
+		input.Level = model.SkillLevelIntermediate
+	}
+	if !input.Level.IsValid() {
+		return fmt.Errorf("%w: level must be one of beginner, intermediate, advanced, expert", ErrInvalidExperienceEntry)
+	}
+	return nil
+}
+
+// validateAchievement - タグは前後の空白を除き、空のタグと重複を取り除く
+func validateAchievement(input *model.InputAchievement) error {
+	if strings.TrimSpace(input.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidExperienceEntry)
+	}
+	tags := make([]string, 0, len(input.Tags))
+	seen := make(map[string]bool, len(input.Tags))
+	for _, tag := range input.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	input.Tags = tags
+	return nil
+}
+
+// entryError - レコードが見つからない場合はErrExperienceEntryNotFoundに変換する
+func entryError(msg string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrExperienceEntryNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
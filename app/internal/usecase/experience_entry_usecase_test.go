@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+	appmock "es-api/app/test/mock/repository"
+)
+
+const (
+	testProfileID = "123e4567-e89b-12d3-a456-426614174000"
+	testEntryID   = "123e4567-e89b-12d3-a456-426614174100"
+)
+
+func TestExperienceEntryUsecase_CreateEpisode(t *testing.T) {
+	input := model.InputEpisode{
+		Title:  "学園祭実行委員",
+		Action: "来場者アンケートを導入した",
+	}
+
+	t.Run("正常系:エピソードを作成できる", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceEntryRepositoryMock)
+		mockRepo.On("CreateEpisode", testifymock.Anything, testProfileID, input).Return(model.ExperienceEpisodes{ID: testEntryID, Title: input.Title}, nil)
+
+		uc := usecase.NewExperienceEntryUsecase(mockRepo)
+		res, err := uc.CreateEpisode(context.Background(), testProfileID, input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, testEntryID, res.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:タイトルが空の場合", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceEntryRepositoryMock)
+
+		uc := usecase.NewExperienceEntryUsecase(mockRepo)
+		res, err := uc.CreateEpisode(context.Background(), testProfileID, model.InputEpisode{Title: " "})
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidExperienceEntry)
+		assert.Nil(t, res)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:プロフィールが存在しない場合", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceEntryRepositoryMock)
+		mockRepo.On("CreateEpisode", testifymock.Anything, testProfileID, input).Return(model.ExperienceEpisodes{}, gorm.ErrRecordNotFound)
+
+		uc := usecase.NewExperienceEntryUsecase(mockRepo)
+		res, err := uc.CreateEpisode(context.Background(), testProfileID, input)
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceProfileNotFound)
+		assert.Nil(t, res)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestExperienceEntryUsecase_CreateSkill(t *testing.T) {
+	t.Run("正常系:習熟度が未指定の場合は中級になる", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceEntryRepositoryMock)
+		expected := model.InputSkill{Name: "Go", Level: model.SkillLevelIntermediate}
+		mockRepo.On("CreateSkill", testifymock.Anything, testProfileID, expected).Return(model.ExperienceSkills{Name: "Go", Level: model.SkillLevelIntermediate}, nil)
+
+		uc := usecase.NewExperienceEntryUsecase(mockRepo)
+		res, err := uc.CreateSkill(context.Background(), testProfileID, model.InputSkill{Name: "Go"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.SkillLevelIntermediate, res.Level)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:習熟度が不正な場合", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceEntryRepositoryMock)
+
+		uc := usecase.NewExperienceEntryUsecase(mockRepo)
+		res, err := uc.CreateSkill(context.Background(), testProfileID, model.InputSkill{Name: "Go", Level: "master"})
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidExperienceEntry)
+		assert.Nil(t, res)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestExperienceEntryUsecase_UpdateAchievement(t *testing.T) {
+	t.Run("正常系:タグの空白と重複を取り除く", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceEntryRepositoryMock)
+		expected := model.InputAchievement{Title: "基本情報技術者", Tags: []string{"資格", "IT"}}
+		mockRepo.On("UpdateAchievement", testifymock.Anything, testEntryID, expected).Return(model.ExperienceAchievements{ID: testEntryID, Title: expected.Title, Tags: expected.Tags}, nil)
+
+		uc := usecase.NewExperienceEntryUsecase(mockRepo)
+		res, err := uc.UpdateAchievement(context.Background(), testEntryID, model.InputAchievement{
+			Title: "基本情報技術者",
+			Tags:  []string{" 資格 ", "IT", "", "資格"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"資格", "IT"}, res.Tags)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:実績が存在しない場合", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceEntryRepositoryMock)
+		input := model.InputAchievement{Title: "基本情報技術者", Tags: []string{}}
+		mockRepo.On("UpdateAchievement", testifymock.Anything, testEntryID, input).Return(model.ExperienceAchievements{}, gorm.ErrRecordNotFound)
+
+		uc := usecase.NewExperienceEntryUsecase(mockRepo)
+		res, err := uc.UpdateAchievement(context.Background(), testEntryID, input)
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceEntryNotFound)
+		assert.Nil(t, res)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestExperienceEntryUsecase_DeleteSkill(t *testing.T) {
+	t.Run("異常系:IDがUUIDでない場合はリポジトリを呼ばない", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceEntryRepositoryMock)
+
+		uc := usecase.NewExperienceEntryUsecase(mockRepo)
+		err := uc.DeleteSkill(context.Background(), "invalid")
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceEntryNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
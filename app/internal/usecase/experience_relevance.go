@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/repository/embedding"
+)
+
+const defaultExperienceTopK = 5
+
+// 経験情報のチャンクを出力する見出し
+const (
+	experienceSectionWork        = "職務経歴"
+	experienceSectionSelfPR      = "自己PR"
+	experienceSectionEpisode     = "エピソード"
+	experienceSectionAchievement = "実績"
+)
+
+// experienceChunk - 質問との関連度を測り、プロンプトに含めるかを決める経験情報の単位
+type experienceChunk struct {
+	section string
+	text    string
+}
+
+// experienceTopK - 1つの質問のプロンプトに含めるチャンクの数
+func experienceTopK() int {
+	if v, err := strconv.Atoi(os.Getenv("EXPERIENCE_TOP_K")); err == nil && v > 0 {
+		return v
+	}
+	return defaultExperienceTopK
+}
+
+// splitExperience - 職務経歴・自己PRは段落ごとに、エピソード・実績は1件ずつチャンクにする
+// スキルと将来の目標は短く、どの質問にも使うためチャンクにしない
+func splitExperience(experience *model.Experiences) []experienceChunk {
+	var chunks []experienceChunk
+	for _, p := range paragraphs(experience.Work) {
+		chunks = append(chunks, experienceChunk{section: experienceSectionWork, text: p})
+	}
+	for _, p := range paragraphs(experience.SelfPR) {
+		chunks = append(chunks, experienceChunk{section: experienceSectionSelfPR, text: p})
+	}
+	for _, e := range experience.Episodes {
+		chunks = append(chunks, experienceChunk{section: experienceSectionEpisode, text: formatEpisode(e)})
+	}
+	for _, a := range experience.Achievements {
+		chunks = append(chunks, experienceChunk{section: experienceSectionAchievement, text: formatAchievement(a)})
+	}
+	return chunks
+}
+
+// paragraphs - 空行で区切られた段落に分ける
+func paragraphs(text string) []string {
+	var result []string
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// selectExperienceChunks - 質問ごとに関連度の高いチャンクを最大experienceTopK件選ぶ。戻り値は質問と同じ順番
+// 埋め込みに失敗した場合は全てのチャンクを使う
+func (u *llmGenerateUsecase) selectExperienceChunks(ctx context.Context, chunks []experienceChunk, questions []string) [][]experienceChunk {
+	topK := experienceTopK()
+	selected := make([][]experienceChunk, len(questions))
+	if len(chunks) <= topK {
+		for i := range selected {
+			selected[i] = chunks
+		}
+		return selected
+	}
+
+	texts := make([]string, 0, len(questions)+len(chunks))
+	texts = append(texts, questions...)
+	for _, c := range chunks {
+		texts = append(texts, c.text)
+	}
+	vectors, err := u.embeddingRepo.Embed(ctx, texts)
+	if err == nil && len(vectors) != len(texts) {
+		err = fmt.Errorf("unexpected number of embeddings: got %d, want %d", len(vectors), len(texts))
+	}
+	if err != nil {
+		log.Printf("経験情報の埋め込みに失敗したため、全ての経験情報を使用します: %v", err)
+		for i := range selected {
+			selected[i] = chunks
+		}
+		return selected
+	}
+
+	chunkVectors := vectors[len(questions):]
+	for i := range questions {
+		selected[i] = topKChunks(vectors[i], chunkVectors, chunks, topK)
+	}
+	return selected
+}
+
+// topKChunks - 類似度の上位k件を、元の順番のまま返す
+func topKChunks(question []float32, chunkVectors [][]float32, chunks []experienceChunk, k int) []experienceChunk {
+	scores := make([]float64, len(chunks))
+	indexes := make([]int, len(chunks))
+	for i := range chunks {
+		indexes[i] = i
+		scores[i] = embedding.CosineSimilarity(question, chunkVectors[i])
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return scores[indexes[a]] > scores[indexes[b]]
+	})
+	indexes = indexes[:k]
+	sort.Ints(indexes)
+
+	selected := make([]experienceChunk, len(indexes))
+	for i, idx := range indexes {
+		selected[i] = chunks[idx]
+	}
+	return selected
+}
+
+func formatEpisode(e model.ExperienceEpisodes) string {
+	var sb strings.Builder
+	sb.WriteString("・")
+	sb.WriteString(e.Title)
+	if details := joinNonEmpty("／", e.Period, e.Role); details != "" {
+		sb.WriteString("（" + details + "）")
+	}
+	for _, field := range []struct{ label, value string }{
+		{"状況", e.Situation},
+		{"行動", e.Action},
+		{"結果", e.Result},
+		{"定量的な成果", e.Metrics},
+	} {
+		if field.value != "" {
+			sb.WriteString(fmt.Sprintf("\n  %s: %s", field.label, field.value))
+		}
+	}
+	return sb.String()
+}
+
+func formatAchievement(a model.ExperienceAchievements) string {
+	var sb strings.Builder
+	sb.WriteString("・")
+	sb.WriteString(a.Title)
+	if a.AchievedAt != "" {
+		sb.WriteString("（" + a.AchievedAt + "）")
+	}
+	if a.Description != "" {
+		sb.WriteString(": " + a.Description)
+	}
+	if len(a.Tags) > 0 {
+		sb.WriteString(" [" + strings.Join(a.Tags, ", ") + "]")
+	}
+	return sb.String()
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	nonEmpty := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
@@ -2,15 +2,27 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
 	"es-api/app/internal/entity/model"
 	repository "es-api/app/internal/repository/db"
 )
 
+// ErrExperienceProfileNotFound - 指定したプロフィールが存在しない、または他のユーザーのもの
+var ErrExperienceProfileNotFound = errors.New("experience profile not found")
+
 type ExperienceUsecase interface {
 	GetExperienceByUserID(ctx context.Context) (*model.Experiences, error)
 	PostExperience(ctx context.Context, experience model.InputExperience) (*model.Experiences, error)
+	ListProfiles(ctx context.Context) ([]model.Experiences, error)
+	CreateProfile(ctx context.Context, experience model.InputExperience) (*model.Experiences, error)
+	UpdateProfile(ctx context.Context, profileID string, experience model.InputExperience) (*model.Experiences, error)
+	DeleteProfile(ctx context.Context, profileID string) error
+	SetDefaultProfile(ctx context.Context, profileID string) (*model.Experiences, error)
 }
 
 type experienceUsecase struct {
@@ -52,3 +64,86 @@ func (u *experienceUsecase) PostExperience(ctx context.Context, experience model
 
 	return &experiences, nil
 }
+
+func (u *experienceUsecase) ListProfiles(ctx context.Context) ([]model.Experiences, error) {
+	experiences, err := u.er.ListExperiences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiences: %w", err)
+	}
+	if experiences == nil {
+		experiences = []model.Experiences{}
+	}
+
+	return experiences, nil
+}
+
+// CreateProfile - 最初のプロフィールは常にデフォルトになる
+func (u *experienceUsecase) CreateProfile(ctx context.Context, experience model.InputExperience) (*model.Experiences, error) {
+	exists, err := u.er.FindExperienceByUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find experience by user ID: %w", err)
+	}
+
+	created, err := u.er.CreateExperience(ctx, experience, experience.IsDefault || !exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create experience: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (u *experienceUsecase) UpdateProfile(ctx context.Context, profileID string, experience model.InputExperience) (*model.Experiences, error) {
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
+	}
+
+	updated, err := u.er.UpdateExperience(ctx, profileID, experience)
+	if err != nil {
+		return nil, profileError("failed to update experience", err)
+	}
+
+	if experience.IsDefault && !updated.IsDefault {
+		return u.SetDefaultProfile(ctx, profileID)
+	}
+
+	return &updated, nil
+}
+
+func (u *experienceUsecase) DeleteProfile(ctx context.Context, profileID string) error {
+	if !isValidUUID(profileID) {
+		return ErrExperienceProfileNotFound
+	}
+
+	if err := u.er.DeleteExperience(ctx, profileID); err != nil {
+		return profileError("failed to delete experience", err)
+	}
+
+	return nil
+}
+
+func (u *experienceUsecase) SetDefaultProfile(ctx context.Context, profileID string) (*model.Experiences, error) {
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
+	}
+
+	experience, err := u.er.SetDefaultExperience(ctx, profileID)
+	if err != nil {
+		return nil, profileError("failed to set default experience", err)
+	}
+
+	return &experience, nil
+}
+
+// isValidUUID - IDがUUID形式かどうか。形式が不正なIDはDBに問い合わせずに存在しない扱いにする
+func isValidUUID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+// profileError - レコードが見つからない場合はErrExperienceProfileNotFoundに変換する
+func profileError(msg string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrExperienceProfileNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
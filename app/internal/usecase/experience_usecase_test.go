@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 
 	"es-api/app/internal/entity/model"
 	"es-api/app/internal/usecase"
@@ -134,3 +135,105 @@ func TestExperienceUsecase_PostExperience(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestExperienceUsecase_CreateProfile(t *testing.T) {
+	inputExperience := model.InputExperience{
+		Name: "IT業界向け",
+		Work: "test work",
+	}
+
+	t.Run("正常系:最初のプロフィールはデフォルトになる", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceRepositoryMock)
+		created := model.Experiences{ID: "test-id-1", Name: inputExperience.Name, IsDefault: true}
+		mockRepo.On("FindExperienceByUserID", testifymock.Anything).Return(false, nil)
+		mockRepo.On("CreateExperience", testifymock.Anything, inputExperience, true).Return(created, nil)
+
+		uc := usecase.NewExperienceUsecase(mockRepo)
+		res, err := uc.CreateProfile(context.Background(), inputExperience)
+
+		assert.NoError(t, err)
+		assert.True(t, res.IsDefault)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系:2つ目以降のプロフィールはデフォルトにならない", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceRepositoryMock)
+		created := model.Experiences{ID: "test-id-2", Name: inputExperience.Name}
+		mockRepo.On("FindExperienceByUserID", testifymock.Anything).Return(true, nil)
+		mockRepo.On("CreateExperience", testifymock.Anything, inputExperience, false).Return(created, nil)
+
+		uc := usecase.NewExperienceUsecase(mockRepo)
+		res, err := uc.CreateProfile(context.Background(), inputExperience)
+
+		assert.NoError(t, err)
+		assert.False(t, res.IsDefault)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestExperienceUsecase_UpdateProfile(t *testing.T) {
+	const profileID = "123e4567-e89b-12d3-a456-426614174000"
+	inputExperience := model.InputExperience{Work: "updated work"}
+
+	t.Run("正常系:プロフィールを更新できる", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceRepositoryMock)
+		updated := model.Experiences{ID: profileID, Work: inputExperience.Work}
+		mockRepo.On("UpdateExperience", testifymock.Anything, profileID, inputExperience).Return(updated, nil)
+
+		uc := usecase.NewExperienceUsecase(mockRepo)
+		res, err := uc.UpdateProfile(context.Background(), profileID, inputExperience)
+
+		assert.NoError(t, err)
+		assert.Equal(t, inputExperience.Work, res.Work)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:プロフィールが存在しない場合", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceRepositoryMock)
+		mockRepo.On("UpdateExperience", testifymock.Anything, profileID, inputExperience).Return(model.Experiences{}, gorm.ErrRecordNotFound)
+
+		uc := usecase.NewExperienceUsecase(mockRepo)
+		res, err := uc.UpdateProfile(context.Background(), profileID, inputExperience)
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceProfileNotFound)
+		assert.Nil(t, res)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:IDがUUIDでない場合はリポジトリを呼ばない", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceRepositoryMock)
+
+		uc := usecase.NewExperienceUsecase(mockRepo)
+		res, err := uc.UpdateProfile(context.Background(), "invalid", inputExperience)
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceProfileNotFound)
+		assert.Nil(t, res)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestExperienceUsecase_DeleteProfile(t *testing.T) {
+	const profileID = "123e4567-e89b-12d3-a456-426614174000"
+
+	t.Run("正常系:プロフィールを削除できる", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceRepositoryMock)
+		mockRepo.On("DeleteExperience", testifymock.Anything, profileID).Return(nil)
+
+		uc := usecase.NewExperienceUsecase(mockRepo)
+		err := uc.DeleteProfile(context.Background(), profileID)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:プロフィールが存在しない場合", func(t *testing.T) {
+		mockRepo := new(appmock.ExperienceRepositoryMock)
+		mockRepo.On("DeleteExperience", testifymock.Anything, profileID).Return(gorm.ErrRecordNotFound)
+
+		uc := usecase.NewExperienceUsecase(mockRepo)
+		err := uc.DeleteProfile(context.Background(), profileID)
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceProfileNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
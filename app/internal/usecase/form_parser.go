@@ -0,0 +1,435 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"es-api/app/internal/entity/model"
+)
+
+const (
+	// maxContextBlocks - 入力欄ごとに保持する直前のテキストの数
+	maxContextBlocks = 3
+	// maxBlockRunes - テキスト1つあたりの最大文字数
+	maxBlockRunes = 300
+)
+
+// formField - HTMLから見つけた回答用の入力欄
+type formField struct {
+	FieldType   model.QuestionFieldType
+	Name        string
+	ID          string
+	Label       string   // label要素またはaria-label属性のテキスト
+	Placeholder string   // placeholder属性
+	Heading     string   // 直前の見出し(h1〜h6、legend)
+	Context     []string // 前の入力欄からこの入力欄までにあるテキスト(見出しとラベルを除く)
+	MaxLength   int      // maxlength属性。ない場合は0
+}
+
+// parsedForm - ESのHTMLを解析した結果
+type parsedForm struct {
+	fields   []formField
+	stripped string // script・style・コメントと不要な属性を取り除いたHTML
+	hash     string // 入力欄の中身も取り除いたHTMLのハッシュ。設問の保存のキーにする
+}
+
+// skippedElements - 中身を解析しない要素
+var skippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Iframe:   true,
+	atom.Head:     true,
+}
+
+// blockElements - テキストの区切りとして扱う要素
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Ul: true, atom.Ol: true,
+	atom.Tr: true, atom.Td: true, atom.Th: true, atom.Dt: true, atom.Dd: true, atom.Dl: true,
+	atom.Section: true, atom.Article: true, atom.Fieldset: true, atom.Form: true, atom.Table: true,
+	atom.Label: true, atom.Header: true, atom.Footer: true, atom.Main: true, atom.Caption: true,
+	atom.Pre: true, atom.Blockquote: true, atom.Hr: true,
+}
+
+// headingElements - 見出しとして扱う要素
+var headingElements = map[atom.Atom]bool{
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true, atom.Legend: true,
+}
+
+// textInputTypes - 回答欄として扱うinput要素のtype
+var textInputTypes = map[string]bool{
+	"":       true,
+	"text":   true,
+	"search": true,
+}
+
+// keptAttributes - LLMに渡すHTMLに残す属性
+var keptAttributes = map[string]bool{
+	"name": true, "id": true, "for": true, "type": true,
+	"maxlength": true, "placeholder": true, "aria-label": true,
+}
+
+// parseForm - ESのHTMLから回答用の入力欄(textareaと単一行のinput)と、その周辺のテキストを取り出す
+func parseForm(htmlText string) (*parsedForm, error) {
+	doc, err := html.Parse(strings.NewReader(htmlText))
+	if err != nil {
+		return nil, fmt.Errorf("HTMLの解析に失敗: %w", err)
+	}
+
+	p := &formParser{
+		labelsByFor:  make(map[string]string),
+		labelsByNode: make(map[*html.Node]string),
+	}
+	p.collectLabels(doc)
+	p.walk(doc)
+
+	var b strings.Builder
+	writeStrippedHTML(&b, doc, false)
+	var normalized strings.Builder
+	writeStrippedHTML(&normalized, doc, true)
+
+	return &parsedForm{fields: p.fields, stripped: b.String(), hash: hashHTML(normalized.String())}, nil
+}
+
+type formParser struct {
+	labelsByFor  map[string]string
+	labelsByNode map[*html.Node]string
+
+	fields  []formField
+	heading string
+	blocks  []string
+	text    strings.Builder
+}
+
+// collectLabels - label要素のテキストを、for属性の値と内包する入力欄ごとに集める
+func (p *formParser) collectLabels(n *html.Node) {
+	if n.Type == html.ElementNode && skippedElements[n.DataAtom] {
+		return
+	}
+	if n.Type == html.ElementNode && n.DataAtom == atom.Label {
+		text := nodeText(n)
+		if text == "" {
+			return
+		}
+		if id := attr(n, "for"); id != "" {
+			p.labelsByFor[id] = text
+		}
+		forEachField(n, func(field *html.Node) {
+			p.labelsByNode[field] = text
+		})
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.collectLabels(c)
+	}
+}
+
+func (p *formParser) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		p.text.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if skippedElements[n.DataAtom] {
+			return
+		}
+		if headingElements[n.DataAtom] {
+			p.flush()
+			p.heading = truncateRunes(nodeText(n), maxBlockRunes)
+			return
+		}
+		if isFormControl(n) {
+			p.flush()
+			if field, ok := p.field(n); ok {
+				p.fields = append(p.fields, field)
+			}
+			// 入力欄より前のテキストは、その入力欄のものとして扱う
+			p.blocks = nil
+			return
+		}
+		if blockElements[n.DataAtom] {
+			p.flush()
+			defer p.flush()
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.walk(c)
+	}
+}
+
+// flush - 溜まっているテキストを1つのまとまりとして確定する
+func (p *formParser) flush() {
+	text := normalizeSpace(p.text.String())
+	p.text.Reset()
+	if text == "" {
+		return
+	}
+	p.blocks = append(p.blocks, truncateRunes(text, maxBlockRunes))
+}
+
+// field - 回答用の入力欄であれば、その情報を返す。ラジオボタンやセレクトボックスなどは対象外
+func (p *formParser) field(n *html.Node) (formField, bool) {
+	var fieldType model.QuestionFieldType
+	switch {
+	case n.DataAtom == atom.Textarea:
+		fieldType = model.QuestionFieldTypeTextarea
+	case n.DataAtom == atom.Input && textInputTypes[strings.ToLower(attr(n, "type"))]:
+		fieldType = model.QuestionFieldTypeText
+	default:
+		return formField{}, false
+	}
+
+	field := formField{
+		FieldType:   fieldType,
+		Name:        attr(n, "name"),
+		ID:          attr(n, "id"),
+		Placeholder: normalizeSpace(attr(n, "placeholder")),
+		Heading:     p.heading,
+	}
+	if maxLength, err := strconv.Atoi(attr(n, "maxlength")); err == nil && maxLength > 0 {
+		field.MaxLength = maxLength
+	}
+
+	switch {
+	case field.ID != "" && p.labelsByFor[field.ID] != "":
+		field.Label = p.labelsByFor[field.ID]
+	case p.labelsByNode[n] != "":
+		field.Label = p.labelsByNode[n]
+	default:
+		field.Label = normalizeSpace(attr(n, "aria-label"))
+	}
+
+	for _, block := range p.blocks {
+		if block != field.Label {
+			field.Context = append(field.Context, block)
+		}
+	}
+	if len(field.Context) > maxContextBlocks {
+		field.Context = field.Context[len(field.Context)-maxContextBlocks:]
+	}
+	return field, true
+}
+
+// questionNumberPattern - 質問文の先頭の質問番号(Q1、1.、①、設問1など)
+var questionNumberPattern = regexp.MustCompile(`^(?:[QＱ][0-9０-９]+|設問\s*[0-9０-９]+|[0-9０-９]+\s*[.．、)）]|[①-⑳])\s*[.．:：、)）]?\s*`)
+
+// requirementPattern - 「必須」「任意」などの入力要件の表示
+var requirementPattern = regexp.MustCompile(`[\[［【(（]\s*(?:必須|任意)\s*[\]］】)）]|^(?:必須|任意)(?:\s|$)|\s(?:必須|任意)$`)
+
+// questionWordingPattern - 設問らしい言い回し。氏名や大学名などの基本情報の入力欄と区別する
+var questionWordingPattern = regexp.MustCompile(`[?？]|ください|下さい|教えて|について|ですか|ますか|とは`)
+
+// inputInstructionPattern - 「〜を入力してください」などの入力方法の指示。設問らしい言い回しとはみなさない
+var inputInstructionPattern = regexp.MustCompile(`(?:を|で)?(?:入力|選択|記入)(?:して)?(?:ください|下さい)`)
+
+// cleanQuestionText - 質問番号と入力要件の表示を取り除く
+func cleanQuestionText(text string) string {
+	text = requirementPattern.ReplaceAllString(text, "")
+	text = questionNumberPattern.ReplaceAllString(strings.TrimSpace(text), "")
+	return normalizeSpace(text)
+}
+
+// directQuestions - 全ての入力欄について質問文が一意に決まる単純なフォームであれば、LLMを使わずに設問を返す
+// ラベル(aria-labelを含む)があればそれを、なければ直前のテキストが1つだけの場合にそれを質問文とする
+// 単一行の入力欄は、文字数制限の指定か設問らしい言い回し(「〜を入力してください」などの入力方法の指示を除く)がある場合のみ設問とみなす
+// 氏名や電話番号などの基本情報の入力欄が混ざったフォームは、設問かどうかの判断をLLMに任せる
+func (f *parsedForm) directQuestions() ([]model.ExtractedQuestion, bool) {
+	if len(f.fields) == 0 {
+		return nil, false
+	}
+
+	questions := make([]model.ExtractedQuestion, 0, len(f.fields))
+	for _, field := range f.fields {
+		text := field.Label
+		if text == "" && len(field.Context) == 1 {
+			text = field.Context[0]
+		}
+		question := cleanQuestionText(text)
+		if question == "" {
+			return nil, false
+		}
+
+		charLimit := parseCharLimit(question)
+		if charLimit == 0 {
+			charLimit = parseCharLimit(field.Placeholder)
+		}
+		// maxlengthは電話番号などにも付くので、設問かどうかの判断には使わない
+		if field.FieldType != model.QuestionFieldTypeTextarea && charLimit == 0 && !hasQuestionWording(question) {
+			return nil, false
+		}
+		if charLimit == 0 {
+			charLimit = field.MaxLength
+		}
+
+		questions = append(questions, model.ExtractedQuestion{
+			Question:  question,
+			CharLimit: charLimit,
+			FieldType: field.FieldType,
+			Name:      field.Name,
+			ID:        field.ID,
+		})
+	}
+	return questions, true
+}
+
+// hasQuestionWording - 入力方法の指示を除いた質問文に、設問らしい言い回しがあるか
+func hasQuestionWording(question string) bool {
+	return questionWordingPattern.MatchString(inputInstructionPattern.ReplaceAllString(question, ""))
+}
+
+// describe - LLMに渡すための、入力欄ごとの情報をまとめたテキスト
+func (f *parsedForm) describe() string {
+	var b strings.Builder
+	for i, field := range f.fields {
+		fmt.Fprintf(&b, "[入力欄%d] fieldType=%s name=%q id=%q", i+1, field.FieldType, field.Name, field.ID)
+		if field.MaxLength > 0 {
+			fmt.Fprintf(&b, " maxlength=%d", field.MaxLength)
+		}
+		b.WriteString("\n")
+		if field.Heading != "" {
+			fmt.Fprintf(&b, "見出し: %s\n", field.Heading)
+		}
+		if field.Label != "" {
+			fmt.Fprintf(&b, "ラベル: %s\n", field.Label)
+		}
+		if field.Placeholder != "" {
+			fmt.Fprintf(&b, "プレースホルダー: %s\n", field.Placeholder)
+		}
+		for _, text := range field.Context {
+			fmt.Fprintf(&b, "直前のテキスト: %s\n", text)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// writeStrippedHTML - script・style・コメントと、質問の抽出に不要な属性を取り除いたHTMLを書き出す
+// omitValuesがtrueの場合は、入力済みの回答で結果が変わらないようにtextareaの中身も取り除く
+func writeStrippedHTML(b *strings.Builder, n *html.Node, omitValues bool) {
+	switch n.Type {
+	case html.CommentNode, html.DoctypeNode:
+		return
+	case html.TextNode:
+		if text := normalizeSpace(n.Data); text != "" {
+			b.WriteString(html.EscapeString(text))
+		}
+		return
+	case html.ElementNode:
+		if skippedElements[n.DataAtom] {
+			return
+		}
+		b.WriteString("<" + n.Data)
+		for _, a := range n.Attr {
+			if keptAttributes[a.Key] {
+				fmt.Fprintf(b, " %s=\"%s\"", a.Key, html.EscapeString(a.Val))
+			}
+		}
+		b.WriteString(">")
+		if !omitValues || n.DataAtom != atom.Textarea {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				writeStrippedHTML(b, c, omitValues)
+			}
+		}
+		if !isVoidElement(n.DataAtom) {
+			b.WriteString("</" + n.Data + ">")
+		}
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeStrippedHTML(b, c, omitValues)
+	}
+}
+
+// hashHTML - 正規化したHTMLのSHA-256を16進数で返す
+func hashHTML(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// isFormControl - 入力欄の要素かどうか。中身のテキスト(初期値や選択肢)は周辺のテキストに含めない
+func isFormControl(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Textarea, atom.Select, atom.Button:
+		return true
+	case atom.Input:
+		return strings.ToLower(attr(n, "type")) != "hidden"
+	}
+	return false
+}
+
+func isVoidElement(a atom.Atom) bool {
+	switch a {
+	case atom.Br, atom.Hr, atom.Img, atom.Input, atom.Meta, atom.Link, atom.Col, atom.Wbr, atom.Source, atom.Area, atom.Base, atom.Embed, atom.Track:
+		return true
+	}
+	return false
+}
+
+// forEachField - 子孫の入力欄ごとにfnを呼ぶ
+func forEachField(n *html.Node, fn func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if isFormControl(c) {
+			fn(c)
+			continue
+		}
+		forEachField(c, fn)
+	}
+}
+
+// nodeText - 要素内のテキスト。入力欄とscript・styleの中身は含めない
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			return
+		}
+		if n.Type == html.ElementNode && (skippedElements[n.DataAtom] || isFormControl(n)) {
+			return
+		}
+		if n.Type == html.ElementNode && blockElements[n.DataAtom] {
+			b.WriteString(" ")
+			defer b.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+	return normalizeSpace(b.String())
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// normalizeSpace - 改行や連続する空白(全角スペースを含む)を1つの半角スペースにまとめる
+func normalizeSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func truncateRunes(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max]) + "…"
+}
@@ -0,0 +1,136 @@
+package usecase_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/test"
+)
+
+func TestLLMGenerateUsecase_FormParser(t *testing.T) {
+	t.Run("正常系:ラベルから質問文が決まるフォームはLLMを使わずに抽出する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機を教えてください。（400字以内）"))).Return(model.GeminiResponse{Text: strings.Repeat("あ", 380)}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("学生時代に力を入れたこと"))).Return(model.GeminiResponse{Text: strings.Repeat("い", 190)}, nil)
+		req := llmGenerateRequest
+		req.HTML = `<html><head><style>.q{color:red}</style></head><body>
+			<script>var question = "スクリプト内の文字列";</script>
+			<h2>エントリーシート</h2>
+			<form>
+				<input type="hidden" name="token" value="xxx">
+				<label for="q1">Q1. 志望動機を教えてください。（400字以内）<span>【必須】</span></label>
+				<textarea id="q1" name="answer_1">初期値</textarea>
+				<p>学生時代に力を入れたこと</p>
+				<textarea name="answer_2" maxlength="200"></textarea>
+			</form>
+		</body></html>`
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.LLMGeneratedResponse{
+			{Question: "志望動機を教えてください。（400字以内）", Answer: strings.Repeat("あ", 380), Status: model.LLMAnswerStatusSucceeded, CharLimit: 400, CharCount: 380, FieldType: model.QuestionFieldTypeTextarea, FieldName: "answer_1", FieldID: "q1"},
+			{Question: "学生時代に力を入れたこと", Answer: strings.Repeat("い", 190), Status: model.LLMAnswerStatusSucceeded, CharLimit: 200, CharCount: 190, FieldType: model.QuestionFieldTypeTextarea, FieldName: "answer_2"},
+		}, res)
+		m.gemini.AssertNotCalled(t, "GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput))
+	})
+
+	t.Run("正常系:氏名などの基本情報の入力欄があるフォームは設問かどうかをLLMで判断する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return strings.Contains(input.Text, "以下の入力欄を分析してください") &&
+				strings.Contains(input.Text, "氏名") &&
+				strings.Contains(input.Text, "大学名")
+		})).Return(model.GeminiResponse{Text: questionsJSON("志望動機を教えてください")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機を教えてください"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		req := llmGenerateRequest
+		req.HTML = `<form>
+			<label for="name">氏名</label><input type="text" id="name" name="name">
+			<label for="univ">大学名</label><input type="text" id="univ" name="university" maxlength="50">
+			<label for="q1">志望動機を教えてください</label><textarea id="q1" name="answer_1"></textarea>
+		</form>`
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "志望動機を教えてください", res[0].Question)
+		}
+	})
+
+	t.Run("正常系:「〜を入力してください」という基本情報の入力欄があるフォームは設問かどうかをLLMで判断する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return strings.Contains(input.Text, "以下の入力欄を分析してください") &&
+				strings.Contains(input.Text, "氏名を入力してください") &&
+				strings.Contains(input.Text, "メールアドレスを入力してください")
+		})).Return(model.GeminiResponse{Text: questionsJSON("志望動機を教えてください")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機を教えてください"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		req := llmGenerateRequest
+		req.HTML = `<form>
+			<label for="name">氏名を入力してください</label><input type="text" id="name" name="name">
+			<label for="mail">メールアドレスを入力してください</label><input type="text" id="mail" name="email">
+			<label for="q1">志望動機を教えてください</label><input type="text" id="q1" name="answer_1">
+		</form>`
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "志望動機を教えてください", res[0].Question)
+		}
+		m.gemini.AssertNotCalled(t, "GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("氏名を入力してください")))
+	})
+
+	t.Run("正常系:質問文が決まらない入力欄は周辺の情報をLLMに渡す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return strings.Contains(input.Text, "以下の入力欄を分析してください") &&
+				strings.Contains(input.Text, `name="pr"`) &&
+				strings.Contains(input.Text, "見出し: 設問2") &&
+				strings.Contains(input.Text, "プレースホルダー: 300字以内で入力") &&
+				strings.Contains(input.Text, "直前のテキスト: あなたの強みを教えてください") &&
+				!strings.Contains(input.Text, "スクリプト内の文字列") &&
+				!strings.Contains(input.Text, "<textarea")
+		})).Return(model.GeminiResponse{Text: questionsJSON("あなたの強みを教えてください")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("あなたの強みを教えてください"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		req := llmGenerateRequest
+		req.HTML = `<div>
+			<script>var question = "スクリプト内の文字列";</script>
+			<h3>設問2</h3>
+			<p>あなたの強みを教えてください</p>
+			<p>具体的なエピソードを交えて記入してください</p>
+			<textarea name="pr" placeholder="300字以内で入力"></textarea>
+		</div>`
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("正常系:入力欄が見つからない場合はscriptとstyleを除いたHTMLをLLMに渡す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return strings.Contains(input.Text, "以下のHTMLを分析してください") &&
+				strings.Contains(input.Text, `<div id="editor">`) &&
+				strings.Contains(input.Text, "志望動機") &&
+				!strings.Contains(input.Text, "スクリプト内の文字列") &&
+				!strings.Contains(input.Text, "color:red") &&
+				!strings.Contains(input.Text, "onclick")
+		})).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		req := llmGenerateRequest
+		req.HTML = `<style>.q{color:red}</style><script>var question = "スクリプト内の文字列";</script>
+			<p>志望動機</p><div id="editor" contenteditable="true" onclick="focus()"></div>`
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+	})
+}
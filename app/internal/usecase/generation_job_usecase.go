@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+)
+
+// ErrGenerationJobFinished - 終了済みのジョブをキャンセルしようとした
+var ErrGenerationJobFinished = errors.New("generation job has already finished")
+
+type GenerationJobUsecase interface {
+	CreateJob(ctx context.Context, req model.LLMGenerateRequest) (*model.GenerationJobs, error)
+	GetJob(ctx context.Context, id string) (*model.GenerationJobs, error)
+	CancelJob(ctx context.Context, id string) (*model.GenerationJobs, error)
+}
+
+type generationJobUsecase struct {
+	jr repository.GenerationJobRepository
+}
+
+func NewGenerationJobUsecase(r repository.GenerationJobRepository) GenerationJobUsecase {
+	return &generationJobUsecase{jr: r}
+}
+
+// CreateJob - 生成ジョブを実行待ちとして登録する。生成はGenerationJobWorkerが行う
+func (u *generationJobUsecase) CreateJob(ctx context.Context, req model.LLMGenerateRequest) (*model.GenerationJobs, error) {
+	job := &model.GenerationJobs{
+		Request:     req,
+		CompanyID:   req.CompanyID,
+		CompanyName: req.CompanyName,
+		Progress:    []model.GenerationJobQuestion{},
+	}
+	if err := u.jr.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create generation job: %w", err)
+	}
+	return job, nil
+}
+
+// GetJob - ログインユーザーのジョブを返す。存在しない場合はnilを返す
+func (u *generationJobUsecase) GetJob(ctx context.Context, id string) (*model.GenerationJobs, error) {
+	if !isValidUUID(id) {
+		return nil, nil
+	}
+
+	job, err := u.jr.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find generation job: %w", err)
+	}
+	return job, nil
+}
+
+// CancelJob - ログインユーザーのジョブをキャンセルする。存在しない場合はnilを、終了済みの場合はErrGenerationJobFinishedを返す
+// 実行待ちのジョブはすぐにキャンセル済みになり、実行中のジョブはワーカーが中断した時点でキャンセル済みになる
+func (u *generationJobUsecase) CancelJob(ctx context.Context, id string) (*model.GenerationJobs, error) {
+	if !isValidUUID(id) {
+		return nil, nil
+	}
+
+	job, err := u.jr.RequestCancel(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel generation job: %w", err)
+	}
+	if job != nil && job.Status.IsFinished() && !job.CancelRequested {
+		return nil, ErrGenerationJobFinished
+	}
+	return job, nil
+}
@@ -0,0 +1,290 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+	"es-api/app/test"
+	mock "es-api/app/test/mock/repository"
+	usecasemock "es-api/app/test/mock/usecase"
+)
+
+const generationJobID = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestGenerationJobUsecase_CreateJob(t *testing.T) {
+	t.Run("正常系:リクエストを実行待ちのジョブとして登録する", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		req := model.LLMGenerateRequest{CompanyID: "1234567890123", CompanyName: "株式会社テスト", HTML: "<form></form>"}
+		mockRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(job *model.GenerationJobs) bool {
+			return job.Request.HTML == req.HTML && job.CompanyID == req.CompanyID && job.CompanyName == req.CompanyName
+		})).Return(nil)
+
+		uc := usecase.NewGenerationJobUsecase(mockRepo)
+		job, err := uc.CreateJob(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, req, job.Request)
+		assert.Empty(t, job.Progress)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:リポジトリでエラーが発生した場合", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(errors.New("db error"))
+
+		uc := usecase.NewGenerationJobUsecase(mockRepo)
+		job, err := uc.CreateJob(test.SetupContextContext("test-user"), model.LLMGenerateRequest{})
+
+		assert.Error(t, err)
+		assert.Nil(t, job)
+	})
+}
+
+func TestGenerationJobUsecase_GetJob(t *testing.T) {
+	t.Run("正常系:ジョブが存在する場合", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		expected := &model.GenerationJobs{ID: generationJobID, Status: model.GenerationJobStatusRunning}
+		mockRepo.On("FindByID", testifymock.Anything, generationJobID).Return(expected, nil)
+
+		uc := usecase.NewGenerationJobUsecase(mockRepo)
+		job, err := uc.GetJob(test.SetupContextContext("test-user"), generationJobID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, job)
+	})
+
+	t.Run("異常系:IDがUUIDでない場合は見つからない扱いにする", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+
+		uc := usecase.NewGenerationJobUsecase(mockRepo)
+		job, err := uc.GetJob(test.SetupContextContext("test-user"), "invalid-id")
+
+		assert.NoError(t, err)
+		assert.Nil(t, job)
+		mockRepo.AssertNotCalled(t, "FindByID", testifymock.Anything, testifymock.Anything)
+	})
+}
+
+func TestGenerationJobUsecase_CancelJob(t *testing.T) {
+	t.Run("正常系:実行中のジョブのキャンセルを要求できる", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		expected := &model.GenerationJobs{ID: generationJobID, Status: model.GenerationJobStatusRunning, CancelRequested: true}
+		mockRepo.On("RequestCancel", testifymock.Anything, generationJobID).Return(expected, nil)
+
+		uc := usecase.NewGenerationJobUsecase(mockRepo)
+		job, err := uc.CancelJob(test.SetupContextContext("test-user"), generationJobID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, job)
+	})
+
+	t.Run("異常系:終了済みのジョブはキャンセルできない", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockRepo.On("RequestCancel", testifymock.Anything, generationJobID).Return(&model.GenerationJobs{ID: generationJobID, Status: model.GenerationJobStatusSucceeded}, nil)
+
+		uc := usecase.NewGenerationJobUsecase(mockRepo)
+		job, err := uc.CancelJob(test.SetupContextContext("test-user"), generationJobID)
+
+		assert.ErrorIs(t, err, usecase.ErrGenerationJobFinished)
+		assert.Nil(t, job)
+	})
+
+	t.Run("異常系:ジョブが存在しない場合", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockRepo.On("RequestCancel", testifymock.Anything, generationJobID).Return(nil, nil)
+
+		uc := usecase.NewGenerationJobUsecase(mockRepo)
+		job, err := uc.CancelJob(test.SetupContextContext("test-user"), generationJobID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, job)
+	})
+}
+
+func TestGenerationJobWorker_ProcessNext(t *testing.T) {
+	job := func() *model.GenerationJobs {
+		return &model.GenerationJobs{
+			ID:       generationJobID,
+			UserID:   "test-user",
+			Status:   model.GenerationJobStatusRunning,
+			Attempts: 1,
+			Request:  model.LLMGenerateRequest{CompanyID: "1234567890123", CompanyName: "株式会社テスト", HTML: "<form></form>"},
+		}
+	}
+
+	t.Run("正常系:ジョブを実行して進捗と結果を保存する", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockGenerator := new(usecasemock.LLMGenerateUsecaseMock)
+		answers := []model.LLMGeneratedResponse{{Question: "志望動機", Answer: "回答"}}
+		mockRepo.On("RequeueStale", testifymock.Anything, testifymock.Anything, 3).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", testifymock.Anything).Return(job(), nil)
+		mockGenerator.On("LLMGenerateBackground", testifymock.MatchedBy(func(ctx context.Context) bool {
+			return ctx.Value(contextKey.UserIDKey) == "test-user"
+		}), job().Request, testifymock.Anything).Return([]model.LLMGenerateEvent{
+			{Type: model.LLMGenerateEventQuestions, Data: model.LLMQuestionsEventData{Questions: []string{"志望動機", "自己PR"}}},
+			{Type: model.LLMGenerateEventAnswer, Data: model.LLMAnswerEventData{Index: 0, Question: "志望動機", Answer: "回答"}},
+			{Type: model.LLMGenerateEventError, Data: model.LLMErrorEventData{Index: 1, Question: "自己PR", ErrorCode: model.LLMErrorCodeTimeout}},
+			{Type: model.LLMGenerateEventDone, Data: model.LLMDoneEventData{Total: 2, Succeeded: 1, Failed: 1}},
+		}, answers, nil)
+		mockRepo.On("UpdateProgress", testifymock.Anything, generationJobID, 1, testifymock.Anything).Return(nil)
+		mockRepo.On("Finish", testifymock.Anything, generationJobID, 1, model.GenerationJobStatusSucceeded, answers, "").Return(nil)
+
+		worker := usecase.NewGenerationJobWorker(mockRepo, mockGenerator, []string{"clerk"})
+		processed, err := worker.ProcessNext(context.Background(), "clerk")
+
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		mockRepo.AssertNumberOfCalls(t, "UpdateProgress", 3)
+		mockRepo.AssertCalled(t, "UpdateProgress", testifymock.Anything, generationJobID, 1, []model.GenerationJobQuestion{
+			{Question: "志望動機", Status: model.GenerationJobQuestionSucceeded},
+			{Question: "自己PR", Status: model.GenerationJobQuestionFailed, ErrorCode: model.LLMErrorCodeTimeout},
+		})
+		mockRepo.AssertExpectations(t)
+		mockGenerator.AssertExpectations(t)
+	})
+
+	t.Run("正常系:実行待ちのジョブがない場合", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockGenerator := new(usecasemock.LLMGenerateUsecaseMock)
+		mockRepo.On("RequeueStale", testifymock.Anything, testifymock.Anything, 3).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", testifymock.Anything).Return(nil, nil)
+
+		worker := usecase.NewGenerationJobWorker(mockRepo, mockGenerator, []string{"clerk"})
+		processed, err := worker.ProcessNext(context.Background(), "clerk")
+
+		assert.NoError(t, err)
+		assert.False(t, processed)
+		mockGenerator.AssertNotCalled(t, "LLMGenerateBackground", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("正常系:キャンセルが要求されたジョブを中断する", func(t *testing.T) {
+		t.Setenv("GENERATION_JOB_HEARTBEAT_INTERVAL", "10ms")
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockGenerator := new(usecasemock.LLMGenerateUsecaseMock)
+		mockRepo.On("RequeueStale", testifymock.Anything, testifymock.Anything, 3).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", testifymock.Anything).Return(job(), nil)
+		mockRepo.On("Heartbeat", testifymock.Anything, generationJobID, 1).Return(true, nil)
+		mockGenerator.On("LLMGenerateBackground", testifymock.Anything, testifymock.Anything, testifymock.Anything).
+			Run(func(args testifymock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(nil, nil, context.Canceled)
+		mockRepo.On("Finish", testifymock.Anything, generationJobID, 1, model.GenerationJobStatusCanceled, []model.LLMGeneratedResponse(nil), "").Return(nil)
+
+		worker := usecase.NewGenerationJobWorker(mockRepo, mockGenerator, []string{"clerk"})
+		processed, err := worker.ProcessNext(context.Background(), "clerk")
+
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:生存通知の間に別のワーカーが実行し直し始めた場合は中断して結果を保存しない", func(t *testing.T) {
+		t.Setenv("GENERATION_JOB_HEARTBEAT_INTERVAL", "10ms")
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockGenerator := new(usecasemock.LLMGenerateUsecaseMock)
+		mockRepo.On("RequeueStale", testifymock.Anything, testifymock.Anything, 3).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", testifymock.Anything).Return(job(), nil)
+		mockRepo.On("Heartbeat", testifymock.Anything, generationJobID, 1).Return(false, gorm.ErrRecordNotFound)
+		mockGenerator.On("LLMGenerateBackground", testifymock.Anything, testifymock.Anything, testifymock.Anything).
+			Run(func(args testifymock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(nil, nil, context.Canceled)
+
+		worker := usecase.NewGenerationJobWorker(mockRepo, mockGenerator, []string{"clerk"})
+		processed, err := worker.ProcessNext(context.Background(), "clerk")
+
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Finish", testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:進捗の保存で別のワーカーが実行し直していると分かった場合は中断して結果を保存しない", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockGenerator := new(usecasemock.LLMGenerateUsecaseMock)
+		mockRepo.On("RequeueStale", testifymock.Anything, testifymock.Anything, 3).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", testifymock.Anything).Return(job(), nil)
+		mockRepo.On("UpdateProgress", testifymock.Anything, generationJobID, 1, testifymock.Anything).Return(gorm.ErrRecordNotFound)
+		mockGenerator.On("LLMGenerateBackground", testifymock.Anything, testifymock.Anything, testifymock.Anything).Return([]model.LLMGenerateEvent{
+			{Type: model.LLMGenerateEventQuestions, Data: model.LLMQuestionsEventData{Questions: []string{"志望動機"}}},
+		}, []model.LLMGeneratedResponse{{Question: "志望動機", Answer: "回答"}}, nil)
+
+		worker := usecase.NewGenerationJobWorker(mockRepo, mockGenerator, []string{"clerk"})
+		processed, err := worker.ProcessNext(context.Background(), "clerk")
+
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		mockRepo.AssertNotCalled(t, "Finish", testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:生成に失敗した場合はエラーを保存する", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockGenerator := new(usecasemock.LLMGenerateUsecaseMock)
+		mockRepo.On("RequeueStale", testifymock.Anything, testifymock.Anything, 3).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", testifymock.Anything).Return(job(), nil)
+		mockGenerator.On("LLMGenerateBackground", testifymock.Anything, testifymock.Anything, testifymock.Anything).
+			Return(nil, nil, errors.New("質問が見つかりませんでした"))
+		mockRepo.On("Finish", testifymock.Anything, generationJobID, 1, model.GenerationJobStatusFailed, []model.LLMGeneratedResponse(nil), "質問が見つかりませんでした").Return(nil)
+
+		worker := usecase.NewGenerationJobWorker(mockRepo, mockGenerator, []string{"clerk"})
+		processed, err := worker.ProcessNext(context.Background(), "clerk")
+
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:別のワーカーが実行し直している場合は結果を上書きしない", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockGenerator := new(usecasemock.LLMGenerateUsecaseMock)
+		mockRepo.On("RequeueStale", testifymock.Anything, testifymock.Anything, 3).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", testifymock.Anything).Return(job(), nil)
+		mockGenerator.On("LLMGenerateBackground", testifymock.Anything, testifymock.Anything, testifymock.Anything).
+			Return(nil, nil, errors.New("質問が見つかりませんでした"))
+		mockRepo.On("Finish", testifymock.Anything, generationJobID, 1, model.GenerationJobStatusFailed, []model.LLMGeneratedResponse(nil), "質問が見つかりませんでした").Return(gorm.ErrRecordNotFound)
+
+		worker := usecase.NewGenerationJobWorker(mockRepo, mockGenerator, []string{"clerk"})
+		processed, err := worker.ProcessNext(context.Background(), "clerk")
+
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:ジョブの取得に失敗した場合", func(t *testing.T) {
+		mockRepo := new(mock.GenerationJobRepositoryMock)
+		mockGenerator := new(usecasemock.LLMGenerateUsecaseMock)
+		mockRepo.On("RequeueStale", testifymock.Anything, testifymock.Anything, 3).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", testifymock.Anything).Return(nil, errors.New("db error"))
+
+		worker := usecase.NewGenerationJobWorker(mockRepo, mockGenerator, []string{"clerk"})
+		processed, err := worker.ProcessNext(context.Background(), "clerk")
+
+		assert.Error(t, err)
+		assert.False(t, processed)
+	})
+}
+
+func TestGenerationJobIDPsFromEnv(t *testing.T) {
+	t.Run("正常系:未設定の場合はDBに接続している全てのidpを返す", func(t *testing.T) {
+		t.Setenv("GENERATION_JOB_IDPS", "")
+
+		assert.Equal(t, []string{"clerk", "swagger"}, usecase.GenerationJobIDPsFromEnv([]string{"clerk", "swagger"}))
+	})
+
+	t.Run("正常系:設定されている場合はそのidpのみ返す", func(t *testing.T) {
+		t.Setenv("GENERATION_JOB_IDPS", "clerk, ")
+
+		assert.Equal(t, []string{"clerk"}, usecase.GenerationJobIDPsFromEnv([]string{"clerk", "swagger"}))
+	})
+}
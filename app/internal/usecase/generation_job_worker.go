@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+)
+
+const (
+	defaultGenerationJobWorkers     = 2
+	defaultGenerationJobMaxAttempts = 3
+)
+
+// GenerationJobWorker - DBに登録された生成ジョブを取得して実行するワーカープール
+// ジョブの状態は全てDBに保存するので、複数のサーバーで動かしても、再起動しても実行を引き継げる
+type GenerationJobWorker struct {
+	jobRepo   repository.GenerationJobRepository
+	generator LLMGenerateUsecase
+	idps      []string // ジョブを取得するDB。idpごとに接続先が異なる
+
+	workers           int
+	pollInterval      time.Duration // 実行待ちのジョブがない場合に次に確認するまでの間隔
+	heartbeatInterval time.Duration // 実行中のジョブの生存通知とキャンセル確認の間隔
+	staleAfter        time.Duration // 生存通知がこれ以上途絶えたジョブは、ワーカーが停止したとみなして実行待ちに戻す
+	maxAttempts       int
+}
+
+// NewGenerationJobWorker - 設定は環境変数から読み込む
+// GENERATION_JOB_WORKERS(同時に実行するジョブ数、デフォルト2)、GENERATION_JOB_POLL_INTERVAL(デフォルト2秒)、
+// GENERATION_JOB_HEARTBEAT_INTERVAL(デフォルト5秒)、GENERATION_JOB_STALE_AFTER(デフォルト1分)
+func NewGenerationJobWorker(jobRepo repository.GenerationJobRepository, generator LLMGenerateUsecase, idps []string) *GenerationJobWorker {
+	workers := defaultGenerationJobWorkers
+	if v, err := strconv.Atoi(os.Getenv("GENERATION_JOB_WORKERS")); err == nil && v > 0 {
+		workers = v
+	}
+	return &GenerationJobWorker{
+		jobRepo:           jobRepo,
+		generator:         generator,
+		idps:              idps,
+		workers:           workers,
+		pollInterval:      durationFromEnv("GENERATION_JOB_POLL_INTERVAL", 2*time.Second),
+		heartbeatInterval: durationFromEnv("GENERATION_JOB_HEARTBEAT_INTERVAL", 5*time.Second),
+		staleAfter:        durationFromEnv("GENERATION_JOB_STALE_AFTER", time.Minute),
+		maxAttempts:       defaultGenerationJobMaxAttempts,
+	}
+}
+
+// Run - ctxがキャンセルされるまでジョブを実行し続ける。実行中のジョブが終わるのを待ってから返る
+func (w *GenerationJobWorker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *GenerationJobWorker) loop(ctx context.Context) {
+	for {
+		processed := false
+		for _, idp := range w.idps {
+			ok, err := w.ProcessNext(ctx, idp)
+			if err != nil {
+				log.Printf("生成ジョブの取得に失敗しました(idp=%s): %v", idp, err)
+			}
+			processed = processed || ok
+		}
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// ProcessNext - 停止したワーカーのジョブを実行待ちに戻してから、実行待ちのジョブを1件実行する
+// ジョブを実行した場合はtrueを返す
+func (w *GenerationJobWorker) ProcessNext(ctx context.Context, idp string) (bool, error) {
+	ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	if requeued, err := w.jobRepo.RequeueStale(ctx, time.Now().Add(-w.staleAfter), w.maxAttempts); err != nil {
+		log.Printf("停止した生成ジョブの再登録に失敗しました: %v", err)
+	} else if requeued > 0 {
+		log.Printf("停止した生成ジョブを%d件実行待ちに戻しました", requeued)
+	}
+
+	job, err := w.jobRepo.ClaimNext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	w.run(ctx, job)
+	return true, nil
+}
+
+// run - ジョブを実行し、結果を保存する
+// サーバーの停止でctxがキャンセルされた場合は結果を保存せず、再起動後に別のワーカーが実行し直す
+// 生存通知が途絶えている間に別のワーカーが実行し直し始めた場合も、その場で中断して結果を保存しない
+func (w *GenerationJobWorker) run(ctx context.Context, job *model.GenerationJobs) {
+	jobCtx, cancel := context.WithCancel(context.WithValue(ctx, contextKey.UserIDKey, job.UserID))
+	defer cancel()
+	// 進捗と結果は、ジョブを中断した後でも保存できるようにキャンセルを引き継がない
+	saveCtx := context.WithoutCancel(jobCtx)
+
+	var canceled, lost atomic.Bool
+	onLost := func() {
+		lost.Store(true)
+		cancel()
+	}
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		w.watch(jobCtx, job, func() {
+			canceled.Store(true)
+			cancel()
+		}, onLost)
+	}()
+
+	progress := newGenerationJobProgress()
+	answers, err := w.generator.LLMGenerateBackground(jobCtx, job.Request, func(event model.LLMGenerateEvent) {
+		if !progress.apply(event) {
+			return
+		}
+		err := w.jobRepo.UpdateProgress(saveCtx, job.ID, job.Attempts, progress.snapshot())
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			onLost()
+		case err != nil:
+			log.Printf("生成ジョブ%sの進捗の保存に失敗しました: %v", job.ID, err)
+		}
+	})
+	cancel()
+	<-watchDone
+
+	status, errMsg := model.GenerationJobStatusSucceeded, ""
+	switch {
+	case lost.Load():
+		log.Printf("生成ジョブ%sは別のワーカーが実行し直しているため、中断しました", job.ID)
+		return
+	case canceled.Load():
+		status = model.GenerationJobStatusCanceled
+	case ctx.Err() != nil:
+		log.Printf("サーバーの停止により生成ジョブ%sを中断しました", job.ID)
+		return
+	case err != nil:
+		status, errMsg = model.GenerationJobStatusFailed, err.Error()
+	}
+	err = w.jobRepo.Finish(saveCtx, job.ID, job.Attempts, status, answers, errMsg)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		log.Printf("生成ジョブ%sは別のワーカーが実行し直しているため、結果を保存しません", job.ID)
+	case err != nil:
+		log.Printf("生成ジョブ%sの結果の保存に失敗しました: %v", job.ID, err)
+	}
+}
+
+// watch - ジョブの実行中、定期的に生存を通知し、キャンセルが要求されていればonCancelを呼び出す
+// 別のワーカーが実行し直していて生存を通知できなかった場合はonLostを呼び出す
+func (w *GenerationJobWorker) watch(ctx context.Context, job *model.GenerationJobs, onCancel func(), onLost func()) {
+	ticker := time.NewTicker(w.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelRequested, err := w.jobRepo.Heartbeat(context.WithoutCancel(ctx), job.ID, job.Attempts)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				onLost()
+				return
+			}
+			if err != nil {
+				log.Printf("生成ジョブ%sの生存通知に失敗しました: %v", job.ID, err)
+				continue
+			}
+			if cancelRequested {
+				onCancel()
+				return
+			}
+		}
+	}
+}
+
+// generationJobProgress - 生成のイベントから質問ごとの進捗を組み立てる
+type generationJobProgress struct {
+	questions []model.GenerationJobQuestion
+}
+
+func newGenerationJobProgress() *generationJobProgress {
+	return &generationJobProgress{questions: []model.GenerationJobQuestion{}}
+}
+
+// apply - イベントを進捗に反映する。進捗が変わった場合はtrueを返す
+func (p *generationJobProgress) apply(event model.LLMGenerateEvent) bool {
+	switch data := event.Data.(type) {
+	case model.LLMQuestionsEventData:
+		p.questions = make([]model.GenerationJobQuestion, len(data.Questions))
+		for i, q := range data.Questions {
+			p.questions[i] = model.GenerationJobQuestion{Question: q, Status: model.GenerationJobQuestionPending}
+		}
+		return true
+	case model.LLMAnswerEventData:
+		return p.set(data.Index, model.GenerationJobQuestionSucceeded, "")
+	case model.LLMErrorEventData:
+		return p.set(data.Index, model.GenerationJobQuestionFailed, data.ErrorCode)
+	}
+	return false
+}
+
+func (p *generationJobProgress) set(index int, status model.GenerationJobQuestionStatus, code model.LLMErrorCode) bool {
+	if index < 0 || index >= len(p.questions) {
+		return false
+	}
+	p.questions[index].Status = status
+	p.questions[index].ErrorCode = code
+	return true
+}
+
+func (p *generationJobProgress) snapshot() []model.GenerationJobQuestion {
+	return append([]model.GenerationJobQuestion{}, p.questions...)
+}
+
+// GenerationJobIDPsFromEnv - ワーカーがジョブを取得するidpの一覧。GENERATION_JOB_IDPS(カンマ区切り)、未設定の場合はdefaults
+// defaultsには、ジョブを登録しうる全てのDBを取得できるように、DBConnectionManagerが接続している全てのidpを渡す
+func GenerationJobIDPsFromEnv(defaults []string) []string {
+	var idps []string
+	for _, idp := range strings.Split(os.Getenv("GENERATION_JOB_IDPS"), ",") {
+		if idp = strings.TrimSpace(idp); idp != "" {
+			idps = append(idps, idp)
+		}
+	}
+	if len(idps) == 0 {
+		return defaults
+	}
+	return idps
+}
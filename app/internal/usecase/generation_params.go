@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"es-api/app/internal/entity/model"
+)
+
+const (
+	// MaxTemperature - 生成パラメータで指定できるtemperatureの上限
+	MaxTemperature = 2.0
+	// MaxOutputTokens - 生成パラメータで指定できる最大出力トークン数の上限
+	MaxOutputTokens = 8192
+)
+
+// generationParams - LLM呼び出しの用途ごとの生成パラメータ。prompts/generation_params.jsonで定義する
+type generationParams map[model.LLMUsagePurpose]model.LLMGenerationParams
+
+// defaultGenerationParams - 設定ファイルを読み込めない場合に使う生成パラメータ
+// 質問抽出や採点は結果を安定させるために低いtemperatureで、回答生成は表現に幅を持たせるために高めのtemperatureで呼び出す
+var defaultGenerationParams = generationParams{
+	model.LLMUsagePurposeExtraction: {Temperature: float32Ptr(0), MaxOutputTokens: int32Ptr(8192)},
+	model.LLMUsagePurposeGeneration: {Temperature: float32Ptr(0.7), TopP: float32Ptr(0.95), MaxOutputTokens: int32Ptr(2048), SafetySettings: defaultSafetySettings},
+	model.LLMUsagePurposeRewrite:    {Temperature: float32Ptr(0.3), MaxOutputTokens: int32Ptr(2048)},
+	model.LLMUsagePurposeRefine:     {Temperature: float32Ptr(0.6), TopP: float32Ptr(0.95), MaxOutputTokens: int32Ptr(2048), SafetySettings: defaultSafetySettings},
+	model.LLMUsagePurposeJudge:      {Temperature: float32Ptr(0), MaxOutputTokens: int32Ptr(1024)},
+	model.LLMUsagePurposeReview:     {Temperature: float32Ptr(0.2), MaxOutputTokens: int32Ptr(4096)},
+}
+
+var defaultSafetySettings = []model.LLMSafetySetting{
+	{Category: model.LLMSafetyCategoryHarassment, Threshold: model.LLMSafetyThresholdBlockMediumAndAbove},
+	{Category: model.LLMSafetyCategoryHateSpeech, Threshold: model.LLMSafetyThresholdBlockMediumAndAbove},
+	{Category: model.LLMSafetyCategorySexuallyExplicit, Threshold: model.LLMSafetyThresholdBlockMediumAndAbove},
+	{Category: model.LLMSafetyCategoryDangerousContent, Threshold: model.LLMSafetyThresholdBlockMediumAndAbove},
+}
+
+var validSafetyCategories = map[model.LLMSafetyCategory]bool{
+	model.LLMSafetyCategoryHarassment:       true,
+	model.LLMSafetyCategoryHateSpeech:       true,
+	model.LLMSafetyCategorySexuallyExplicit: true,
+	model.LLMSafetyCategoryDangerousContent: true,
+}
+
+var validSafetyThresholds = map[model.LLMSafetyThreshold]bool{
+	model.LLMSafetyThresholdBlockNone:           true,
+	model.LLMSafetyThresholdBlockOnlyHigh:       true,
+	model.LLMSafetyThresholdBlockMediumAndAbove: true,
+	model.LLMSafetyThresholdBlockLowAndAbove:    true,
+}
+
+// loadGenerationParams - 生成パラメータの設定を読み込む
+// LLM_GENERATION_PARAMS_FILEが設定されている場合はそのファイルを、ない場合はprompts/generation_params.jsonを使う
+func loadGenerationParams() generationParams {
+	var content []byte
+	var err error
+	if path := os.Getenv("LLM_GENERATION_PARAMS_FILE"); path != "" {
+		content, err = os.ReadFile(path)
+	} else {
+		var text string
+		text, err = loadPromptFromFile("generation_params.json")
+		content = []byte(text)
+	}
+
+	if err == nil {
+		var params generationParams
+		params, err = parseGenerationParams(content)
+		if err == nil {
+			return params
+		}
+	}
+
+	log.Printf("生成パラメータの設定の読み込みに失敗: %v, デフォルトの生成パラメータを使用します", err)
+	return defaultGenerationParams
+}
+
+// parseGenerationParams - JSONの生成パラメータの設定を読み込み、内容を検証する
+// 設定にない用途はデフォルトの生成パラメータを使う
+func parseGenerationParams(content []byte) (generationParams, error) {
+	var configured generationParams
+	if err := json.Unmarshal(content, &configured); err != nil {
+		return nil, fmt.Errorf("生成パラメータの設定のJSONが不正です: %w", err)
+	}
+
+	params := make(generationParams, len(defaultGenerationParams))
+	for purpose, p := range defaultGenerationParams {
+		params[purpose] = p
+	}
+	for purpose, p := range configured {
+		if _, ok := defaultGenerationParams[purpose]; !ok {
+			return nil, fmt.Errorf("不明な用途の生成パラメータです: %s", purpose)
+		}
+		if err := validateGenerationParams(p); err != nil {
+			return nil, fmt.Errorf("%sの生成パラメータが不正です: %w", purpose, err)
+		}
+		params[purpose] = p
+	}
+	return params, nil
+}
+
+// validateGenerationParams - 生成パラメータが範囲内かを検証する
+func validateGenerationParams(p model.LLMGenerationParams) error {
+	if err := ValidateRequestParams(&model.LLMRequestParams{Temperature: p.Temperature, TopP: p.TopP, MaxOutputTokens: p.MaxOutputTokens}); err != nil {
+		return err
+	}
+	for _, s := range p.SafetySettings {
+		if !validSafetyCategories[s.Category] {
+			return fmt.Errorf("不明な安全性フィルタの対象です: %s", s.Category)
+		}
+		if !validSafetyThresholds[s.Threshold] {
+			return fmt.Errorf("不明な安全性フィルタの基準です: %s", s.Threshold)
+		}
+	}
+	return nil
+}
+
+// ValidateRequestParams - リクエストで指定された生成パラメータが範囲内かを検証する
+func ValidateRequestParams(p *model.LLMRequestParams) error {
+	if p == nil {
+		return nil
+	}
+	if p.Temperature != nil && (*p.Temperature < 0 || *p.Temperature > MaxTemperature) {
+		return fmt.Errorf("temperatureは0から%gの範囲で指定してください", MaxTemperature)
+	}
+	if p.TopP != nil && (*p.TopP <= 0 || *p.TopP > 1) {
+		return errors.New("topPは0より大きく1以下の範囲で指定してください")
+	}
+	if p.MaxOutputTokens != nil && (*p.MaxOutputTokens < 1 || *p.MaxOutputTokens > MaxOutputTokens) {
+		return fmt.Errorf("maxOutputTokensは1から%dの範囲で指定してください", MaxOutputTokens)
+	}
+	return nil
+}
+
+// forPurpose - 用途の生成パラメータを返す。返り値を書き換えても設定には影響しない
+func (p generationParams) forPurpose(purpose model.LLMUsagePurpose) model.LLMGenerationParams {
+	params := p[purpose]
+	params.StopSequences = append([]string(nil), params.StopSequences...)
+	params.SafetySettings = append([]model.LLMSafetySetting(nil), params.SafetySettings...)
+	return params
+}
+
+// withRequestParams - リクエストで指定された生成パラメータで上書きする
+func withRequestParams(params model.LLMGenerationParams, req *model.LLMRequestParams) model.LLMGenerationParams {
+	if req == nil {
+		return params
+	}
+	if req.Temperature != nil {
+		params.Temperature = req.Temperature
+	}
+	if req.TopP != nil {
+		params.TopP = req.TopP
+	}
+	if req.MaxOutputTokens != nil {
+		params.MaxOutputTokens = req.MaxOutputTokens
+	}
+	return params
+}
+
+func float32Ptr(v float32) *float32 {
+	return &v
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+)
+
+const (
+	defaultGenerationListLimit = 20
+	maxGenerationListLimit     = 100
+)
+
+type GenerationUsecase interface {
+	ListGenerations(ctx context.Context, limit int, offset int) ([]model.Generations, error)
+	GetGeneration(ctx context.Context, id string) (*model.Generations, error)
+}
+
+type generationUsecase struct {
+	gr repository.GenerationRepository
+}
+
+func NewGenerationUsecase(r repository.GenerationRepository) GenerationUsecase {
+	return &generationUsecase{gr: r}
+}
+
+// ListGenerations - ログインユーザーの生成履歴を新しい順に返す
+func (u *generationUsecase) ListGenerations(ctx context.Context, limit int, offset int) ([]model.Generations, error) {
+	if limit <= 0 {
+		limit = defaultGenerationListLimit
+	}
+	if limit > maxGenerationListLimit {
+		limit = maxGenerationListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	generations, err := u.gr.ListByUserID(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list generations: %w", err)
+	}
+	return generations, nil
+}
+
+// GetGeneration - ログインユーザーの生成履歴を1件返す。存在しない場合はnilを返す
+func (u *generationUsecase) GetGeneration(ctx context.Context, id string) (*model.Generations, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+
+	generation, err := u.gr.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find generation: %w", err)
+	}
+	return generation, nil
+}
@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+	"es-api/app/test"
+	mock "es-api/app/test/mock/repository"
+)
+
+func TestGenerationUsecase_ListGenerations(t *testing.T) {
+	t.Run("正常系:limitが未指定の場合はデフォルト値で取得する", func(t *testing.T) {
+		mockRepo := new(mock.GenerationRepositoryMock)
+		expected := []model.Generations{{ID: "123e4567-e89b-12d3-a456-426614174000"}}
+		mockRepo.On("ListByUserID", testifymock.Anything, 20, 0).Return(expected, nil)
+
+		uc := usecase.NewGenerationUsecase(mockRepo)
+		res, err := uc.ListGenerations(test.SetupContextContext("test-user"), 0, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系:limitは上限値に丸める", func(t *testing.T) {
+		mockRepo := new(mock.GenerationRepositoryMock)
+		mockRepo.On("ListByUserID", testifymock.Anything, 100, 10).Return([]model.Generations{}, nil)
+
+		uc := usecase.NewGenerationUsecase(mockRepo)
+		_, err := uc.ListGenerations(test.SetupContextContext("test-user"), 1000, 10)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:リポジトリでエラーが発生した場合", func(t *testing.T) {
+		mockRepo := new(mock.GenerationRepositoryMock)
+		mockRepo.On("ListByUserID", testifymock.Anything, 20, 0).Return(nil, errors.New("db error"))
+
+		uc := usecase.NewGenerationUsecase(mockRepo)
+		res, err := uc.ListGenerations(test.SetupContextContext("test-user"), 0, 0)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestGenerationUsecase_GetGeneration(t *testing.T) {
+	t.Run("正常系:履歴が存在する場合", func(t *testing.T) {
+		mockRepo := new(mock.GenerationRepositoryMock)
+		id := "123e4567-e89b-12d3-a456-426614174000"
+		expected := &model.Generations{ID: id}
+		mockRepo.On("FindByID", testifymock.Anything, id).Return(expected, nil)
+
+		uc := usecase.NewGenerationUsecase(mockRepo)
+		res, err := uc.GetGeneration(test.SetupContextContext("test-user"), id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("異常系:IDがUUIDでない場合は見つからない扱いにする", func(t *testing.T) {
+		mockRepo := new(mock.GenerationRepositoryMock)
+
+		uc := usecase.NewGenerationUsecase(mockRepo)
+		res, err := uc.GetGeneration(test.SetupContextContext("test-user"), "invalid-id")
+
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "FindByID", testifymock.Anything, testifymock.Anything)
+	})
+}
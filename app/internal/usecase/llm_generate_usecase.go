@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -10,105 +11,202 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	"es-api/app/internal/entity/model"
 	db "es-api/app/internal/repository/db"
-	gemini "es-api/app/internal/repository/gemini"
-	tavily "es-api/app/internal/repository/tavily"
+	"es-api/app/internal/repository/embedding"
+	"es-api/app/internal/repository/gbiz"
+	llm "es-api/app/internal/repository/llm"
 )
 
 type LLMGenerateUsecase interface {
 	LLMGenerate(ctx context.Context, req model.LLMGenerateRequest) ([]model.LLMGeneratedResponse, error)
+	LLMGenerateStream(ctx context.Context, req model.LLMGenerateRequest, onEvent func(model.LLMGenerateEvent)) error
+	LLMGenerateBackground(ctx context.Context, req model.LLMGenerateRequest, onEvent func(model.LLMGenerateEvent)) ([]model.LLMGeneratedResponse, error)
+	RefineAnswer(ctx context.Context, req model.LLMRefineRequest) (*model.LLMRefineResponse, error)
+	ReviewAnswer(ctx context.Context, req model.LLMReviewRequest) (*model.LLMReviewResponse, error)
+	CachedQuestions(ctx context.Context, req model.LLMCachedQuestionsRequest) (*model.QuestionCaches, error)
 }
 
 // llmGenerateUsecase はLLMGenerateUsecaseの実装
 type llmGenerateUsecase struct {
-	geminiRepo          gemini.GeminiRepository
-	companyInfoRepo     tavily.TavilyRepository
-	experienceRepo      db.ExperienceRepository
-	companyResearchRepo db.CompanyResearchRepository
+	llmRegistry       llm.LLMProviderRegistry
+	researcher        *CompanyResearcher
+	details           *companyDetailLoader
+	experienceRepo    db.ExperienceRepository
+	generationRepo    db.GenerationRepository
+	usageRepo         db.UsageRepository
+	embeddingRepo     embedding.EmbeddingRepository
+	questionCacheRepo db.QuestionCacheRepository
+	params            generationParams
+	prompts           *promptRegistry
 }
 
 // NewLLMGenerateUsecase は新しいLLMGenerateUsecaseを作成
 func NewLLMGenerateUsecase(
-	geminiRepo gemini.GeminiRepository,
-	companyInfoRepo tavily.TavilyRepository,
+	llmRegistry llm.LLMProviderRegistry,
+	researcher *CompanyResearcher,
 	experienceRepo db.ExperienceRepository,
-	companyResearchRepo db.CompanyResearchRepository,
+	gbizRepo gbiz.GBizInfoRepository,
+	companyDetailRepo db.CompanyDetailRepository,
+	generationRepo db.GenerationRepository,
+	usageRepo db.UsageRepository,
+	embeddingRepo embedding.EmbeddingRepository,
+	questionCacheRepo db.QuestionCacheRepository,
+	promptTemplateRepo db.PromptTemplateRepository,
 ) LLMGenerateUsecase {
 	return &llmGenerateUsecase{
-		geminiRepo:          geminiRepo,
-		companyInfoRepo:     companyInfoRepo,
-		experienceRepo:      experienceRepo,
-		companyResearchRepo: companyResearchRepo,
+		llmRegistry:       llmRegistry,
+		researcher:        researcher,
+		details:           newCompanyDetailLoader(gbizRepo, companyDetailRepo),
+		experienceRepo:    experienceRepo,
+		generationRepo:    generationRepo,
+		usageRepo:         usageRepo,
+		embeddingRepo:     embeddingRepo,
+		questionCacheRepo: questionCacheRepo,
+		params:            loadGenerationParams(),
+		prompts:           newPromptRegistry(promptTemplateRepo),
 	}
 }
 
 // LLMGenerate はHTMLから質問を抽出し、企業情報とユーザーの経験に基づいて回答を生成
+// 一部の質問で生成に失敗した場合も、その質問をStatusがfailedの要素として含めて返す
+// 全ての質問で失敗した場合のみエラーを返す
 func (u *llmGenerateUsecase) LLMGenerate(ctx context.Context, req model.LLMGenerateRequest) ([]model.LLMGeneratedResponse, error) {
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	return u.generate(ctx, req, 30*time.Second, func(model.LLMGenerateEvent) {})
+}
+
+// LLMGenerateStream はLLMGenerateと同じ処理を行い、質問抽出・各回答の生成・完了の各時点でonEventを呼び出す
+// onEventは単一のゴルーチンから順番に呼び出される
+func (u *llmGenerateUsecase) LLMGenerateStream(ctx context.Context, req model.LLMGenerateRequest, onEvent func(model.LLMGenerateEvent)) error {
+	_, err := u.generate(ctx, req, 30*time.Second, onEvent)
+	return err
+}
+
+// LLMGenerateBackground はLLMGenerateStreamと同じ処理を、HTTPリクエストより長い制限時間で行い、生成結果も返す
+// 非同期生成ジョブのワーカーから呼び出す。制限時間はGENERATION_JOB_TIMEOUT(デフォルト10分)
+func (u *llmGenerateUsecase) LLMGenerateBackground(ctx context.Context, req model.LLMGenerateRequest, onEvent func(model.LLMGenerateEvent)) ([]model.LLMGeneratedResponse, error) {
+	return u.generate(ctx, req, durationFromEnv("GENERATION_JOB_TIMEOUT", 10*time.Minute), onEvent)
+}
+
+func (u *llmGenerateUsecase) generate(ctx context.Context, req model.LLMGenerateRequest, timeout time.Duration, onEvent func(model.LLMGenerateEvent)) ([]model.LLMGeneratedResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	llmModel := modelFromEnv("LLM_DEFAULT_MODEL", model.GeminiFlashLite)
+	if model.LLMModel(req.Model) != "" {
+		llmModel = model.LLMModel(req.Model)
+	}
+	provider, err := u.llmRegistry.Resolve(llmModel)
+	if err != nil {
+		return nil, err
+	}
+
+	// ユーザーの経験情報を取得。プロフィールが明示的に指定された場合は、存在しなければ生成を始める前にエラーにする
+	experience, err := u.loadExperience(ctx, req.ProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 全ての質問で同じバージョンを使うよう、回答生成のプロンプトテンプレートは最初に1回だけ取得する
+	generationPrompt, err := u.prompts.load(ctx, model.PromptTemplateESGeneration)
+	if err != nil {
+		return nil, err
+	}
+	systemInstruction, systemPromptVersionID := u.systemInstruction(ctx)
+
+	// 途中で失敗しても、それまでのLLM呼び出しの使用量は記録する
+	usage := newUsageRecorder()
+	defer u.saveUsage(ctx, usage)
+
 	// 1. HTMLから質問を抽出
-	questions, err := u.extractQuestionsFromHTML(ctx, req.HTML)
+	fields, extractionPromptVersionID, err := u.extractQuestionsFromHTML(ctx, req.HTML, req.PageURL, usage)
 	if err != nil {
 		return nil, fmt.Errorf("質問抽出に失敗しました: %w", err)
 	}
-	if len(questions) == 0 {
+	if len(fields) == 0 {
 		return nil, fmt.Errorf("質問が見つかりませんでした")
 	}
+	questions := make([]string, len(fields))
+	for i, field := range fields {
+		questions[i] = field.Question
+	}
+	onEvent(model.LLMGenerateEvent{
+		Type: model.LLMGenerateEventQuestions,
+		Data: model.LLMQuestionsEventData{Questions: questions, Fields: fields},
+	})
 
 	// 2. 企業情報を取得
-	companyInfo, err := u.getCompanyInfo(ctx, req.CompanyID, req.CompanyName)
+	companyInfo, err := u.researcher.companyInfo(ctx, req.CompanyID, req.CompanyName)
 	if err != nil {
 		// 企業情報がなくても回答を生成したいので、エラーはログに記録するのみ
 		log.Printf("企業情報の取得に失敗しました: %v", err)
 	}
+	companyInfo = u.withCompanyDetails(ctx, companyInfo, req.CompanyID, req.CompanyName)
 
-	// 3. ユーザーの経験情報をデータベースから取得
-	experience, err := u.experienceRepo.GetExperienceByUserID(ctx)
-	if err != nil {
-		// 経験情報がなくても回答を生成したいので、エラーはログに記録するのみ
-		log.Printf("経験情報の取得に失敗しました: %v", err)
-	}
+	// 3. 質問ごとに関連する経験情報を選ぶ
+	experienceChunks := u.selectExperienceChunks(ctx, splitExperience(&experience), questions)
 
-	// 4. 質問ごとに回答を生成
+	// 4. 質問ごとに回答を生成。複数の候補を生成する場合は採点して順位を付ける
+	candidateCount := answerCandidateCount(req.Candidates)
 	var wg sync.WaitGroup
 
 	type indexedResponse struct {
 		index int
 		resp  model.LLMGeneratedResponse
+		err   error
 	}
 	responseCh := make(chan indexedResponse, len(questions))
-	errorCh := make(chan error, len(questions))
-
-	llmModel := model.GeminiFlashLite
-	if model.LLMModel(req.Model) != "" {
-		llmModel = model.LLMModel(req.Model)
-	}
 
-	for i, question := range questions {
+	for i, field := range fields {
 		wg.Add(1)
-		go func(idx int, q string) {
+		go func(idx int, field model.ExtractedQuestion) {
 			defer wg.Done()
 
+			q := field.Question
+			charLimit := field.CharLimit
+
+			fail := func(code model.LLMErrorCode, err error) {
+				responseCh <- indexedResponse{
+					index: idx,
+					resp: model.LLMGeneratedResponse{
+						Question:  q,
+						Status:    model.LLMAnswerStatusFailed,
+						ErrorCode: code,
+						CharLimit: charLimit,
+					},
+					err: err,
+				}
+			}
+
 			defer func() {
 				if r := recover(); r != nil {
-					errorCh <- fmt.Errorf("質問「%s」の処理中にパニックが発生: %v", q, r)
+					fail(model.LLMErrorCodeInternal, fmt.Errorf("質問「%s」の処理中にパニックが発生: %v", q, r))
 				}
 			}()
 
-			prompt := u.buildPrompt(q, companyInfo, &experience, req.CompanyName)
-			llmInput := model.GeminiInput{
-				Model: llmModel,
-				Text:  prompt,
+			prompt, promptErr := u.buildPrompt(generationPrompt, q, charLimit, companyInfo, &experience, experienceChunks[idx], req.CompanyName)
+			if promptErr != nil {
+				fail(model.LLMErrorCodeInternal, fmt.Errorf("質問「%s」のプロンプトの作成に失敗: %w", q, promptErr))
+				return
+			}
+			input := model.LLMInput{
+				Model:             llmModel,
+				Text:              prompt,
+				SystemInstruction: systemInstruction,
+				Params:            withRequestParams(u.params.forPurpose(model.LLMUsagePurposeGeneration), req.Params),
 			}
 
 			done := make(chan struct{})
-			var resp model.GeminiResponse
+			var candidates []model.LLMAnswerCandidate
 			var err error
 
 			go func() {
-				resp, err = u.geminiRepo.GetGeminiRequest(ctx, llmInput)
+				candidates, err = u.generateAnswerCandidates(ctx, provider, input, q, charLimit, candidateCount, usage)
+				if err == nil && len(candidates) > 1 {
+					candidates = u.rankAnswerCandidates(ctx, provider, llmModel, q, charLimit, companyInfo, candidates, usage)
+				}
 				close(done)
 			}()
 
@@ -116,39 +214,115 @@ func (u *llmGenerateUsecase) LLMGenerate(ctx context.Context, req model.LLMGener
 			select {
 			case <-done:
 				if err != nil {
-					errorCh <- fmt.Errorf("質問「%s」への回答生成に失敗: %v", q, err)
+					fail(model.LLMErrorCodeGenerationFailed, fmt.Errorf("質問「%s」への回答生成に失敗: %v", q, err))
 					return
 				}
-				responseCh <- indexedResponse{
-					index: idx,
-					resp: model.LLMGeneratedResponse{
-						Question: q,
-						Answer:   resp.Text,
-					},
+				resp := model.LLMGeneratedResponse{
+					Question:  q,
+					Answer:    candidates[0].Answer,
+					Status:    model.LLMAnswerStatusSucceeded,
+					CharLimit: charLimit,
+					CharCount: candidates[0].CharCount,
 				}
+				if candidateCount > 1 {
+					resp.Candidates = candidates
+				}
+				responseCh <- indexedResponse{index: idx, resp: resp}
 			case <-ctx.Done():
-				errorCh <- fmt.Errorf("質問「%s」の回答生成がタイムアウトまたはキャンセルされました: %v", q, ctx.Err())
+				code := model.LLMErrorCodeTimeout
+				if errors.Is(ctx.Err(), context.Canceled) {
+					code = model.LLMErrorCodeCanceled
+				}
+				fail(code, fmt.Errorf("質問「%s」の回答生成がタイムアウトまたはキャンセルされました: %v", q, ctx.Err()))
 			}
-		}(i, question)
+		}(i, field)
 	}
 
 	go func() {
 		wg.Wait()
 		close(responseCh)
-		close(errorCh)
 	}()
 
 	answers := make([]model.LLMGeneratedResponse, len(questions))
 	validAnswers := 0
+	var firstErr error
 
 	for resp := range responseCh {
+		field := fields[resp.index]
+		resp.resp.FieldType = field.FieldType
+		resp.resp.FieldName = field.Name
+		resp.resp.FieldID = field.ID
+		if resp.err != nil {
+			log.Printf("回答生成エラー: %v", resp.err)
+			if firstErr == nil {
+				firstErr = resp.err
+			}
+			resp.resp.Error = resp.err.Error()
+			answers[resp.index] = resp.resp
+			onEvent(model.LLMGenerateEvent{
+				Type: model.LLMGenerateEventError,
+				Data: model.LLMErrorEventData{
+					Index:     resp.index,
+					Question:  resp.resp.Question,
+					ErrorCode: resp.resp.ErrorCode,
+					Error:     resp.resp.Error,
+				},
+			})
+			continue
+		}
 		answers[resp.index] = resp.resp
 		validAnswers++
+		onEvent(model.LLMGenerateEvent{
+			Type: model.LLMGenerateEventAnswer,
+			Data: model.LLMAnswerEventData{
+				Index:      resp.index,
+				Question:   resp.resp.Question,
+				Answer:     resp.resp.Answer,
+				CharLimit:  resp.resp.CharLimit,
+				CharCount:  resp.resp.CharCount,
+				FieldType:  resp.resp.FieldType,
+				FieldName:  resp.resp.FieldName,
+				FieldID:    resp.resp.FieldID,
+				Candidates: resp.resp.Candidates,
+			},
+		})
+	}
+
+	// 5. 生成結果を履歴として保存
+	if validAnswers > 0 {
+		generation := &model.Generations{
+			ID:          usage.requestID,
+			CompanyID:   req.CompanyID,
+			CompanyName: req.CompanyName,
+			Model:       string(llmModel),
+			HTML:        req.HTML,
+			Questions:   questions,
+			Answers:     answers,
+			// プロンプトの変更による品質の違いを比較できるよう、使ったバージョンを記録する
+			PromptVersionID:           generationPrompt.versionIDPtr(),
+			SystemPromptVersionID:     systemPromptVersionID,
+			ExtractionPromptVersionID: extractionPromptVersionID,
+		}
+		// タイムアウト後でも保存できるよう、キャンセルを引き継がないコンテキストを使う
+		if err := u.generationRepo.Create(context.WithoutCancel(ctx), generation); err != nil {
+			// 履歴が保存できなくても回答は返したいので、エラーはログに記録するのみ
+			log.Printf("生成履歴の保存に失敗しました: %v", err)
+		}
 	}
 
-	if validAnswers != len(questions) {
-		if err, ok := <-errorCh; ok {
-			return nil, err
+	onEvent(model.LLMGenerateEvent{
+		Type: model.LLMGenerateEventDone,
+		Data: model.LLMDoneEventData{
+			Total:     len(questions),
+			Succeeded: validAnswers,
+			Failed:    len(questions) - validAnswers,
+		},
+	})
+
+	// 一部の質問が失敗しても、成功した回答は失敗した質問の情報と合わせて返す
+	if validAnswers == 0 {
+		if firstErr != nil {
+			return nil, firstErr
 		}
 		return nil, fmt.Errorf("回答を生成できませんでした")
 	}
@@ -156,76 +330,112 @@ func (u *llmGenerateUsecase) LLMGenerate(ctx context.Context, req model.LLMGener
 	return answers, nil
 }
 
-func (u *llmGenerateUsecase) extractQuestionsFromHTML(ctx context.Context, html string) ([]string, error) {
-	// HTMLが空の場合はエラー
-	if html == "" {
-		return nil, fmt.Errorf("HTMLが空です")
+// getExperienceProfile - 指定されたIDの経験プロフィールを取得する
+func (u *llmGenerateUsecase) getExperienceProfile(ctx context.Context, profileID string) (*model.Experiences, error) {
+	if !isValidUUID(profileID) {
+		return nil, ErrExperienceProfileNotFound
 	}
-
-	promptTemplate, err := loadPromptFromFile("extract_questions.txt")
+	experience, err := u.experienceRepo.GetExperienceByID(ctx, profileID)
 	if err != nil {
-		return nil, fmt.Errorf("プロンプトファイルの読み込みに失敗: %v", err)
+		return nil, profileError("経験プロフィールの取得に失敗しました", err)
 	}
-	prompt := promptTemplate + html
+	return &experience, nil
+}
 
-	// Gemini APIを呼び出す
-	geminiInput := model.GeminiInput{
-		Model: model.GeminiFlashLite, // HTML解析は軽量モデルで十分
-		Text:  prompt,
+// loadExperience - 指定されたプロフィール、未指定の場合はデフォルトのプロフィールの経験情報を取得する
+// デフォルトのプロフィールが取得できない場合は、空の経験情報で続ける
+func (u *llmGenerateUsecase) loadExperience(ctx context.Context, profileID string) (model.Experiences, error) {
+	if profileID != "" {
+		profile, err := u.getExperienceProfile(ctx, profileID)
+		if err != nil {
+			return model.Experiences{}, err
+		}
+		return *profile, nil
 	}
-
-	// geminiリポジトリを利用して質問抽出
-	geminiResponse, err := u.geminiRepo.GetGeminiRequest(ctx, geminiInput)
+	experience, err := u.experienceRepo.GetExperienceByUserID(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("質問抽出エラー: %w", err)
+		// 経験情報がなくても続けたいので、エラーはログに記録するのみ
+		log.Printf("経験情報の取得に失敗しました: %v", err)
 	}
+	return experience, nil
+}
 
-	// Geminiの応答から質問リストを抽出
-	questions := strings.Split(geminiResponse.Text, "*#*")
+// withCompanyDetails - gBizINFOの法人情報を企業情報に加える。取得できない場合はそのまま返す
+func (u *llmGenerateUsecase) withCompanyDetails(ctx context.Context, companyInfo *model.CompanyInfo, companyID string, companyName string) *model.CompanyInfo {
+	if !corporateNumberPattern.MatchString(companyID) {
+		return companyInfo
+	}
+	details, err := u.details.load(ctx, companyID)
+	if err != nil {
+		// 法人情報がなくても回答を生成したいので、エラーはログに記録するのみ
+		log.Printf("法人情報の取得に失敗しました: %v", err)
+		return companyInfo
+	}
+	if companyInfo == nil {
+		companyInfo = &model.CompanyInfo{Name: companyName}
+	}
+	companyInfo.Detail = details
+	return companyInfo
+}
 
-	// 空白の質問をフィルタリング
-	var filteredQuestions []string
-	for _, q := range questions {
-		trimmed := strings.TrimSpace(q)
-		if trimmed != "" {
-			filteredQuestions = append(filteredQuestions, trimmed)
-		}
+// writeCompanyDetails - 法人情報のうち分かっている項目を、会社概要としてプロンプトに書き込む
+func writeCompanyDetails(sb *strings.Builder, details *model.CompanyDetails) {
+	var lines []string
+	if details.BusinessSummary != "" {
+		lines = append(lines, "事業概要: "+details.BusinessSummary)
+	}
+	if details.Address != "" {
+		lines = append(lines, "本社所在地: "+details.Address)
+	}
+	if details.FoundedAt != "" {
+		lines = append(lines, "設立: "+details.FoundedAt)
+	}
+	if details.Capital > 0 {
+		lines = append(lines, fmt.Sprintf("資本金: %d円", details.Capital))
+	}
+	if details.EmployeeCount > 0 {
+		lines = append(lines, fmt.Sprintf("従業員数: %d人", details.EmployeeCount))
+	}
+	if details.URL != "" {
+		lines = append(lines, "URL: "+details.URL)
+	}
+	if len(lines) == 0 {
+		return
 	}
 
-	return filteredQuestions, nil
+	sb.WriteString("■会社概要\n")
+	sb.WriteString(strings.Join(lines, "\n"))
+	sb.WriteString("\n\n")
 }
 
-func (u *llmGenerateUsecase) buildPrompt(question string, companyInfo *model.CompanyInfo, experience *model.Experiences, companyName string) string {
-	promptTemplate, err := loadPromptFromFile("es_generation.txt")
+// buildPrompt - 回答生成のプロンプトテンプレートに質問を埋め込み、企業情報と応募者の経験情報を追加する
+// chunksは質問に関連するとして選ばれた経験情報
+func (u *llmGenerateUsecase) buildPrompt(promptTemplate *promptTemplate, question string, charLimit int, companyInfo *model.CompanyInfo, experience *model.Experiences, chunks []experienceChunk, companyName string) (string, error) {
+	header, err := promptTemplate.render(promptGenerationData{
+		Question:    question,
+		CharLimit:   charLimit,
+		CompanyName: companyName,
+	})
 	if err != nil {
-		log.Printf("プロンプトファイルの読み込みに失敗: %v, デフォルトのプロンプトを使用します", err)
-		return fmt.Sprintf("企業%sについての質問です。一般的な応募者として回答してください。\n\n%s", companyName, question)
+		return "", err
 	}
 
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf(promptTemplate, question))
+	sb.WriteString(header)
+
+	if charLimit > 0 {
+		sb.WriteString(charLimitInstruction(charLimit))
+	}
 
 	// 企業情報の追加
 	sb.WriteString("【企業情報】\n")
 	if companyInfo != nil && companyInfo.Name != "" {
-		if companyInfo.Philosophy != "" {
-			sb.WriteString("■企業理念・バリュー\n")
-			sb.WriteString(companyInfo.Philosophy)
-			sb.WriteString("\n\n")
-		}
-
-		if companyInfo.TalentNeeds != "" {
-			sb.WriteString("■求める人材像\n")
-			sb.WriteString(companyInfo.TalentNeeds)
-			sb.WriteString("\n\n")
+		if companyInfo.Detail != nil {
+			writeCompanyDetails(&sb, companyInfo.Detail)
 		}
 
-		if companyInfo.CareerPath != "" {
-			sb.WriteString("■キャリアパス\n")
-			sb.WriteString(companyInfo.CareerPath)
-			sb.WriteString("\n\n")
-		}
+		u.writeCompanyResearch(&sb, companyInfo)
 	} else {
 		sb.WriteString(fmt.Sprintf("%sという企業についての質問です。一般的な応募者として回答してください。\n\n", companyName))
 	}
@@ -233,202 +443,164 @@ func (u *llmGenerateUsecase) buildPrompt(question string, companyInfo *model.Com
 	// 応募者の経験情報の追加
 	sb.WriteString("【応募者の経歴情報】\n")
 	if experience != nil {
-		if experience.Work != "" {
-			sb.WriteString("■職務経歴\n")
-			sb.WriteString(experience.Work)
-			sb.WriteString("\n\n")
-		}
-
-		if experience.Skills != "" {
-			sb.WriteString("■スキル\n")
-			sb.WriteString(experience.Skills)
-			sb.WriteString("\n\n")
-		}
+		writeExperience(&sb, experience, chunks)
+	}
 
-		if experience.SelfPR != "" {
-			sb.WriteString("■自己PR\n")
-			sb.WriteString(experience.SelfPR)
-			sb.WriteString("\n\n")
-		}
+	return sb.String(), nil
+}
 
-		if experience.FutureGoals != "" {
-			sb.WriteString("■将来の目標\n")
-			sb.WriteString(experience.FutureGoals)
-			sb.WriteString("\n\n")
-		}
+// systemInstruction - 回答生成と修正のシステムプロンプトと、記録用のバージョンのID
+// 取得できない場合は空文字を返し、プロンプトだけで生成する
+func (u *llmGenerateUsecase) systemInstruction(ctx context.Context) (string, *string) {
+	promptTemplate, err := u.prompts.load(ctx, model.PromptTemplateESGenerationSystem)
+	if err != nil {
+		log.Printf("システムプロンプトの読み込みに失敗: %v", err)
+		return "", nil
 	}
-
-	return sb.String()
+	instruction, err := promptTemplate.render(promptSystemData{})
+	if err != nil {
+		log.Printf("システムプロンプトの読み込みに失敗: %v", err)
+		return "", nil
+	}
+	return instruction, promptTemplate.versionIDPtr()
 }
 
-func loadPromptFromFile(filename string) (string, error) {
-	paths := []string{
-		filename,
-		filepath.Join("./prompts", filename),
+// writeExperience - 応募者の経験情報をプロンプトに書き込む。chunksは質問に関連するとして選ばれた経験情報
+func writeExperience(sb *strings.Builder, experience *model.Experiences, chunks []experienceChunk) {
+	writeExperienceSection(sb, experienceSectionWork, chunks)
+
+	if experience.Skills != "" {
+		sb.WriteString("■スキル\n")
+		sb.WriteString(experience.Skills)
+		sb.WriteString("\n\n")
 	}
 
-	var content []byte
-	var err error
+	writeExperienceSection(sb, experienceSectionSelfPR, chunks)
 
-	for _, path := range paths {
-		content, err = os.ReadFile(path)
-		if err == nil {
-			return string(content), nil
-		}
+	if experience.FutureGoals != "" {
+		sb.WriteString("■将来の目標\n")
+		sb.WriteString(experience.FutureGoals)
+		sb.WriteString("\n\n")
 	}
 
-	return "", err
+	writeExperienceSection(sb, experienceSectionEpisode, chunks)
+	writeSkillEntries(sb, experience.SkillEntries)
+	writeExperienceSection(sb, experienceSectionAchievement, chunks)
 }
 
-func (u *llmGenerateUsecase) getCompanyInfo(ctx context.Context, companyID string, companyName string) (*model.CompanyInfo, error) {
-	// キャッシュから企業情報を検索
-	research, err := u.companyResearchRepo.FindByCompanyID(ctx, companyID)
-	if err != nil {
-		return nil, fmt.Errorf("企業情報のキャッシュ検索中にエラーが発生しました: %w", err)
+// companyContext - 採点や添削のプロンプトに含める企業情報(会社概要と調査項目)。情報がない場合は空文字を返す
+func (u *llmGenerateUsecase) companyContext(companyInfo *model.CompanyInfo) string {
+	if companyInfo == nil {
+		return ""
 	}
-
-	// キャッシュがある場合はそれを返す
-	if research != nil {
-		log.Printf("企業情報のキャッシュを利用します: %s", research.CompanyName)
-		return &model.CompanyInfo{
-			Name:        research.CompanyName,
-			Philosophy:  research.Philosophy,
-			CareerPath:  research.CareerPath,
-			TalentNeeds: research.TalentNeeds,
-		}, nil
+	var sb strings.Builder
+	if companyInfo.Detail != nil {
+		writeCompanyDetails(&sb, companyInfo.Detail)
 	}
+	u.writeCompanyResearch(&sb, companyInfo)
+	return strings.TrimSpace(sb.String())
+}
 
-	// APIキーを設定
-	apiKey := os.Getenv("TAVILY_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("TAVILY_API_KEYが設定されていません")
+// writeCompanyResearch - 調査項目ごとの企業情報を、設定の順番でプロンプトに書き込む
+func (u *llmGenerateUsecase) writeCompanyResearch(sb *strings.Builder, companyInfo *model.CompanyInfo) {
+	for _, d := range u.researcher.dimensions {
+		if content := companyInfo.Contents[d.Name]; content != "" {
+			sb.WriteString("■" + d.Label + "\n")
+			sb.WriteString(content)
+			sb.WriteString("\n\n")
+		}
 	}
+}
 
-	// 企業情報を検索
-	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
-	defer cancel()
-
-	companyInfo, err := u.searchCompanyInfoParallel(ctx, apiKey, companyName)
-	if err != nil {
-		return nil, fmt.Errorf("企業情報の検索中にエラーが発生しました: %w", err)
+// writeExperienceSection - 選ばれたチャンクのうち、見出しが一致するものを書き込む
+func writeExperienceSection(sb *strings.Builder, section string, chunks []experienceChunk) {
+	var texts []string
+	for _, c := range chunks {
+		if c.section == section {
+			texts = append(texts, c.text)
+		}
+	}
+	if len(texts) == 0 {
+		return
 	}
+	sb.WriteString("■" + section + "\n")
+	sb.WriteString(strings.Join(texts, "\n"))
+	sb.WriteString("\n\n")
+}
 
-	// 検索結果をキャッシュに保存
-	research = &model.CompanyResearch{
-		CompanyID:   companyID,
-		CompanyName: companyName,
-		Philosophy:  companyInfo.Philosophy,
-		CareerPath:  companyInfo.CareerPath,
-		TalentNeeds: companyInfo.TalentNeeds,
+func writeSkillEntries(sb *strings.Builder, skills []model.ExperienceSkills) {
+	if len(skills) == 0 {
+		return
 	}
-	if err := u.companyResearchRepo.Create(ctx, research); err != nil {
-		log.Printf("企業情報のキャッシュ保存中にエラーが発生しました: %v", err)
+	sb.WriteString("■スキル一覧\n")
+	for _, s := range skills {
+		sb.WriteString(fmt.Sprintf("・%s（%s）\n", s.Name, s.Level.Label()))
 	}
+	sb.WriteString("\n")
+}
 
-	return companyInfo, nil
+// usageRecorder は1回の生成リクエスト内のLLM呼び出しの使用量を集める
+type usageRecorder struct {
+	mu        sync.Mutex
+	requestID string
+	usages    []model.LLMUsages
 }
 
-func (u *llmGenerateUsecase) searchCompanyInfoParallel(ctx context.Context, apiKey string, companyName string) (*model.CompanyInfo, error) {
-	info := &model.CompanyInfo{
-		Name: companyName,
+func newUsageRecorder() *usageRecorder {
+	return &usageRecorder{
+		requestID: uuid.NewString(),
 	}
+}
 
-	var wg sync.WaitGroup
-	errCh := make(chan error, 3)
-	resultCh := make(chan bool, 3)
-
-	wg.Add(3)
-
-	// 1. 企業理念の検索
-	go func() {
-		defer wg.Done()
-
-		philosophyQuery := fmt.Sprintf("%s 企業理念 ミッション 価値観 経営理念", companyName)
-		result, err := u.companyInfoRepo.SearchWithAnswer(ctx, apiKey, philosophyQuery)
-
-		if err != nil || result == nil || result.Answer == "" {
-			// バックアップクエリでリトライ
-			backupQuery := fmt.Sprintf("%s 理念 目指すもの", companyName)
-			result, err = u.companyInfoRepo.SearchWithAnswer(ctx, apiKey, backupQuery)
-		}
-
-		if err != nil {
-			errCh <- fmt.Errorf("企業理念の検索エラー: %v", err)
-			resultCh <- false
-			return
-		}
-
-		if result != nil && result.Answer != "" {
-			info.Philosophy = result.Answer
-		}
-
-		resultCh <- true
-	}()
-
-	// 2. キャリアパスの検索
-	go func() {
-		defer wg.Done()
-
-		careerQuery := fmt.Sprintf("%s 社員 キャリアパス キャリア形成 成長機会 研修", companyName)
-		result, err := u.companyInfoRepo.SearchWithAnswer(ctx, apiKey, careerQuery)
-
-		if err != nil || result == nil || result.Answer == "" {
-			// バックアップクエリ
-			careerQuery = fmt.Sprintf("%s 社員インタビュー キャリア", companyName)
-			result, err = u.companyInfoRepo.SearchWithAnswer(ctx, apiKey, careerQuery)
-		}
-
-		if err != nil {
-			errCh <- fmt.Errorf("キャリアパスの検索エラー: %v", err)
-			resultCh <- false
-			return
-		}
-
-		if result != nil && result.Answer != "" {
-			info.CareerPath = result.Answer
-		}
+func (r *usageRecorder) add(purpose model.LLMUsagePurpose, llmModel model.LLMModel, resp model.LLMResponse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usages = append(r.usages, model.LLMUsages{
+		RequestID:    r.requestID,
+		Model:        string(llmModel),
+		Purpose:      purpose,
+		InputTokens:  resp.InputTokens,
+		OutputTokens: resp.OutputTokens,
+	})
+}
 
-		resultCh <- true
-	}()
+func (u *llmGenerateUsecase) saveUsage(ctx context.Context, usage *usageRecorder) {
+	usage.mu.Lock()
+	usages := usage.usages
+	usage.mu.Unlock()
+	if len(usages) == 0 {
+		return
+	}
 
-	// 3. 求める人材像の検索
-	go func() {
-		defer wg.Done()
+	// タイムアウト後でも保存できるよう、キャンセルを引き継がないコンテキストを使う
+	if err := u.usageRepo.Create(context.WithoutCancel(ctx), usages); err != nil {
+		log.Printf("使用量の保存に失敗しました: %v", err)
+	}
+}
 
-		talentQuery := fmt.Sprintf("%s 求める人材 採用 人物像 採用基準", companyName)
-		result, err := u.companyInfoRepo.SearchWithAnswer(ctx, apiKey, talentQuery)
+// modelFromEnv は環境変数keyに設定されたモデル名を返す。未設定の場合はfallbackを返す
+func modelFromEnv(key string, fallback model.LLMModel) model.LLMModel {
+	if v := os.Getenv(key); v != "" {
+		return model.LLMModel(v)
+	}
+	return fallback
+}
 
-		if err != nil || result == nil || result.Answer == "" {
-			// バックアップクエリ
-			talentQuery = fmt.Sprintf("%s 採用情報 募集要項", companyName)
-			result, err = u.companyInfoRepo.SearchWithAnswer(ctx, apiKey, talentQuery)
-		}
+func loadPromptFromFile(filename string) (string, error) {
+	paths := []string{
+		filename,
+		filepath.Join("./prompts", filename),
+	}
 
-		if err != nil {
-			errCh <- fmt.Errorf("求める人材像の検索エラー: %v", err)
-			resultCh <- false
-			return
-		}
+	var content []byte
+	var err error
 
-		if result != nil && result.Answer != "" {
-			info.TalentNeeds = result.Answer
+	for _, path := range paths {
+		content, err = os.ReadFile(path)
+		if err == nil {
+			return string(content), nil
 		}
-
-		resultCh <- true
-	}()
-
-	// 全ての検索が完了するのを待機
-	go func() {
-		wg.Wait()
-		close(errCh)
-		close(resultCh)
-	}()
-
-	success := 0
-	for range resultCh {
-		success++
 	}
 
-	// エラーがあっても部分的な結果を返す
-	return info, nil
+	return "", err
 }
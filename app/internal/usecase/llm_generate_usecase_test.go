@@ -0,0 +1,1087 @@
+package usecase_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/repository/embedding"
+	llm "es-api/app/internal/repository/llm"
+	"es-api/app/internal/usecase"
+	"es-api/app/test"
+	mock "es-api/app/test/mock/repository"
+)
+
+// isExtractInput は質問抽出用のGemini呼び出しかどうかを判定する
+func isExtractInput(input model.GeminiInput) bool {
+	return strings.Contains(input.Text, "以下の入力欄を分析してください") || strings.Contains(input.Text, "以下のHTMLを分析してください")
+}
+
+// questionsJSON は質問抽出のGeminiの応答を作る
+func questionsJSON(questions ...string) string {
+	fields := make([]model.ExtractedQuestion, len(questions))
+	for i, q := range questions {
+		fields[i] = model.ExtractedQuestion{Question: q, FieldType: model.QuestionFieldTypeTextarea}
+	}
+	b, _ := json.Marshal(map[string]interface{}{"questions": fields})
+	return string(b)
+}
+
+// isRepairInput は質問抽出結果のJSON修復用のGemini呼び出しかどうかを判定する
+func isRepairInput(input model.GeminiInput) bool {
+	return strings.Contains(input.Text, "正しいJSONに修正してください")
+}
+
+// isRewriteInput は文字数制限に合わせた書き直し用のGemini呼び出しかどうかを判定する
+func isRewriteInput(input model.GeminiInput) bool {
+	return strings.Contains(input.Text, "文字数制限に合わせて書き直してください")
+}
+
+// isJudgeInput は回答候補の採点用のGemini呼び出しかどうかを判定する
+func isJudgeInput(input model.GeminiInput) bool {
+	return strings.Contains(input.Text, "複数の回答候補を、以下の基準で")
+}
+
+// isAnswerInput は指定した質問への回答生成用のGemini呼び出しかどうかを判定する
+func isAnswerInput(question string) func(model.GeminiInput) bool {
+	return func(input model.GeminiInput) bool {
+		return !isExtractInput(input) && !isRepairInput(input) && !isRewriteInput(input) && !isJudgeInput(input) && strings.Contains(input.Text, "「"+question+"」")
+	}
+}
+
+// isCandidateInput は指定した切り口の指示を含む回答候補の生成用のGemini呼び出しかどうかを判定する。instructionが空の場合は切り口の指示がないもの
+func isCandidateInput(question string, instruction string) func(model.GeminiInput) bool {
+	return func(input model.GeminiInput) bool {
+		if !isAnswerInput(question)(input) || input.Params.Temperature == nil {
+			return false
+		}
+		if instruction == "" {
+			return !strings.Contains(input.Text, "【回答の切り口】")
+		}
+		return strings.Contains(input.Text, instruction)
+	}
+}
+
+type llmGenerateMocks struct {
+	gemini     *mock.GeminiRepositoryMock
+	search     *mock.SearchProviderMock
+	experience *mock.ExperienceRepositoryMock
+	research   *mock.CompanyResearchRepositoryMock
+	gbiz       *mock.GBizInfoRepositoryMock
+	details    *mock.CompanyDetailRepositoryMock
+	generation *mock.GenerationRepositoryMock
+	usage      *mock.UsageRepositoryMock
+	embedding  embedding.EmbeddingRepository
+	questions  *mock.QuestionCacheRepositoryMock
+	prompts    *mock.PromptTemplateRepositoryMock
+}
+
+func newLLMGenerateMocks() llmGenerateMocks {
+	m := llmGenerateMocks{
+		gemini:     new(mock.GeminiRepositoryMock),
+		search:     new(mock.SearchProviderMock),
+		experience: new(mock.ExperienceRepositoryMock),
+		research:   new(mock.CompanyResearchRepositoryMock),
+		gbiz:       new(mock.GBizInfoRepositoryMock),
+		details:    new(mock.CompanyDetailRepositoryMock),
+		generation: new(mock.GenerationRepositoryMock),
+		usage:      new(mock.UsageRepositoryMock),
+		embedding:  embedding.NewHashedEmbeddingRepository(0),
+		questions:  new(mock.QuestionCacheRepositoryMock),
+		prompts:    new(mock.PromptTemplateRepositoryMock),
+	}
+	m.research.On("FindByCompanyID", testifymock.Anything, "1234567890123").Return(&model.CompanyResearch{
+		CompanyID:   "1234567890123",
+		CompanyName: "株式会社テスト",
+		Contents: map[model.ResearchSection]string{
+			model.ResearchSectionPhilosophy:  "テスト企業理念",
+			model.ResearchSectionCareerPath:  "テストキャリアパス",
+			model.ResearchSectionTalentNeeds: "テスト求める人材像",
+		},
+		UpdatedAt: time.Now(),
+	}, nil)
+	m.details.On("FindByCorporateNumber", testifymock.Anything, "1234567890123").Return(&model.CompanyDetails{
+		CorporateNumber: "1234567890123",
+		Name:            "株式会社テスト",
+		UpdatedAt:       time.Now(),
+	}, nil)
+	m.experience.On("GetExperienceByUserID", testifymock.Anything).Return(model.Experiences{Work: "テスト職歴"}, nil)
+	m.generation.On("Create", testifymock.Anything, testifymock.Anything).Return(nil)
+	m.usage.On("Create", testifymock.Anything, testifymock.Anything).Return(nil)
+	m.questions.On("FindByHTMLHash", testifymock.Anything, testifymock.Anything).Return(nil, nil).Maybe()
+	m.questions.On("Upsert", testifymock.Anything, testifymock.Anything).Return(nil).Maybe()
+	m.prompts.On("FindActive", testifymock.Anything, testifymock.Anything).Return(nil, nil).Maybe()
+	return m
+}
+
+func (m llmGenerateMocks) usecase() usecase.LLMGenerateUsecase {
+	return usecase.NewLLMGenerateUsecase(llm.NewLLMProviderRegistry(llm.NewGeminiProvider(m.gemini)), usecase.NewCompanyResearcher(m.search, m.research), m.experience, m.gbiz, m.details, m.generation, m.usage, m.embedding, m.questions, m.prompts)
+}
+
+var experienceWithEpisodes = model.Experiences{
+	SelfPR: "御社を志望する理由は、事業の将来性に惹かれたからです",
+	Episodes: []model.ExperienceEpisodes{
+		{Title: "研究室でのチーム開発", Action: "学生時代は研究室でのチーム開発に力を入れた"},
+	},
+	SkillEntries: []model.ExperienceSkills{{Name: "Go", Level: model.SkillLevelAdvanced}},
+}
+
+var llmGenerateRequest = model.LLMGenerateRequest{
+	CompanyName: "株式会社テスト",
+	CompanyID:   "1234567890123",
+	HTML:        "<textarea></textarea>",
+}
+
+func TestLLMGenerateUsecase_LLMGenerate(t *testing.T) {
+	t.Run("正常系:質問の順番通りに回答を返す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機", "自己PR"), InputTokens: 100, OutputTokens: 10}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1", InputTokens: 50, OutputTokens: 20}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("自己PR"))).Return(model.GeminiResponse{Text: "回答2", InputTokens: 50, OutputTokens: 30}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.LLMGeneratedResponse{
+			{Question: "志望動機", Answer: "回答1", Status: model.LLMAnswerStatusSucceeded, CharCount: 3, FieldType: model.QuestionFieldTypeTextarea},
+			{Question: "自己PR", Answer: "回答2", Status: model.LLMAnswerStatusSucceeded, CharCount: 3, FieldType: model.QuestionFieldTypeTextarea},
+		}, res)
+		m.generation.AssertCalled(t, "Create", testifymock.Anything, testifymock.MatchedBy(func(g *model.Generations) bool {
+			return g.CompanyID == "1234567890123" &&
+				g.Model == string(model.GeminiFlashLite) &&
+				len(g.Questions) == 2 &&
+				len(g.Answers) == 2
+		}))
+		m.usage.AssertCalled(t, "Create", testifymock.Anything, testifymock.MatchedBy(func(usages []model.LLMUsages) bool {
+			var input, output int32
+			extraction := 0
+			for _, u := range usages {
+				input += u.InputTokens
+				output += u.OutputTokens
+				if u.Purpose == model.LLMUsagePurposeExtraction {
+					extraction++
+				}
+			}
+			return len(usages) == 3 && extraction == 1 && input == 200 && output == 60 &&
+				usages[0].RequestID != "" && usages[0].RequestID == usages[1].RequestID
+		}))
+	})
+
+	t.Run("正常系:履歴の保存に失敗しても回答を返す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.generation.ExpectedCalls = nil
+		m.generation.On("Create", testifymock.Anything, testifymock.Anything).Return(errors.New("db error"))
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("正常系:一部の質問が失敗しても成功した回答を返す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機", "自己PR")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{}, errors.New("gemini error"))
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("自己PR"))).Return(model.GeminiResponse{Text: "回答2"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, model.LLMAnswerStatusFailed, res[0].Status)
+		assert.Equal(t, model.LLMErrorCodeGenerationFailed, res[0].ErrorCode)
+		assert.Empty(t, res[0].Answer)
+		assert.NotEmpty(t, res[0].Error)
+		assert.Equal(t, model.LLMGeneratedResponse{Question: "自己PR", Answer: "回答2", Status: model.LLMAnswerStatusSucceeded, CharCount: 3, FieldType: model.QuestionFieldTypeTextarea}, res[1])
+	})
+
+	t.Run("正常系:文字数制限を超えた回答は書き直す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		question := "自己PRを教えてください（１０字以内）"
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON(question)}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput(question)(input) && strings.Contains(input.Text, "改行を除いて8字以上10字以内")
+		})).Return(model.GeminiResponse{Text: "私の強みは粘り強さです。\n何事も諦めません。"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isRewriteInput(input) && strings.Contains(input.Text, "（21字）")
+		})).Return(model.GeminiResponse{Text: "強みは粘り強さです", InputTokens: 10, OutputTokens: 5}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "強みは粘り強さです", res[0].Answer)
+		assert.Equal(t, 10, res[0].CharLimit)
+		assert.Equal(t, 9, res[0].CharCount)
+		m.usage.AssertCalled(t, "Create", testifymock.Anything, testifymock.MatchedBy(func(usages []model.LLMUsages) bool {
+			return len(usages) == 3 && usages[2].Purpose == model.LLMUsagePurposeRewrite
+		}))
+	})
+
+	t.Run("正常系:書き直しの回数を使い切った場合は最も制限に近い回答を返す", func(t *testing.T) {
+		t.Setenv("CHAR_LIMIT_MAX_REWRITES", "2")
+		m := newLLMGenerateMocks()
+		question := "自己PRを教えてください（10文字以内）"
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON(question)}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput(question))).Return(model.GeminiResponse{Text: "私の強みは粘り強さです。"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isRewriteInput(input) && strings.Contains(input.Text, "（12字）")
+		})).Return(model.GeminiResponse{Text: "強みです"}, nil).Once()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isRewriteInput(input) && strings.Contains(input.Text, "（4字）")
+		})).Return(model.GeminiResponse{Text: "私の強みは粘り強さと行動力です"}, nil).Once()
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "強みです", res[0].Answer)
+		assert.Equal(t, 4, res[0].CharCount)
+		m.gemini.AssertNumberOfCalls(t, "GetGeminiRequest", 4)
+	})
+
+	t.Run("異常系:全ての質問が失敗した場合", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{}, errors.New("gemini error"))
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		m.generation.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("正常系:指定したプロフィールの経験で回答を生成する", func(t *testing.T) {
+		const profileID = "123e4567-e89b-12d3-a456-426614174000"
+		m := newLLMGenerateMocks()
+		m.experience.On("GetExperienceByID", testifymock.Anything, profileID).Return(model.Experiences{Work: "プロフィール職歴"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機")(input) && strings.Contains(input.Text, "プロフィール職歴")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		req := llmGenerateRequest
+		req.ProfileID = profileID
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		m.experience.AssertNotCalled(t, "GetExperienceByUserID", testifymock.Anything)
+	})
+
+	t.Run("正常系:質問に関連する経験情報だけをプロンプトに含める", func(t *testing.T) {
+		t.Setenv("EXPERIENCE_TOP_K", "1")
+		m := newLLMGenerateMocks()
+		m.experience.ExpectedCalls = nil
+		m.experience.On("GetExperienceByUserID", testifymock.Anything).Return(experienceWithEpisodes, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("学生時代に力を入れたことを教えてください", "志望動機を教えてください")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("学生時代に力を入れたことを教えてください")(input) &&
+				strings.Contains(input.Text, "学生時代は研究室でのチーム開発に力を入れた") &&
+				!strings.Contains(input.Text, "御社を志望する理由") &&
+				strings.Contains(input.Text, "Go（上級）")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機を教えてください")(input) &&
+				strings.Contains(input.Text, "御社を志望する理由") &&
+				!strings.Contains(input.Text, "学生時代は研究室でのチーム開発に力を入れた")
+		})).Return(model.GeminiResponse{Text: "回答2"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.LLMAnswerStatusSucceeded, res[0].Status)
+		assert.Equal(t, model.LLMAnswerStatusSucceeded, res[1].Status)
+	})
+
+	t.Run("正常系:埋め込みに失敗した場合は全ての経験情報を使う", func(t *testing.T) {
+		t.Setenv("EXPERIENCE_TOP_K", "1")
+		m := newLLMGenerateMocks()
+		embeddingMock := new(mock.EmbeddingRepositoryMock)
+		embeddingMock.On("Embed", testifymock.Anything, testifymock.Anything).Return(nil, errors.New("embedding error"))
+		m.embedding = embeddingMock
+		m.experience.ExpectedCalls = nil
+		m.experience.On("GetExperienceByUserID", testifymock.Anything).Return(experienceWithEpisodes, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機を教えてください")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機を教えてください")(input) &&
+				strings.Contains(input.Text, "御社を志望する理由") &&
+				strings.Contains(input.Text, "学生時代は研究室でのチーム開発に力を入れた")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("正常系:入力欄のname/idを回答に含める", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		extracted := `{"questions":[` +
+			`{"question":"志望動機（400字以内）","charLimit":0,"fieldType":"textarea","name":"q1","id":"motivation"},` +
+			`{"question":"氏名（ふりがな）","charLimit":0,"fieldType":"text","name":"kana","id":""},` +
+			`{"question":"  ","charLimit":0,"fieldType":"textarea","name":"","id":""}]}`
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isExtractInput(input) && input.ResponseSchema != nil
+		})).Return(model.GeminiResponse{Text: extracted}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機（400字以内）"))).Return(model.GeminiResponse{Text: strings.Repeat("あ", 380)}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("氏名（ふりがな）"))).Return(model.GeminiResponse{Text: "回答2"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, 400, res[0].CharLimit)
+		assert.Equal(t, model.QuestionFieldTypeTextarea, res[0].FieldType)
+		assert.Equal(t, "q1", res[0].FieldName)
+		assert.Equal(t, "motivation", res[0].FieldID)
+		assert.Equal(t, model.QuestionFieldTypeText, res[1].FieldType)
+		assert.Equal(t, "kana", res[1].FieldName)
+		assert.Empty(t, res[1].FieldID)
+	})
+
+	t.Run("正常系:前置きやコードブロック付きのJSONも解析する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		extracted := "以下が抽出結果です。\n```json\n{\"questions\": [{\"question\": \"志望動機\", \"name\": \"q1\"},]}\n```"
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: extracted}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.LLMGeneratedResponse{
+			{Question: "志望動機", Answer: "回答1", Status: model.LLMAnswerStatusSucceeded, CharCount: 3, FieldType: model.QuestionFieldTypeTextarea, FieldName: "q1"},
+		}, res)
+		m.gemini.AssertNumberOfCalls(t, "GetGeminiRequest", 2)
+	})
+
+	t.Run("正常系:解析できないJSONはLLMで修復する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: `{"questions": [{"question": "志望動機"`}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isRepairInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		m.usage.AssertCalled(t, "Create", testifymock.Anything, testifymock.MatchedBy(func(usages []model.LLMUsages) bool {
+			extraction := 0
+			for _, u := range usages {
+				if u.Purpose == model.LLMUsagePurposeExtraction {
+					extraction++
+				}
+			}
+			return extraction == 2
+		}))
+	})
+
+	t.Run("正常系:法人情報を企業情報としてプロンプトに含める", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.details.ExpectedCalls = nil
+		m.details.On("FindByCorporateNumber", testifymock.Anything, "1234567890123").Return(nil, nil)
+		m.gbiz.On("GetCompany", testifymock.Anything, "1234567890123").Return(&model.CompanyDetails{
+			CorporateNumber: "1234567890123",
+			Name:            "株式会社テスト",
+			Address:         "東京都千代田区丸の内1-1-1",
+			Capital:         100000000,
+			EmployeeCount:   500,
+			FoundedAt:       "2000-04-01",
+			BusinessSummary: "業務用ソフトウェアの開発",
+		}, nil)
+		m.details.On("Upsert", testifymock.Anything, testifymock.Anything).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機")(input) &&
+				strings.Contains(input.Text, "■会社概要\n事業概要: 業務用ソフトウェアの開発\n本社所在地: 東京都千代田区丸の内1-1-1\n設立: 2000-04-01\n資本金: 100000000円\n従業員数: 500人\n") &&
+				strings.Contains(input.Text, "テスト企業理念")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		m.details.AssertCalled(t, "Upsert", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("正常系:法人情報が取得できなくても回答を生成する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.details.ExpectedCalls = nil
+		m.details.On("FindByCorporateNumber", testifymock.Anything, "1234567890123").Return(nil, nil)
+		m.gbiz.On("GetCompany", testifymock.Anything, "1234567890123").Return(nil, errors.New("GBIZ_API_KEY is not set"))
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isAnswerInput("志望動機")(input) && !strings.Contains(input.Text, "■会社概要")
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("異常系:指定したプロフィールが存在しない場合", func(t *testing.T) {
+		const profileID = "123e4567-e89b-12d3-a456-426614174000"
+		m := newLLMGenerateMocks()
+		m.experience.On("GetExperienceByID", testifymock.Anything, profileID).Return(model.Experiences{}, gorm.ErrRecordNotFound)
+		req := llmGenerateRequest
+		req.ProfileID = profileID
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceProfileNotFound)
+		assert.Nil(t, res)
+		m.gemini.AssertNotCalled(t, "GetGeminiRequest", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:対応するプロバイダーがないモデルの場合", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		req := llmGenerateRequest
+		req.Model = "unknown-model"
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		m.gemini.AssertNotCalled(t, "GetGeminiRequest", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:修復後もJSONとして解析できない場合", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: "質問はありません"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isRepairInput)).Return(model.GeminiResponse{Text: "修復できません"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("異常系:質問抽出に失敗した場合", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{}, errors.New("gemini error"))
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		m.usage.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+	})
+}
+
+func TestLLMGenerateUsecase_LLMGenerate_Candidates(t *testing.T) {
+	req := llmGenerateRequest
+	req.Candidates = 3
+
+	t.Run("正常系:回答候補を採点の高い順に返す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", ""))).Return(model.GeminiResponse{Text: "候補A"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", "エピソードの場面"))).Return(model.GeminiResponse{Text: "候補B"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", "自分の強みとの結びつき"))).Return(model.GeminiResponse{Text: "候補C"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isJudgeInput(input) && input.ResponseSchema != nil &&
+				strings.Contains(input.Text, "テスト求める人材像") &&
+				strings.Contains(input.Text, "候補A") && strings.Contains(input.Text, "候補B") && strings.Contains(input.Text, "候補C")
+		})).Return(model.GeminiResponse{Text: `{"scores":[` +
+			`{"index":1,"specificity":3,"companyFit":3,"charLimit":5},` +
+			`{"index":2,"specificity":5,"companyFit":4,"charLimit":5,"comment":"具体的"},` +
+			`{"index":3,"specificity":4,"companyFit":9,"charLimit":5}]}`}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "候補B", res[0].Answer)
+		assert.Equal(t, []model.LLMAnswerCandidate{
+			{Answer: "候補B", CharCount: 3, Angle: "episode", Score: &model.LLMAnswerScore{Specificity: 5, CompanyFit: 4, CharLimit: 5, Total: 14, Comment: "具体的"}},
+			{Answer: "候補C", CharCount: 3, Angle: "company_fit", Score: &model.LLMAnswerScore{Specificity: 4, CompanyFit: 5, CharLimit: 5, Total: 14}},
+			{Answer: "候補A", CharCount: 3, Angle: "standard", Score: &model.LLMAnswerScore{Specificity: 3, CompanyFit: 3, CharLimit: 5, Total: 11}},
+		}, res[0].Candidates)
+		m.usage.AssertCalled(t, "Create", testifymock.Anything, testifymock.MatchedBy(func(usages []model.LLMUsages) bool {
+			judge := 0
+			for _, u := range usages {
+				if u.Purpose == model.LLMUsagePurposeJudge {
+					judge++
+				}
+			}
+			return len(usages) == 5 && judge == 1
+		}))
+	})
+
+	t.Run("正常系:採点に失敗した場合は生成した順番で返す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", ""))).Return(model.GeminiResponse{Text: "候補A"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", "エピソードの場面"))).Return(model.GeminiResponse{Text: "候補B"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", "自分の強みとの結びつき"))).Return(model.GeminiResponse{Text: "候補C"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isJudgeInput)).Return(model.GeminiResponse{}, errors.New("gemini error"))
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "候補A", res[0].Answer)
+		assert.Len(t, res[0].Candidates, 3)
+		for i, answer := range []string{"候補A", "候補B", "候補C"} {
+			assert.Equal(t, answer, res[0].Candidates[i].Answer)
+			assert.Nil(t, res[0].Candidates[i].Score)
+		}
+	})
+
+	t.Run("正常系:一部の候補の生成に失敗しても成功した候補を返す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", ""))).Return(model.GeminiResponse{}, errors.New("gemini error"))
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", "エピソードの場面"))).Return(model.GeminiResponse{Text: "候補B"}, nil)
+		req := req
+		req.Candidates = 2
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "候補B", res[0].Answer)
+		assert.Equal(t, []model.LLMAnswerCandidate{{Answer: "候補B", CharCount: 3, Angle: "episode"}}, res[0].Candidates)
+		m.gemini.AssertNotCalled(t, "GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isJudgeInput))
+	})
+
+	t.Run("異常系:全ての候補の生成に失敗した場合", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{}, errors.New("gemini error"))
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestLLMGenerateUsecase_LLMGenerateStream(t *testing.T) {
+	t.Run("正常系:質問・回答・完了の順にイベントを送出する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機", "自己PR")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("自己PR"))).Return(model.GeminiResponse{Text: "回答2"}, nil)
+
+		var events []model.LLMGenerateEvent
+		err := m.usecase().LLMGenerateStream(test.SetupContextContext("test-user"), llmGenerateRequest, func(event model.LLMGenerateEvent) {
+			events = append(events, event)
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, events, 4)
+		assert.Equal(t, model.LLMGenerateEventQuestions, events[0].Type)
+		assert.Equal(t, []string{"志望動機", "自己PR"}, events[0].Data.(model.LLMQuestionsEventData).Questions)
+
+		answers := map[int]string{}
+		for _, event := range events[1:3] {
+			assert.Equal(t, model.LLMGenerateEventAnswer, event.Type)
+			data := event.Data.(model.LLMAnswerEventData)
+			answers[data.Index] = data.Answer
+		}
+		assert.Equal(t, map[int]string{0: "回答1", 1: "回答2"}, answers)
+
+		assert.Equal(t, model.LLMGenerateEventDone, events[3].Type)
+		assert.Equal(t, model.LLMDoneEventData{Total: 2, Succeeded: 2, Failed: 0}, events[3].Data)
+	})
+}
+
+func TestLLMGenerateUsecase_GenerationParams(t *testing.T) {
+	// capture は呼び出されたGeminiへの入力を記録して、回答を返す
+	capture := func(m llmGenerateMocks, question string) (*model.GeminiInput, *model.GeminiInput) {
+		var extract, answer model.GeminiInput
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Run(func(args testifymock.Arguments) {
+			extract = args.Get(1).(model.GeminiInput)
+		}).Return(model.GeminiResponse{Text: questionsJSON(question)}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput(question))).Run(func(args testifymock.Arguments) {
+			answer = args.Get(1).(model.GeminiInput)
+		}).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		return &extract, &answer
+	}
+
+	t.Run("正常系:質問抽出は低いtemperature、回答生成は高めのtemperatureとシステムプロンプトで呼び出す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		extract, answer := capture(m, "志望動機")
+
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0), *extract.Params.Temperature)
+		assert.Empty(t, extract.SystemInstruction)
+		assert.Equal(t, float32(0.7), *answer.Params.Temperature)
+		assert.Equal(t, float32(0.95), *answer.Params.TopP)
+		assert.Len(t, answer.Params.SafetySettings, 4)
+		assert.Contains(t, answer.SystemInstruction, "エントリーシート(ES)のプロフェッショナル作成者")
+		assert.NotContains(t, answer.Text, "【回答作成の基本方針】")
+	})
+
+	t.Run("正常系:リクエストの生成パラメータで回答生成の設定だけを上書きする", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		extract, answer := capture(m, "志望動機")
+		temperature := float32(0.2)
+		maxOutputTokens := int32(512)
+		req := llmGenerateRequest
+		req.Params = &model.LLMRequestParams{Temperature: &temperature, MaxOutputTokens: &maxOutputTokens}
+
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0.2), *answer.Params.Temperature)
+		assert.Equal(t, int32(512), *answer.Params.MaxOutputTokens)
+		assert.Equal(t, float32(0.95), *answer.Params.TopP)
+		assert.Equal(t, float32(0), *extract.Params.Temperature)
+	})
+
+	t.Run("正常系:複数の候補を生成する場合はリクエストのtemperatureを基準に切り口ごとに調整する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		temperatures := map[string]float32{}
+		var mu sync.Mutex
+		for angle, instruction := range map[string]string{"standard": "", "episode": "エピソードの場面"} {
+			m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isCandidateInput("志望動機", instruction))).Run(func(args testifymock.Arguments) {
+				mu.Lock()
+				defer mu.Unlock()
+				temperatures[angle] = *args.Get(1).(model.GeminiInput).Params.Temperature
+			}).Return(model.GeminiResponse{Text: "候補"}, nil)
+		}
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isJudgeInput)).Return(model.GeminiResponse{Text: `{"scores":[` +
+			`{"index":1,"specificity":4,"companyFit":4,"charLimit":5},` +
+			`{"index":2,"specificity":3,"companyFit":3,"charLimit":5}]}`}, nil)
+		temperature := float32(0.2)
+		req := llmGenerateRequest
+		req.Candidates = 2
+		req.Params = &model.LLMRequestParams{Temperature: &temperature}
+
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]float32{"standard": 0, "episode": 0.2}, temperatures)
+	})
+
+	t.Run("正常系:設定ファイルの生成パラメータを使い、設定にない用途はデフォルトを使う", func(t *testing.T) {
+		path := t.TempDir() + "/generation_params.json"
+		if err := os.WriteFile(path, []byte(`{"generation": {"temperature": 1.2, "stopSequences": ["以上"]}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("LLM_GENERATION_PARAMS_FILE", path)
+		m := newLLMGenerateMocks()
+		extract, answer := capture(m, "志望動機")
+
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float32(1.2), *answer.Params.Temperature)
+		assert.Equal(t, []string{"以上"}, answer.Params.StopSequences)
+		assert.Nil(t, answer.Params.TopP)
+		assert.Equal(t, float32(0), *extract.Params.Temperature)
+	})
+
+	t.Run("異常系:設定ファイルの値が範囲外の場合はデフォルトの生成パラメータを使う", func(t *testing.T) {
+		path := t.TempDir() + "/generation_params.json"
+		if err := os.WriteFile(path, []byte(`{"generation": {"temperature": 3}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("LLM_GENERATION_PARAMS_FILE", path)
+		m := newLLMGenerateMocks()
+		_, answer := capture(m, "志望動機")
+
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0.7), *answer.Params.Temperature)
+	})
+}
+
+func TestLLMGenerateUsecase_PromptTemplates(t *testing.T) {
+	t.Run("正常系:有効なバージョンのテンプレートで生成し、使ったバージョンを履歴に記録する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.prompts.ExpectedCalls = nil
+		m.prompts.On("FindActive", testifymock.Anything, model.PromptTemplateESGeneration).Return(&model.PromptTemplates{
+			ID:      "generation-version-id",
+			Name:    model.PromptTemplateESGeneration,
+			Version: 2,
+			Content: "{{.CompanyName}}の選考で「{{.Question}}」に回答してください。\n",
+		}, nil)
+		m.prompts.On("FindActive", testifymock.Anything, model.PromptTemplateExtractFormFields).Return(&model.PromptTemplates{
+			ID:      "extraction-version-id",
+			Name:    model.PromptTemplateExtractFormFields,
+			Version: 3,
+			Content: "以下の入力欄を分析してください(v3):\n{{.Content}}",
+		}, nil)
+		m.prompts.On("FindActive", testifymock.Anything, model.PromptTemplateESGenerationSystem).Return(&model.PromptTemplates{
+			ID:      "system-version-id",
+			Name:    model.PromptTemplateESGenerationSystem,
+			Version: 4,
+			Content: "あなたは就職活動の支援者です(v4)。",
+		}, nil)
+		m.generation.ExpectedCalls = nil
+		m.generation.On("Create", testifymock.Anything, testifymock.MatchedBy(func(g *model.Generations) bool {
+			return g.PromptVersionID != nil && *g.PromptVersionID == "generation-version-id" &&
+				g.SystemPromptVersionID != nil && *g.SystemPromptVersionID == "system-version-id" &&
+				g.ExtractionPromptVersionID != nil && *g.ExtractionPromptVersionID == "extraction-version-id"
+		})).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return strings.HasPrefix(input.Text, "以下の入力欄を分析してください(v3):\n")
+		})).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return strings.HasPrefix(input.Text, "株式会社テストの選考で「志望動機」に回答してください。\n") &&
+				input.SystemInstruction == "あなたは就職活動の支援者です(v4)。"
+		})).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "回答1", res[0].Answer)
+		m.generation.AssertExpectations(t)
+	})
+
+	t.Run("正常系:有効なバージョンがない場合はファイルのテンプレートを使い、バージョンは記録しない", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.generation.ExpectedCalls = nil
+		m.generation.On("Create", testifymock.Anything, testifymock.MatchedBy(func(g *model.Generations) bool {
+			return g.PromptVersionID == nil && g.SystemPromptVersionID == nil && g.ExtractionPromptVersionID == nil
+		})).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		m.generation.AssertExpectations(t)
+	})
+
+	t.Run("正常系:保存済みの設問を使う場合は、抽出したときのバージョンを記録する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		extractionVersionID := "extraction-version-id"
+		m.prompts.ExpectedCalls = nil
+		m.prompts.On("FindActive", testifymock.Anything, model.PromptTemplateExtractFormFields).Return(&model.PromptTemplates{
+			ID:      extractionVersionID,
+			Name:    model.PromptTemplateExtractFormFields,
+			Version: 3,
+			Content: "以下の入力欄を分析してください(v3):\n{{.Content}}",
+		}, nil)
+		m.prompts.On("FindActive", testifymock.Anything, testifymock.Anything).Return(nil, nil)
+		m.questions.ExpectedCalls = nil
+		m.questions.On("FindByHTMLHash", testifymock.Anything, testifymock.Anything).Return(&model.QuestionCaches{
+			Questions:       []model.ExtractedQuestion{{Question: "志望動機", FieldType: model.QuestionFieldTypeTextarea}},
+			PromptVersionID: &extractionVersionID,
+		}, nil)
+		m.generation.ExpectedCalls = nil
+		m.generation.On("Create", testifymock.Anything, testifymock.MatchedBy(func(g *model.Generations) bool {
+			return g.ExtractionPromptVersionID != nil && *g.ExtractionPromptVersionID == extractionVersionID
+		})).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		m.generation.AssertExpectations(t)
+	})
+
+	t.Run("正常系:保存後に抽出のテンプレートの有効なバージョンが変わった場合は抽出し直す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		oldVersionID := "old-extraction-version-id"
+		m.prompts.ExpectedCalls = nil
+		m.prompts.On("FindActive", testifymock.Anything, model.PromptTemplateExtractFormFields).Return(&model.PromptTemplates{
+			ID:      "extraction-version-id",
+			Name:    model.PromptTemplateExtractFormFields,
+			Version: 3,
+			Content: "以下の入力欄を分析してください(v3):\n{{.Content}}",
+		}, nil)
+		m.prompts.On("FindActive", testifymock.Anything, testifymock.Anything).Return(nil, nil)
+		m.questions.ExpectedCalls = nil
+		m.questions.On("FindByHTMLHash", testifymock.Anything, testifymock.Anything).Return(&model.QuestionCaches{
+			Questions:       []model.ExtractedQuestion{{Question: "古い設問", FieldType: model.QuestionFieldTypeTextarea}},
+			PromptVersionID: &oldVersionID,
+		}, nil)
+		m.questions.On("Upsert", testifymock.Anything, testifymock.MatchedBy(func(c *model.QuestionCaches) bool {
+			return c.PromptVersionID != nil && *c.PromptVersionID == "extraction-version-id"
+		})).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return strings.HasPrefix(input.Text, "以下の入力欄を分析してください(v3):\n")
+		})).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "志望動機", res[0].Question)
+		}
+		m.questions.AssertExpectations(t)
+	})
+
+	t.Run("異常系:有効なバージョンが不正な場合はファイルのテンプレートを使う", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.prompts.ExpectedCalls = nil
+		m.prompts.On("FindActive", testifymock.Anything, model.PromptTemplateESGeneration).Return(&model.PromptTemplates{
+			ID:      "generation-version-id",
+			Name:    model.PromptTemplateESGeneration,
+			Version: 2,
+			Content: "{{.Unknown}}",
+		}, nil)
+		m.prompts.On("FindActive", testifymock.Anything, testifymock.Anything).Return(nil, errors.New("db error"))
+		m.generation.ExpectedCalls = nil
+		m.generation.On("Create", testifymock.Anything, testifymock.MatchedBy(func(g *model.Generations) bool {
+			return g.PromptVersionID == nil
+		})).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		m.generation.AssertExpectations(t)
+	})
+}
+
+func TestLLMGenerateUsecase_QuestionCache(t *testing.T) {
+	cached := []model.ExtractedQuestion{
+		{Question: "志望動機", FieldType: model.QuestionFieldTypeTextarea},
+		{Question: "自己PR", FieldType: model.QuestionFieldTypeTextarea},
+	}
+
+	t.Run("正常系:保存済みの設問がある場合は質問抽出を省略する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.questions = new(mock.QuestionCacheRepositoryMock)
+		m.questions.On("FindByHTMLHash", testifymock.Anything, testifymock.Anything).Return(&model.QuestionCaches{Questions: cached}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("自己PR"))).Return(model.GeminiResponse{Text: "回答2"}, nil)
+
+		res, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), llmGenerateRequest)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		m.gemini.AssertNotCalled(t, "GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput))
+		m.questions.AssertNotCalled(t, "Upsert", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("正常系:抽出した設問をURLと合わせて保存し、入力済みの回答が違っても同じHTMLとして検索する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.questions = new(mock.QuestionCacheRepositoryMock)
+		m.questions.On("FindByHTMLHash", testifymock.Anything, testifymock.Anything).Return(nil, nil)
+		m.questions.On("Upsert", testifymock.Anything, testifymock.Anything).Return(nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isExtractInput)).Return(model.GeminiResponse{Text: questionsJSON("志望動機", "自己PR")}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("志望動機"))).Return(model.GeminiResponse{Text: "回答1"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isAnswerInput("自己PR"))).Return(model.GeminiResponse{Text: "回答2"}, nil)
+
+		req := llmGenerateRequest
+		req.PageURL = "https://example.com/entry"
+		_, err := m.usecase().LLMGenerate(test.SetupContextContext("test-user"), req)
+		assert.NoError(t, err)
+
+		var saved *model.QuestionCaches
+		m.questions.AssertCalled(t, "Upsert", testifymock.Anything, testifymock.MatchedBy(func(c *model.QuestionCaches) bool {
+			saved = c
+			return c.PageURL == "https://example.com/entry" && c.HTMLHash != "" && len(c.Questions) == 2
+		}))
+
+		_, err = m.usecase().CachedQuestions(test.SetupContextContext("test-user"), model.LLMCachedQuestionsRequest{HTML: "<textarea>書きかけの回答</textarea>"})
+		assert.NoError(t, err)
+		m.questions.AssertCalled(t, "FindByHTMLHash", testifymock.Anything, saved.HTMLHash)
+	})
+
+	t.Run("正常系:URLのみ指定された場合はURLで検索する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.questions.On("FindLatestByPageURL", testifymock.Anything, "https://example.com/entry").Return(&model.QuestionCaches{PageURL: "https://example.com/entry", Questions: cached}, nil)
+
+		res, err := m.usecase().CachedQuestions(test.SetupContextContext("test-user"), model.LLMCachedQuestionsRequest{PageURL: "https://example.com/entry"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, cached, res.Questions)
+	})
+}
+
+// isRefineInput は回答修正用のGemini呼び出しかどうかを判定する
+func isRefineInput(input model.GeminiInput) bool {
+	return strings.Contains(input.Text, "以下の指示に従って修正してください")
+}
+
+var llmRefineRequest = model.LLMRefineRequest{
+	Question:    "志望動機を教えてください",
+	Answer:      "元の回答",
+	Instruction: "もっと具体的にしてください",
+	CompanyName: "株式会社テスト",
+	CompanyID:   "1234567890123",
+}
+
+func TestLLMGenerateUsecase_RefineAnswer(t *testing.T) {
+	t.Run("正常系:生成時のプロンプトと回答を履歴として修正を依頼する", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isRefineInput(input) &&
+				strings.Contains(input.Text, "もっと具体的にしてください") &&
+				len(input.History) == 2 &&
+				input.History[0].Role == model.LLMMessageRoleUser &&
+				strings.Contains(input.History[0].Text, "「志望動機を教えてください」") &&
+				strings.Contains(input.History[0].Text, "テスト企業理念") &&
+				strings.Contains(input.History[0].Text, "テスト職歴") &&
+				input.History[1] == model.LLMMessage{Role: model.LLMMessageRoleModel, Text: "元の回答"}
+		})).Return(model.GeminiResponse{Text: "修正後の回答", InputTokens: 100, OutputTokens: 10}, nil)
+
+		res, err := m.usecase().RefineAnswer(test.SetupContextContext("test-user"), llmRefineRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.LLMRefineResponse{Question: "志望動機を教えてください", Answer: "修正後の回答", CharCount: 6}, res)
+		m.usage.AssertCalled(t, "Create", testifymock.Anything, testifymock.MatchedBy(func(usages []model.LLMUsages) bool {
+			return len(usages) == 1 && usages[0].Purpose == model.LLMUsagePurposeRefine && usages[0].InputTokens == 100
+		}))
+	})
+
+	t.Run("正常系:これまでの修正指示と回答を会話の続きとして渡す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isRefineInput(input) &&
+				strings.Contains(input.Text, "敬語を見直してください") &&
+				len(input.History) == 4 &&
+				input.History[2].Role == model.LLMMessageRoleUser &&
+				strings.Contains(input.History[2].Text, "もっと具体的にしてください") &&
+				input.History[3] == model.LLMMessage{Role: model.LLMMessageRoleModel, Text: "1回目の修正"}
+		})).Return(model.GeminiResponse{Text: "2回目の修正"}, nil)
+		req := llmRefineRequest
+		req.History = []model.LLMRefineTurn{{Instruction: "もっと具体的にしてください", Answer: "1回目の修正"}}
+		req.Instruction = "敬語を見直してください"
+
+		res, err := m.usecase().RefineAnswer(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2回目の修正", res.Answer)
+	})
+
+	t.Run("正常系:質問文の文字数制限に合わせて書き直す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isRefineInput(input) && strings.Contains(input.Text, "改行を除いて8字以上10字以内")
+		})).Return(model.GeminiResponse{Text: "私の強みは粘り強さです。"}, nil)
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isRewriteInput)).Return(model.GeminiResponse{Text: "強みは粘り強さです"}, nil)
+		req := llmRefineRequest
+		req.Question = "自己PRを教えてください（10字以内）"
+
+		res, err := m.usecase().RefineAnswer(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.LLMRefineResponse{Question: req.Question, Answer: "強みは粘り強さです", CharLimit: 10, CharCount: 9}, res)
+	})
+
+	t.Run("異常系:指定したプロフィールが存在しない場合", func(t *testing.T) {
+		const profileID = "123e4567-e89b-12d3-a456-426614174000"
+		m := newLLMGenerateMocks()
+		m.experience.On("GetExperienceByID", testifymock.Anything, profileID).Return(model.Experiences{}, gorm.ErrRecordNotFound)
+		req := llmRefineRequest
+		req.ProfileID = profileID
+
+		res, err := m.usecase().RefineAnswer(test.SetupContextContext("test-user"), req)
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceProfileNotFound)
+		assert.Nil(t, res)
+		m.gemini.AssertNotCalled(t, "GetGeminiRequest", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:修正の生成に失敗した場合", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isRefineInput)).Return(model.GeminiResponse{}, errors.New("gemini error"))
+
+		res, err := m.usecase().RefineAnswer(test.SetupContextContext("test-user"), llmRefineRequest)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+// isReviewInput は添削用のGemini呼び出しかどうかを判定する
+func isReviewInput(input model.GeminiInput) bool {
+	return strings.Contains(input.Text, "以下の回答を添削してください")
+}
+
+var llmReviewRequest = model.LLMReviewRequest{
+	Question:    "志望動機を教えてください（20字以内）",
+	Draft:       "私は非常に御社に興味があり、とても入社したいと思います。",
+	CompanyName: "株式会社テスト",
+	CompanyID:   "1234567890123",
+}
+
+func TestLLMGenerateUsecase_ReviewAnswer(t *testing.T) {
+	t.Run("正常系:評価軸ごとの点数と改善案、冗長な表現、文字数の判定を返す", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(func(input model.GeminiInput) bool {
+			return isReviewInput(input) && input.ResponseSchema != nil &&
+				strings.Contains(input.Text, llmReviewRequest.Draft) &&
+				strings.Contains(input.Text, "テスト企業理念") &&
+				strings.Contains(input.Text, "テスト職歴") &&
+				strings.Contains(input.Text, "28字(改行を除いて16字以上20字以内の制限)")
+		})).Return(model.GeminiResponse{Text: "```json\n" + `{"summary":"熱意は伝わります。",` +
+			`"scores":[{"axis":"expression","score":2,"comment":"冗長"},{"axis":"specificity","score":1,"comment":"具体性がない"},{"axis":"unknown","score":5,"comment":""}],` +
+			`"suggestions":["理由を具体的に書く",""],"fillerPhrases":["興味があり","存在しない表現"]}` + "\n```", InputTokens: 100, OutputTokens: 50}, nil)
+
+		res, err := m.usecase().ReviewAnswer(test.SetupContextContext("test-user"), llmReviewRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.LLMReviewResponse{
+			Question: llmReviewRequest.Question,
+			Scores: []model.LLMReviewScore{
+				{Axis: model.LLMReviewAxisSpecificity, Label: "具体性", Score: 1, Comment: "具体性がない"},
+				{Axis: model.LLMReviewAxisExpression, Label: "表現", Score: 2, Comment: "冗長"},
+			},
+			Total:       3,
+			Summary:     "熱意は伝わります。",
+			Suggestions: []string{"理由を具体的に書く"},
+			FillerPhrases: []model.LLMFillerPhrase{
+				{Phrase: "非常に", Count: 1},
+				{Phrase: "興味があり", Count: 1},
+				{Phrase: "とても", Count: 1},
+				{Phrase: "と思います", Count: 1},
+			},
+			CharLimit: model.LLMCharLimitCheck{Limit: 20, Min: 16, Count: 28, Status: model.LLMCharLimitStatusTooLong},
+		}, res)
+		m.usage.AssertCalled(t, "Create", testifymock.Anything, testifymock.MatchedBy(func(usages []model.LLMUsages) bool {
+			return len(usages) == 1 && usages[0].Purpose == model.LLMUsagePurposeReview
+		}))
+	})
+
+	t.Run("正常系:文字数制限がない場合", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isReviewInput)).Return(model.GeminiResponse{Text: `{"summary":"","scores":[{"axis":"structure","score":4,"comment":""}],"suggestions":[],"fillerPhrases":[]}`}, nil)
+		req := llmReviewRequest
+		req.Question = "志望動機を教えてください"
+		req.Draft = "御社の事業に惹かれました。"
+
+		res, err := m.usecase().ReviewAnswer(test.SetupContextContext("test-user"), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.LLMCharLimitCheck{Count: 13, Status: model.LLMCharLimitStatusNone}, res.CharLimit)
+		assert.Empty(t, res.FillerPhrases)
+		assert.Equal(t, 4, res.Total)
+	})
+
+	t.Run("異常系:添削結果を解析できない場合", func(t *testing.T) {
+		m := newLLMGenerateMocks()
+		m.gemini.On("GetGeminiRequest", testifymock.Anything, testifymock.MatchedBy(isReviewInput)).Return(model.GeminiResponse{Text: "添削できません"}, nil)
+
+		res, err := m.usecase().ReviewAnswer(test.SetupContextContext("test-user"), llmReviewRequest)
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("異常系:指定したプロフィールが存在しない場合", func(t *testing.T) {
+		const profileID = "123e4567-e89b-12d3-a456-426614174000"
+		m := newLLMGenerateMocks()
+		m.experience.On("GetExperienceByID", testifymock.Anything, profileID).Return(model.Experiences{}, gorm.ErrRecordNotFound)
+		req := llmReviewRequest
+		req.ProfileID = profileID
+
+		res, err := m.usecase().ReviewAnswer(test.SetupContextContext("test-user"), req)
+
+		assert.ErrorIs(t, err, usecase.ErrExperienceProfileNotFound)
+		assert.Nil(t, res)
+	})
+}
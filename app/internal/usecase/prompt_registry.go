@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"es-api/app/internal/entity/model"
+	db "es-api/app/internal/repository/db"
+)
+
+// promptGenerationData - 回答生成のプロンプトテンプレートに渡す値
+type promptGenerationData struct {
+	Question    string
+	CharLimit   int // 文字数制限。制限がない場合は0
+	CompanyName string
+}
+
+// promptExtractionData - 質問抽出のプロンプトテンプレートに渡す値
+type promptExtractionData struct {
+	Content string // 分析するHTML、または入力欄の情報
+}
+
+// promptSystemData - システムプロンプトに渡す値。埋め込む変数はない
+type promptSystemData struct{}
+
+// promptRepairData - 壊れたJSONの修復のプロンプトテンプレートに渡す値
+type promptRepairData struct {
+	Text string // 形式が崩れた質問抽出の結果
+}
+
+// promptReviewData - 添削のプロンプトテンプレートに渡す値
+type promptReviewData struct {
+	Question    string
+	Draft       string // 応募者が書いた回答
+	CharLimit   string // 文字数と文字数制限の説明
+	Axes        string // 評価軸の一覧
+	CompanyInfo string // 企業情報。ない場合は「なし」
+	Experience  string // 応募者の経歴情報。ない場合は「なし」
+}
+
+// promptJudgeData - 回答候補の採点のプロンプトテンプレートに渡す値
+type promptJudgeData struct {
+	Question    string
+	CharLimit   string // 文字数制限の説明。制限がない場合は「なし」
+	CompanyInfo string // 企業情報。ない場合は「なし」
+	Candidates  string // 番号と文字数を付けた回答候補の一覧
+}
+
+// promptRewriteData - 文字数制限に合わせた書き直しのプロンプトテンプレートに渡す値
+type promptRewriteData struct {
+	Question  string
+	Answer    string // 現在の回答
+	CharCount int    // 現在の回答の文字数
+	MinChars  int    // 文字数の下限
+	CharLimit int
+}
+
+// promptRefineData - 回答の修正のプロンプトテンプレートに渡す値
+type promptRefineData struct {
+	Instruction          string // ユーザーの修正指示
+	CharLimitInstruction string // 文字数制限の指示。制限がない場合は空
+}
+
+// promptTemplateDefinition - バージョン管理するプロンプトテンプレートごとの設定
+type promptTemplateDefinition struct {
+	file string // 有効なバージョンがない場合に使うprompts/のファイル
+	data any    // テンプレートに渡す値の型。作成時に空の値で実行して、存在しない変数を使っていないかを検証する
+}
+
+var promptTemplateDefinitions = map[model.PromptTemplateName]promptTemplateDefinition{
+	model.PromptTemplateESGeneration:       {file: "es_generation.txt", data: promptGenerationData{}},
+	model.PromptTemplateESGenerationSystem: {file: "es_generation_system.txt", data: promptSystemData{}},
+	model.PromptTemplateExtractQuestions:   {file: "extract_questions.txt", data: promptExtractionData{}},
+	model.PromptTemplateExtractFormFields:  {file: "extract_form_fields.txt", data: promptExtractionData{}},
+	model.PromptTemplateRepairQuestions:    {file: "repair_questions.txt", data: promptRepairData{}},
+	model.PromptTemplateReviewAnswer:       {file: "review_answer.txt", data: promptReviewData{}},
+	model.PromptTemplateJudgeAnswers:       {file: "judge_answers.txt", data: promptJudgeData{}},
+	model.PromptTemplateRewriteAnswer:      {file: "rewrite_answer.txt", data: promptRewriteData{}},
+	model.PromptTemplateRefineAnswer:       {file: "refine_answer.txt", data: promptRefineData{}},
+}
+
+// promptTemplate - 解析済みのプロンプトテンプレート
+type promptTemplate struct {
+	versionID string // DBのバージョンのID。ファイルのテンプレートの場合は空
+	tmpl      *template.Template
+}
+
+// render - 値を埋め込んだプロンプトを返す
+func (t *promptTemplate) render(data any) (string, error) {
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("プロンプトテンプレートの実行に失敗: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// versionIDPtr - 記録用のバージョンのID。ファイルのテンプレートの場合はnil
+func (t *promptTemplate) versionIDPtr() *string {
+	if t.versionID == "" {
+		return nil
+	}
+	id := t.versionID
+	return &id
+}
+
+// promptRegistry - プロンプトテンプレートを取得する
+// DBで有効にしたバージョンがあればそれを、なければprompts/のファイルを使う
+type promptRegistry struct {
+	repo db.PromptTemplateRepository
+}
+
+func newPromptRegistry(repo db.PromptTemplateRepository) *promptRegistry {
+	return &promptRegistry{repo: repo}
+}
+
+// load - 有効なプロンプトテンプレートを取得する
+// DBから取得できない場合や、有効なバージョンが壊れている場合はファイルのテンプレートを使う
+func (r *promptRegistry) load(ctx context.Context, name model.PromptTemplateName) (*promptTemplate, error) {
+	definition, ok := promptTemplateDefinitions[name]
+	if !ok {
+		return nil, fmt.Errorf("不明なプロンプトテンプレートです: %s", name)
+	}
+
+	active, err := r.repo.FindActive(ctx, name)
+	if err != nil {
+		log.Printf("プロンプトテンプレート%sの取得に失敗: %v, ファイルのテンプレートを使用します", name, err)
+	}
+	if active != nil {
+		tmpl, err := parsePromptTemplate(name, active.Content)
+		if err == nil {
+			return &promptTemplate{versionID: active.ID, tmpl: tmpl}, nil
+		}
+		log.Printf("プロンプトテンプレート%sのバージョン%dが不正です: %v, ファイルのテンプレートを使用します", name, active.Version, err)
+	}
+
+	content, err := loadPromptFromFile(definition.file)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトファイルの読み込みに失敗: %w", err)
+	}
+	tmpl, err := parsePromptTemplate(name, content)
+	if err != nil {
+		return nil, err
+	}
+	return &promptTemplate{tmpl: tmpl}, nil
+}
+
+// parsePromptTemplate - テンプレートを解析し、その種類で使える変数だけを使っているかを検証する
+func parsePromptTemplate(name model.PromptTemplateName, content string) (*template.Template, error) {
+	definition, ok := promptTemplateDefinitions[name]
+	if !ok {
+		return nil, fmt.Errorf("不明なプロンプトテンプレートです: %s", name)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("プロンプトテンプレートが空です")
+	}
+
+	tmpl, err := template.New(string(name)).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレートの構文が不正です: %w", err)
+	}
+	if err := tmpl.Execute(&strings.Builder{}, definition.data); err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレートで使えない変数が含まれています: %w", err)
+	}
+	return tmpl, nil
+}
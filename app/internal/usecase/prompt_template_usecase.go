@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+)
+
+var (
+	// ErrPromptTemplateNotFound - バージョン管理していない種類のプロンプトテンプレートを指定した
+	ErrPromptTemplateNotFound = errors.New("prompt template not found")
+	// ErrInvalidPromptTemplate - テンプレートの構文や変数が不正
+	ErrInvalidPromptTemplate = errors.New("invalid prompt template")
+	// ErrNoPreviousPromptVersion - 有効なバージョンの前に有効だったバージョンがないため、ロールバックできない
+	ErrNoPreviousPromptVersion = errors.New("no previous prompt template version")
+)
+
+type PromptTemplateUsecase interface {
+	ListVersions(ctx context.Context, name model.PromptTemplateName) ([]model.PromptTemplates, error)
+	CreateVersion(ctx context.Context, name model.PromptTemplateName, req model.CreatePromptTemplateRequest) (*model.PromptTemplates, error)
+	ActivateVersion(ctx context.Context, name model.PromptTemplateName, version int) (*model.PromptTemplates, error)
+	Rollback(ctx context.Context, name model.PromptTemplateName) (*model.PromptTemplates, error)
+}
+
+type promptTemplateUsecase struct {
+	ptr repository.PromptTemplateRepository
+}
+
+func NewPromptTemplateUsecase(r repository.PromptTemplateRepository) PromptTemplateUsecase {
+	return &promptTemplateUsecase{ptr: r}
+}
+
+// ListVersions - 全てのバージョンを新しい順に返す
+func (u *promptTemplateUsecase) ListVersions(ctx context.Context, name model.PromptTemplateName) ([]model.PromptTemplates, error) {
+	if _, ok := promptTemplateDefinitions[name]; !ok {
+		return nil, ErrPromptTemplateNotFound
+	}
+
+	templates, err := u.ptr.ListByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+	return templates, nil
+}
+
+// CreateVersion - テンプレートを検証し、新しいバージョンとして保存する。作成したバージョンはActivateVersionで有効にするまで使われない
+func (u *promptTemplateUsecase) CreateVersion(ctx context.Context, name model.PromptTemplateName, req model.CreatePromptTemplateRequest) (*model.PromptTemplates, error) {
+	if _, ok := promptTemplateDefinitions[name]; !ok {
+		return nil, ErrPromptTemplateNotFound
+	}
+	if _, err := parsePromptTemplate(name, req.Content); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+	}
+
+	template := &model.PromptTemplates{
+		Name:        name,
+		Content:     req.Content,
+		Description: req.Description,
+	}
+	if err := u.ptr.Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create prompt template: %w", err)
+	}
+	return template, nil
+}
+
+// ActivateVersion - 指定したバージョンを有効にする。バージョンが存在しない場合はnilを返す
+func (u *promptTemplateUsecase) ActivateVersion(ctx context.Context, name model.PromptTemplateName, version int) (*model.PromptTemplates, error) {
+	if _, ok := promptTemplateDefinitions[name]; !ok {
+		return nil, ErrPromptTemplateNotFound
+	}
+
+	template, err := u.ptr.Activate(ctx, name, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate prompt template: %w", err)
+	}
+	return template, nil
+}
+
+// Rollback - 有効なバージョンの直前に有効だったバージョンを有効にする
+// バージョン番号ではなく最後に有効にした日時で判断するので、一度も有効にしていない下書きのバージョンは有効にしない
+func (u *promptTemplateUsecase) Rollback(ctx context.Context, name model.PromptTemplateName) (*model.PromptTemplates, error) {
+	templates, err := u.ListVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	hasActive := false
+	var previous *model.PromptTemplates
+	for i, t := range templates {
+		if t.Active {
+			hasActive = true
+			continue
+		}
+		if t.ActivatedAt != nil && (previous == nil || t.ActivatedAt.After(*previous.ActivatedAt)) {
+			previous = &templates[i]
+		}
+	}
+	if !hasActive || previous == nil {
+		return nil, ErrNoPreviousPromptVersion
+	}
+	return u.ActivateVersion(ctx, name, previous.Version)
+}
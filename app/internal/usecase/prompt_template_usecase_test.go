@@ -0,0 +1,184 @@
+package usecase_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+	"es-api/app/test"
+	mock "es-api/app/test/mock/repository"
+)
+
+func TestPromptTemplateUsecase_CreateVersion(t *testing.T) {
+	t.Run("正常系:テンプレートの変数が正しい場合は保存する", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+		req := model.CreatePromptTemplateRequest{
+			Content:     "「{{.Question}}」に{{if .CharLimit}}{{.CharLimit}}字以内で{{end}}回答してください",
+			Description: "文字数制限を質問の直後に指示する",
+		}
+		mockRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(template *model.PromptTemplates) bool {
+			return template.Name == model.PromptTemplateESGeneration && template.Content == req.Content && template.Description == req.Description
+		})).Return(nil)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		template, err := uc.CreateVersion(test.SetupContextContext("admin-user"), model.PromptTemplateESGeneration, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, req.Content, template.Content)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系:prompts/のファイルはそのまま各種類のバージョンとして作成できる", func(t *testing.T) {
+		names := []model.PromptTemplateName{
+			model.PromptTemplateESGeneration,
+			model.PromptTemplateESGenerationSystem,
+			model.PromptTemplateExtractQuestions,
+			model.PromptTemplateExtractFormFields,
+			model.PromptTemplateRepairQuestions,
+			model.PromptTemplateReviewAnswer,
+			model.PromptTemplateJudgeAnswers,
+			model.PromptTemplateRewriteAnswer,
+			model.PromptTemplateRefineAnswer,
+		}
+		for _, name := range names {
+			content, err := os.ReadFile(filepath.Join("prompts", string(name)+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			mockRepo := new(mock.PromptTemplateRepositoryMock)
+			mockRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(nil)
+
+			uc := usecase.NewPromptTemplateUsecase(mockRepo)
+			_, err = uc.CreateVersion(test.SetupContextContext("admin-user"), name, model.CreatePromptTemplateRequest{Content: string(content)})
+
+			assert.NoError(t, err, name)
+		}
+	})
+
+	t.Run("異常系:その種類で使えない変数を含む場合", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		_, err := uc.CreateVersion(test.SetupContextContext("admin-user"), model.PromptTemplateExtractQuestions, model.CreatePromptTemplateRequest{
+			Content: "以下のHTMLを分析してください: {{.Question}}",
+		})
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidPromptTemplate)
+		mockRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:構文が不正な場合", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		_, err := uc.CreateVersion(test.SetupContextContext("admin-user"), model.PromptTemplateESGeneration, model.CreatePromptTemplateRequest{
+			Content: "「{{.Question」",
+		})
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidPromptTemplate)
+	})
+
+	t.Run("異常系:バージョン管理していない種類の場合", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		_, err := uc.CreateVersion(test.SetupContextContext("admin-user"), "unknown", model.CreatePromptTemplateRequest{Content: "採点してください"})
+
+		assert.ErrorIs(t, err, usecase.ErrPromptTemplateNotFound)
+	})
+}
+
+func TestPromptTemplateUsecase_Rollback(t *testing.T) {
+	// activatedは有効にした順のバージョン。最後のバージョンが有効
+	versions := func(activated ...int) []model.PromptTemplates {
+		templates := []model.PromptTemplates{
+			{Name: model.PromptTemplateESGeneration, Version: 5},
+			{Name: model.PromptTemplateESGeneration, Version: 4},
+			{Name: model.PromptTemplateESGeneration, Version: 3},
+			{Name: model.PromptTemplateESGeneration, Version: 2},
+			{Name: model.PromptTemplateESGeneration, Version: 1},
+		}
+		base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i, version := range activated {
+			activatedAt := base.Add(time.Duration(i) * time.Hour)
+			for j := range templates {
+				if templates[j].Version == version {
+					templates[j].ActivatedAt = &activatedAt
+					templates[j].Active = i == len(activated)-1
+				}
+			}
+		}
+		return templates
+	}
+
+	t.Run("正常系:有効なバージョンの直前に有効だったバージョンを有効にする", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+		mockRepo.On("ListByName", testifymock.Anything, model.PromptTemplateESGeneration).Return(versions(1, 2, 3), nil)
+		expected := &model.PromptTemplates{Name: model.PromptTemplateESGeneration, Version: 2, Active: true}
+		mockRepo.On("Activate", testifymock.Anything, model.PromptTemplateESGeneration, 2).Return(expected, nil)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		template, err := uc.Rollback(test.SetupContextContext("admin-user"), model.PromptTemplateESGeneration)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, template)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系:番号を飛ばして有効にした場合は、間の有効にしていないバージョンではなく直前に有効だったバージョンに戻す", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+		mockRepo.On("ListByName", testifymock.Anything, model.PromptTemplateESGeneration).Return(versions(1, 2, 5), nil)
+		expected := &model.PromptTemplates{Name: model.PromptTemplateESGeneration, Version: 2, Active: true}
+		mockRepo.On("Activate", testifymock.Anything, model.PromptTemplateESGeneration, 2).Return(expected, nil)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		template, err := uc.Rollback(test.SetupContextContext("admin-user"), model.PromptTemplateESGeneration)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, template)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Activate", testifymock.Anything, model.PromptTemplateESGeneration, 4)
+	})
+
+	t.Run("正常系:ロールバックした後は、最後に有効にした日時が新しいバージョンに戻す", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+		// 1→3→2の順に有効にした場合、2の前に有効だったのは3
+		mockRepo.On("ListByName", testifymock.Anything, model.PromptTemplateESGeneration).Return(versions(1, 3, 2), nil)
+		expected := &model.PromptTemplates{Name: model.PromptTemplateESGeneration, Version: 3, Active: true}
+		mockRepo.On("Activate", testifymock.Anything, model.PromptTemplateESGeneration, 3).Return(expected, nil)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		template, err := uc.Rollback(test.SetupContextContext("admin-user"), model.PromptTemplateESGeneration)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, template)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系:他に有効にしたことのあるバージョンがない場合", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+		mockRepo.On("ListByName", testifymock.Anything, model.PromptTemplateESGeneration).Return(versions(3), nil)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		_, err := uc.Rollback(test.SetupContextContext("admin-user"), model.PromptTemplateESGeneration)
+
+		assert.ErrorIs(t, err, usecase.ErrNoPreviousPromptVersion)
+		mockRepo.AssertNotCalled(t, "Activate", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	})
+
+	t.Run("異常系:有効なバージョンがない場合", func(t *testing.T) {
+		mockRepo := new(mock.PromptTemplateRepositoryMock)
+		mockRepo.On("ListByName", testifymock.Anything, model.PromptTemplateESGeneration).Return(versions(), nil)
+
+		uc := usecase.NewPromptTemplateUsecase(mockRepo)
+		_, err := uc.Rollback(test.SetupContextContext("admin-user"), model.PromptTemplateESGeneration)
+
+		assert.ErrorIs(t, err, usecase.ErrNoPreviousPromptVersion)
+	})
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"es-api/app/internal/entity/model"
+	llm "es-api/app/internal/repository/llm"
+)
+
+// extractedQuestionsSchema - 質問抽出でLLMに出力させるJSONのスキーマ
+var extractedQuestionsSchema = &model.LLMSchema{
+	Type: model.LLMSchemaTypeObject,
+	Properties: map[string]*model.LLMSchema{
+		"questions": {
+			Type: model.LLMSchemaTypeArray,
+			Items: &model.LLMSchema{
+				Type: model.LLMSchemaTypeObject,
+				Properties: map[string]*model.LLMSchema{
+					"question":  {Type: model.LLMSchemaTypeString, Description: "質問文"},
+					"charLimit": {Type: model.LLMSchemaTypeInteger, Description: "文字数制限。ない場合は0"},
+					"fieldType": {
+						Type: model.LLMSchemaTypeString,
+						Enum: []string{string(model.QuestionFieldTypeTextarea), string(model.QuestionFieldTypeText)},
+					},
+					"name": {Type: model.LLMSchemaTypeString, Description: "入力欄のname属性"},
+					"id":   {Type: model.LLMSchemaTypeString, Description: "入力欄のid属性"},
+				},
+				Required: []string{"question", "charLimit", "fieldType", "name", "id"},
+			},
+		},
+	},
+	Required: []string{"questions"},
+}
+
+var errInvalidQuestionsJSON = errors.New("質問抽出の結果がJSONとして解析できません")
+
+// extractQuestionsFromHTML - HTMLから設問をJSONで抽出する
+// 同じHTMLから抽出した設問が保存されていれば、抽出せずにそれを返す。抽出した設問はページのURLと合わせて保存する
+// 保存後に抽出のプロンプトテンプレートの有効なバージョンが変わった場合は、保存済みの設問を使わずに抽出し直す
+// 抽出に使ったプロンプトテンプレートのバージョンも返す
+func (u *llmGenerateUsecase) extractQuestionsFromHTML(ctx context.Context, html string, pageURL string, usage *usageRecorder) ([]model.ExtractedQuestion, *string, error) {
+	// HTMLが空の場合はエラー
+	if html == "" {
+		return nil, nil, fmt.Errorf("HTMLが空です")
+	}
+
+	form, err := parseForm(html)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cache, err := u.questionCacheRepo.FindByHTMLHash(ctx, form.hash)
+	if err != nil {
+		// 保存済みの設問が使えなくても抽出はできるので、エラーはログに記録するのみ
+		log.Printf("保存済みの設問の検索に失敗しました: %v", err)
+	}
+	if cache != nil && len(cache.Questions) > 0 {
+		current, err := u.extractionPromptVersionID(ctx, form)
+		if err != nil {
+			return nil, nil, err
+		}
+		if equalVersionID(cache.PromptVersionID, current) {
+			log.Printf("保存済みの設問%d件を使用します", len(cache.Questions))
+			return cache.Questions, cache.PromptVersionID, nil
+		}
+		log.Printf("抽出のプロンプトテンプレートのバージョンが変わったため、設問を抽出し直します")
+	}
+
+	questions, promptVersionID, err := u.extractQuestions(ctx, form, usage)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(questions) > 0 {
+		err := u.questionCacheRepo.Upsert(ctx, &model.QuestionCaches{
+			HTMLHash:        form.hash,
+			PageURL:         pageURL,
+			Questions:       questions,
+			PromptVersionID: promptVersionID,
+		})
+		if err != nil {
+			log.Printf("抽出した設問の保存に失敗しました: %v", err)
+		}
+	}
+	return questions, promptVersionID, nil
+}
+
+// CachedQuestions - 保存済みの設問を返す。HTMLを指定した場合はHTMLで、それ以外はURLで最新のものを検索する
+// 生成を始める前に設問を表示するためのもので、保存されていない場合も抽出はせずにnilを返す
+func (u *llmGenerateUsecase) CachedQuestions(ctx context.Context, req model.LLMCachedQuestionsRequest) (*model.QuestionCaches, error) {
+	if req.HTML != "" {
+		form, err := parseForm(req.HTML)
+		if err != nil {
+			return nil, err
+		}
+		cache, err := u.questionCacheRepo.FindByHTMLHash(ctx, form.hash)
+		if err != nil {
+			return nil, fmt.Errorf("保存済みの設問の検索に失敗しました: %w", err)
+		}
+		return cache, nil
+	}
+	if req.PageURL != "" {
+		cache, err := u.questionCacheRepo.FindLatestByPageURL(ctx, req.PageURL)
+		if err != nil {
+			return nil, fmt.Errorf("保存済みの設問の検索に失敗しました: %w", err)
+		}
+		return cache, nil
+	}
+	return nil, nil
+}
+
+// extractQuestions - 解析したHTMLから設問を抽出する
+// 質問文が一意に決まる単純なフォームであればLLMを使わない
+// それ以外は入力欄ごとの情報(入力欄が見つからない場合はscript・styleなどを除いたHTML)をLLMに渡す
+// 応答が壊れたJSONだった場合は、ローカルでの修復を試し、それでも解析できなければLLMに1回だけ修復させる
+// LLMを使った場合は、使ったプロンプトテンプレートのバージョンも返す
+func (u *llmGenerateUsecase) extractQuestions(ctx context.Context, form *parsedForm, usage *usageRecorder) ([]model.ExtractedQuestion, *string, error) {
+	if questions, ok := form.directQuestions(); ok {
+		log.Printf("HTMLの解析のみで%d件の質問を抽出しました", len(questions))
+		return validateExtractedQuestions(questions), nil, nil
+	}
+
+	promptName, content := extractionPrompt(form)
+	promptTemplate, err := u.prompts.load(ctx, promptName)
+	if err != nil {
+		return nil, nil, err
+	}
+	prompt, err := promptTemplate.render(promptExtractionData{Content: content})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// HTML解析は軽量モデルで十分
+	extractModel := modelFromEnv("LLM_EXTRACTION_MODEL", model.GeminiFlashLite)
+	provider, err := u.llmRegistry.Resolve(extractModel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	llmResponse, err := provider.Generate(ctx, model.LLMInput{
+		Model:          extractModel,
+		Text:           prompt,
+		ResponseSchema: extractedQuestionsSchema,
+		Params:         u.params.forPurpose(model.LLMUsagePurposeExtraction),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("質問抽出エラー: %w", err)
+	}
+	usage.add(model.LLMUsagePurposeExtraction, extractModel, llmResponse)
+
+	questions, err := parseExtractedQuestions(llmResponse.Text)
+	if err != nil {
+		log.Printf("質問抽出の結果をLLMで修復します: %v", err)
+		questions, err = u.repairExtractedQuestions(ctx, provider, extractModel, llmResponse.Text, usage)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return validateExtractedQuestions(questions), promptTemplate.versionIDPtr(), nil
+}
+
+// extractionPrompt - フォームからの設問抽出に使うプロンプトテンプレートの種類と、LLMに渡す内容
+// 入力欄があれば入力欄ごとの情報を、なければscript・styleなどを除いたHTMLを渡す
+func extractionPrompt(form *parsedForm) (model.PromptTemplateName, string) {
+	if len(form.fields) == 0 {
+		return model.PromptTemplateExtractQuestions, form.stripped
+	}
+	return model.PromptTemplateExtractFormFields, form.describe()
+}
+
+// extractionPromptVersionID - フォームから今抽出する場合に使うプロンプトテンプレートのバージョン
+// LLMを使わずに抽出できる場合や、ファイルのテンプレートを使う場合はnil
+func (u *llmGenerateUsecase) extractionPromptVersionID(ctx context.Context, form *parsedForm) (*string, error) {
+	if _, ok := form.directQuestions(); ok {
+		return nil, nil
+	}
+	promptName, _ := extractionPrompt(form)
+	promptTemplate, err := u.prompts.load(ctx, promptName)
+	if err != nil {
+		return nil, err
+	}
+	return promptTemplate.versionIDPtr(), nil
+}
+
+// equalVersionID - 記録したプロンプトテンプレートのバージョンが同じかどうか。どちらもnilの場合も同じとみなす
+func equalVersionID(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// repairExtractedQuestions - 壊れたJSONをLLMにスキーマ通りのJSONへ直させる
+func (u *llmGenerateUsecase) repairExtractedQuestions(ctx context.Context, provider llm.LLMProvider, extractModel model.LLMModel, broken string, usage *usageRecorder) ([]model.ExtractedQuestion, error) {
+	promptTemplate, err := u.prompts.load(ctx, model.PromptTemplateRepairQuestions)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := promptTemplate.render(promptRepairData{Text: broken})
+	if err != nil {
+		return nil, err
+	}
+
+	llmResponse, err := provider.Generate(ctx, model.LLMInput{
+		Model:          extractModel,
+		Text:           prompt,
+		ResponseSchema: extractedQuestionsSchema,
+		Params:         u.params.forPurpose(model.LLMUsagePurposeExtraction),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("質問抽出結果の修復エラー: %w", err)
+	}
+	usage.add(model.LLMUsagePurposeExtraction, extractModel, llmResponse)
+
+	return parseExtractedQuestions(llmResponse.Text)
+}
+
+var codeFencePattern = regexp.MustCompile("(?s)```(?:json)?\\s*(.*?)\\s*```")
+
+// trailingCommaPattern - JSONとしては不正な、閉じ括弧直前のカンマ
+var trailingCommaPattern = regexp.MustCompile(`,\s*([\]}])`)
+
+// parseExtractedQuestions - LLMの応答を解析する。前置きの文章やコードブロック、末尾のカンマは取り除いてから解析する
+// {"questions": [...]} の形式に加えて、配列だけが返ってきた場合も受け付ける
+func parseExtractedQuestions(text string) ([]model.ExtractedQuestion, error) {
+	candidates := []string{text}
+	if m := codeFencePattern.FindStringSubmatch(text); m != nil {
+		candidates = append(candidates, m[1])
+	}
+	if start, end := strings.IndexAny(text, "{["), strings.LastIndexAny(text, "}]"); start >= 0 && end > start {
+		candidates = append(candidates, text[start:end+1])
+	}
+
+	for _, candidate := range candidates {
+		for _, c := range []string{candidate, trailingCommaPattern.ReplaceAllString(candidate, "$1")} {
+			var wrapped struct {
+				Questions []model.ExtractedQuestion `json:"questions"`
+			}
+			if err := json.Unmarshal([]byte(c), &wrapped); err == nil && wrapped.Questions != nil {
+				return wrapped.Questions, nil
+			}
+			var list []model.ExtractedQuestion
+			if err := json.Unmarshal([]byte(c), &list); err == nil {
+				return list, nil
+			}
+		}
+	}
+	return nil, errInvalidQuestionsJSON
+}
+
+// validateExtractedQuestions - 空の質問と重複を取り除き、欠けている値を補う
+func validateExtractedQuestions(questions []model.ExtractedQuestion) []model.ExtractedQuestion {
+	type key struct{ question, name, id string }
+	seen := make(map[key]bool, len(questions))
+
+	valid := make([]model.ExtractedQuestion, 0, len(questions))
+	for _, q := range questions {
+		q.Question = strings.TrimSpace(q.Question)
+		q.Name = strings.TrimSpace(q.Name)
+		q.ID = strings.TrimSpace(q.ID)
+		if q.Question == "" {
+			continue
+		}
+		k := key{q.Question, q.Name, q.ID}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		if q.FieldType != model.QuestionFieldTypeText {
+			q.FieldType = model.QuestionFieldTypeTextarea
+		}
+		if q.CharLimit <= 0 {
+			q.CharLimit = parseCharLimit(q.Question)
+		}
+		valid = append(valid, q)
+	}
+	return valid
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+
+	"es-api/app/internal/entity/model"
+)
+
+// researchDimension - 企業情報の調査項目。prompts/research_dimensions.jsonで定義する
+type researchDimension struct {
+	Name            model.ResearchSection `json:"name"`            // 保存時のキー。出典の項目名にも使う
+	Label           string                `json:"label"`           // プロンプトの見出し
+	Queries         []string              `json:"queries"`         // 検索クエリのテンプレート。{{.CompanyName}}が企業名に置き換わる
+	FallbackQueries []string              `json:"fallbackQueries"` // Queriesで回答が得られなかった場合に使うクエリのテンプレート
+
+	templates []*template.Template
+}
+
+// researchQueryData - 検索クエリのテンプレートに渡す値
+type researchQueryData struct {
+	CompanyName string
+}
+
+// defaultResearchDimensions - 設定ファイルを読み込めない場合に使う調査項目
+var defaultResearchDimensions = []researchDimension{
+	{
+		Name:            model.ResearchSectionPhilosophy,
+		Label:           "企業理念・バリュー",
+		Queries:         []string{"{{.CompanyName}} 企業理念 ミッション 価値観 経営理念"},
+		FallbackQueries: []string{"{{.CompanyName}} 理念 目指すもの"},
+	},
+	{
+		Name:            model.ResearchSectionTalentNeeds,
+		Label:           "求める人材像",
+		Queries:         []string{"{{.CompanyName}} 求める人材 採用 人物像 採用基準"},
+		FallbackQueries: []string{"{{.CompanyName}} 採用情報 募集要項"},
+	},
+	{
+		Name:            model.ResearchSectionCareerPath,
+		Label:           "キャリアパス",
+		Queries:         []string{"{{.CompanyName}} 社員 キャリアパス キャリア形成 成長機会 研修"},
+		FallbackQueries: []string{"{{.CompanyName}} 社員インタビュー キャリア"},
+	},
+}
+
+// loadResearchDimensions - 調査項目の設定を読み込む
+// COMPANY_RESEARCH_DIMENSIONS_FILEが設定されている場合はそのファイルを、ない場合はprompts/research_dimensions.jsonを使う
+func loadResearchDimensions() []researchDimension {
+	var content []byte
+	var err error
+	if path := os.Getenv("COMPANY_RESEARCH_DIMENSIONS_FILE"); path != "" {
+		content, err = os.ReadFile(path)
+	} else {
+		var text string
+		text, err = loadPromptFromFile("research_dimensions.json")
+		content = []byte(text)
+	}
+
+	if err == nil {
+		var dimensions []researchDimension
+		dimensions, err = parseResearchDimensions(content)
+		if err == nil {
+			return dimensions
+		}
+	}
+
+	log.Printf("調査項目の設定の読み込みに失敗: %v, デフォルトの調査項目を使用します", err)
+	dimensions, err := compileResearchDimensions(defaultResearchDimensions)
+	if err != nil {
+		panic(fmt.Sprintf("デフォルトの調査項目が不正です: %v", err))
+	}
+	return dimensions
+}
+
+// parseResearchDimensions - JSONの調査項目の設定を読み込み、内容を検証する
+func parseResearchDimensions(content []byte) ([]researchDimension, error) {
+	var dimensions []researchDimension
+	if err := json.Unmarshal(content, &dimensions); err != nil {
+		return nil, fmt.Errorf("調査項目の設定のJSONが不正です: %w", err)
+	}
+	return compileResearchDimensions(dimensions)
+}
+
+// compileResearchDimensions - 調査項目を検証し、検索クエリのテンプレートを解析する
+func compileResearchDimensions(dimensions []researchDimension) ([]researchDimension, error) {
+	if len(dimensions) == 0 {
+		return nil, errors.New("調査項目が1つもありません")
+	}
+
+	compiled := make([]researchDimension, 0, len(dimensions))
+	seen := make(map[model.ResearchSection]bool, len(dimensions))
+	for _, d := range dimensions {
+		if d.Name == "" || d.Label == "" {
+			return nil, errors.New("調査項目にはnameとlabelが必要です")
+		}
+		if seen[d.Name] {
+			return nil, fmt.Errorf("調査項目の名前が重複しています: %s", d.Name)
+		}
+		seen[d.Name] = true
+		if len(d.Queries) == 0 {
+			return nil, fmt.Errorf("調査項目%sに検索クエリがありません", d.Name)
+		}
+
+		d.templates = nil
+		for _, query := range append(append([]string{}, d.Queries...), d.FallbackQueries...) {
+			tmpl, err := template.New(string(d.Name)).Option("missingkey=error").Parse(query)
+			if err != nil {
+				return nil, fmt.Errorf("調査項目%sの検索クエリが不正です: %w", d.Name, err)
+			}
+			d.templates = append(d.templates, tmpl)
+		}
+		compiled = append(compiled, d)
+	}
+	return compiled, nil
+}
+
+// queries - 企業名を埋め込んだ検索クエリを、試す順番に返す
+func (d researchDimension) queries(companyName string) []string {
+	queries := make([]string, 0, len(d.templates))
+	for _, tmpl := range d.templates {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, researchQueryData{CompanyName: companyName}); err != nil {
+			log.Printf("調査項目%sの検索クエリの生成に失敗: %v", d.Name, err)
+			continue
+		}
+		queries = append(queries, sb.String())
+	}
+	return queries
+}
+
+// researchSections - 調査項目の名前の一覧
+func researchSections(dimensions []researchDimension) []model.ResearchSection {
+	sections := make([]model.ResearchSection, 0, len(dimensions))
+	for _, d := range dimensions {
+		sections = append(sections, d.Name)
+	}
+	return sections
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"es-api/app/internal/entity/model"
+	repository "es-api/app/internal/repository/db"
+)
+
+// ErrQuotaExceeded - 月間の上限に達している
+var ErrQuotaExceeded = errors.New("今月の利用上限に達しました")
+
+// 月の区切りは日本時間で判定する
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+type UsageUsecase interface {
+	GetUsage(ctx context.Context) (*model.UsageSummary, error)
+	// CheckQuota - 当月の使用量を返す。上限に達している場合はErrQuotaExceededも返す
+	CheckQuota(ctx context.Context) (*model.UsageSummary, error)
+}
+
+type usageUsecase struct {
+	ur    repository.UsageRepository
+	quota model.UsageQuota
+	now   func() time.Time
+}
+
+func NewUsageUsecase(r repository.UsageRepository, quota model.UsageQuota) UsageUsecase {
+	return &usageUsecase{
+		ur:    r,
+		quota: quota,
+		now:   time.Now,
+	}
+}
+
+// UsageQuotaFromEnv - MONTHLY_TOKEN_QUOTAとMONTHLY_REQUEST_QUOTAから上限を読み込む。未設定は無制限
+func UsageQuotaFromEnv() model.UsageQuota {
+	return model.UsageQuota{
+		MonthlyTokens:   int64FromEnv("MONTHLY_TOKEN_QUOTA"),
+		MonthlyRequests: int64FromEnv("MONTHLY_REQUEST_QUOTA"),
+	}
+}
+
+func int64FromEnv(key string) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		log.Printf("%sの値が不正です: %s, 無制限として扱います", key, v)
+		return 0
+	}
+	return n
+}
+
+func (u *usageUsecase) GetUsage(ctx context.Context) (*model.UsageSummary, error) {
+	now := u.now().In(jst)
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, jst)
+
+	byModel, err := u.ur.SumByModel(ctx, periodStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum usage: %w", err)
+	}
+	requests, err := u.ur.CountRequests(ctx, periodStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count requests: %w", err)
+	}
+
+	var tokens int64
+	for _, m := range byModel {
+		tokens += m.InputTokens + m.OutputTokens
+	}
+	if byModel == nil {
+		byModel = []model.LLMUsageByModel{}
+	}
+
+	return &model.UsageSummary{
+		PeriodStart:       periodStart,
+		PeriodEnd:         periodStart.AddDate(0, 1, 0),
+		UsedTokens:        tokens,
+		UsedRequests:      requests,
+		TokenQuota:        u.quota.MonthlyTokens,
+		RequestQuota:      u.quota.MonthlyRequests,
+		RemainingTokens:   remaining(u.quota.MonthlyTokens, tokens),
+		RemainingRequests: remaining(u.quota.MonthlyRequests, requests),
+		ByModel:           byModel,
+	}, nil
+}
+
+func (u *usageUsecase) CheckQuota(ctx context.Context) (*model.UsageSummary, error) {
+	summary, err := u.GetUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if summary.Exceeded() {
+		return summary, ErrQuotaExceeded
+	}
+	return summary, nil
+}
+
+// remaining - 上限が0(無制限)の場合はnilを返す
+func remaining(quota int64, used int64) *int64 {
+	if quota == 0 {
+		return nil
+	}
+	r := quota - used
+	if r < 0 {
+		r = 0
+	}
+	return &r
+}
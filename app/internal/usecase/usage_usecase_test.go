@@ -0,0 +1,76 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+	"es-api/app/test"
+	mock "es-api/app/test/mock/repository"
+)
+
+func TestUsageUsecase_GetUsage(t *testing.T) {
+	t.Run("正常系:モデルごとの使用量を合計する", func(t *testing.T) {
+		mockRepo := new(mock.UsageRepositoryMock)
+		mockRepo.On("SumByModel", testifymock.Anything, testifymock.Anything).Return([]model.LLMUsageByModel{
+			{Model: "gemini-2.0-flash", InputTokens: 100, OutputTokens: 50, Calls: 2},
+			{Model: "gemini-2.0-flash-lite", InputTokens: 30, OutputTokens: 20, Calls: 1},
+		}, nil)
+		mockRepo.On("CountRequests", testifymock.Anything, testifymock.Anything).Return(int64(3), nil)
+
+		uc := usecase.NewUsageUsecase(mockRepo, model.UsageQuota{MonthlyTokens: 1000})
+		res, err := uc.GetUsage(test.SetupContextContext("test-user"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(200), res.UsedTokens)
+		assert.Equal(t, int64(3), res.UsedRequests)
+		assert.Equal(t, int64(800), *res.RemainingTokens)
+		assert.Nil(t, res.RemainingRequests)
+		assert.Equal(t, 1, res.PeriodStart.Day())
+		assert.Equal(t, res.PeriodStart.AddDate(0, 1, 0), res.PeriodEnd)
+		assert.False(t, res.Exceeded())
+	})
+
+	t.Run("異常系:リポジトリでエラーが発生した場合", func(t *testing.T) {
+		mockRepo := new(mock.UsageRepositoryMock)
+		mockRepo.On("SumByModel", testifymock.Anything, testifymock.Anything).Return(nil, errors.New("db error"))
+
+		uc := usecase.NewUsageUsecase(mockRepo, model.UsageQuota{})
+		res, err := uc.GetUsage(test.SetupContextContext("test-user"))
+
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestUsageUsecase_CheckQuota(t *testing.T) {
+	t.Run("正常系:上限が未設定の場合は常に許可する", func(t *testing.T) {
+		mockRepo := new(mock.UsageRepositoryMock)
+		mockRepo.On("SumByModel", testifymock.Anything, testifymock.Anything).Return([]model.LLMUsageByModel{
+			{Model: "gemini-2.0-flash", InputTokens: 1000000, OutputTokens: 1000000, Calls: 100},
+		}, nil)
+		mockRepo.On("CountRequests", testifymock.Anything, testifymock.Anything).Return(int64(100), nil)
+
+		uc := usecase.NewUsageUsecase(mockRepo, model.UsageQuota{})
+		_, err := uc.CheckQuota(test.SetupContextContext("test-user"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("異常系:リクエスト数の上限に達している場合", func(t *testing.T) {
+		mockRepo := new(mock.UsageRepositoryMock)
+		mockRepo.On("SumByModel", testifymock.Anything, testifymock.Anything).Return([]model.LLMUsageByModel{}, nil)
+		mockRepo.On("CountRequests", testifymock.Anything, testifymock.Anything).Return(int64(10), nil)
+
+		uc := usecase.NewUsageUsecase(mockRepo, model.UsageQuota{MonthlyTokens: 1000, MonthlyRequests: 10})
+		res, err := uc.CheckQuota(test.SetupContextContext("test-user"))
+
+		assert.ErrorIs(t, err, usecase.ErrQuotaExceeded)
+		assert.Equal(t, int64(0), *res.RemainingRequests)
+		assert.Equal(t, int64(1000), *res.RemainingTokens)
+	})
+}
@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminMiddleware - ADMIN_USER_IDS(カンマ区切り)に含まれるユーザー以外のリクエストを403で拒否する
+// 認証ミドルウェアの後に使う。ADMIN_USER_IDSが未設定の場合は全てのリクエストを拒否する
+func AdminMiddleware() echo.MiddlewareFunc {
+	adminUserIDs := make(map[string]bool)
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			adminUserIDs[id] = true
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, _ := c.Get("userID").(string)
+			if !adminUserIDs[userID] {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "admin permission required",
+				})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package admin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"es-api/app/middleware/admin"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	next := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+
+	t.Run("正常系:管理者のユーザーは処理を続ける", func(t *testing.T) {
+		t.Setenv("ADMIN_USER_IDS", "admin-1, admin-2")
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/api/admin/companies/1234567890123/research", nil), rec)
+		c.Set("userID", "admin-2")
+
+		err := admin.AdminMiddleware()(next)(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("異常系:管理者でないユーザーは403を返す", func(t *testing.T) {
+		t.Setenv("ADMIN_USER_IDS", "admin-1")
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/api/admin/companies/1234567890123/research", nil), rec)
+		c.Set("userID", "test-user")
+
+		err := admin.AdminMiddleware()(next)(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("異常系:ADMIN_USER_IDSが未設定の場合は403を返す", func(t *testing.T) {
+		t.Setenv("ADMIN_USER_IDS", "")
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/api/admin/companies/1234567890123/research", nil), rec)
+		c.Set("userID", "")
+
+		err := admin.AdminMiddleware()(next)(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
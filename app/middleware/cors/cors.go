@@ -7,9 +7,16 @@ import (
 
 func SetupCORS(e *echo.Echo) echo.MiddlewareFunc {
 	return middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"POST", "GET", "OPTIONS"},
-		AllowHeaders:     []string{"Authorization", "Content-Type", "idp"},
+		AllowOrigins: []string{"*"},
+		AllowMethods: []string{"POST", "GET", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"Authorization", "Content-Type", "idp"},
+		ExposeHeaders: []string{
+			"X-Quota-Tokens-Limit",
+			"X-Quota-Tokens-Remaining",
+			"X-Quota-Requests-Limit",
+			"X-Quota-Requests-Remaining",
+			"X-Quota-Reset",
+		},
 		AllowCredentials: true,
 	})
 }
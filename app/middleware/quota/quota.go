@@ -0,0 +1,67 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"es-api/app/internal/contextKey"
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+)
+
+const (
+	HeaderTokensLimit       = "X-Quota-Tokens-Limit"
+	HeaderTokensRemaining   = "X-Quota-Tokens-Remaining"
+	HeaderRequestsLimit     = "X-Quota-Requests-Limit"
+	HeaderRequestsRemaining = "X-Quota-Requests-Remaining"
+	HeaderReset             = "X-Quota-Reset"
+)
+
+// QuotaMiddleware - 月間の上限に達しているユーザーのリクエストを429で拒否する
+// 上限が設定されている項目については、残りの量をレスポンスヘッダーに付与する
+func QuotaMiddleware(usageUsecase usecase.UsageUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			idp := c.Request().Header.Get("idp")
+			userID := c.Get("userID")
+			ctx = context.WithValue(ctx, contextKey.IDPKey, idp)
+			ctx = context.WithValue(ctx, contextKey.UserIDKey, userID)
+
+			summary, err := usageUsecase.CheckQuota(ctx)
+			if err != nil && !errors.Is(err, usecase.ErrQuotaExceeded) {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": err.Error(),
+				})
+			}
+
+			setQuotaHeaders(c, summary)
+
+			if errors.Is(err, usecase.ErrQuotaExceeded) {
+				return c.JSON(http.StatusTooManyRequests, map[string]string{
+					"error": err.Error(),
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+func setQuotaHeaders(c echo.Context, summary *model.UsageSummary) {
+	header := c.Response().Header()
+	if summary.RemainingTokens != nil {
+		header.Set(HeaderTokensLimit, strconv.FormatInt(summary.TokenQuota, 10))
+		header.Set(HeaderTokensRemaining, strconv.FormatInt(*summary.RemainingTokens, 10))
+	}
+	if summary.RemainingRequests != nil {
+		header.Set(HeaderRequestsLimit, strconv.FormatInt(summary.RequestQuota, 10))
+		header.Set(HeaderRequestsRemaining, strconv.FormatInt(*summary.RemainingRequests, 10))
+	}
+	if summary.RemainingTokens != nil || summary.RemainingRequests != nil {
+		header.Set(HeaderReset, strconv.FormatInt(summary.PeriodEnd.Unix(), 10))
+	}
+}
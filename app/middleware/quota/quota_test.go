@@ -0,0 +1,67 @@
+package quota_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+	"es-api/app/internal/usecase"
+	"es-api/app/middleware/quota"
+	appmock "es-api/app/test/mock/usecase"
+)
+
+func TestQuotaMiddleware(t *testing.T) {
+	periodEnd := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	next := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+
+	t.Run("正常系:上限内の場合は残量をヘッダーに付与して処理を続ける", func(t *testing.T) {
+		remainingTokens := int64(800)
+		mockUsecase := new(appmock.UsageUsecaseMock)
+		mockUsecase.On("CheckQuota", testifymock.Anything).Return(&model.UsageSummary{
+			PeriodEnd:       periodEnd,
+			TokenQuota:      1000,
+			RemainingTokens: &remainingTokens,
+		}, nil)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/generate", nil), rec)
+		c.Set("userID", "test-user")
+
+		err := quota.QuotaMiddleware(mockUsecase)(next)(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1000", rec.Header().Get(quota.HeaderTokensLimit))
+		assert.Equal(t, "800", rec.Header().Get(quota.HeaderTokensRemaining))
+		assert.Empty(t, rec.Header().Get(quota.HeaderRequestsRemaining))
+		assert.Equal(t, "1743465600", rec.Header().Get(quota.HeaderReset))
+	})
+
+	t.Run("異常系:上限に達している場合は429を返す", func(t *testing.T) {
+		remainingRequests := int64(0)
+		mockUsecase := new(appmock.UsageUsecaseMock)
+		mockUsecase.On("CheckQuota", testifymock.Anything).Return(&model.UsageSummary{
+			PeriodEnd:         periodEnd,
+			RequestQuota:      10,
+			RemainingRequests: &remainingRequests,
+		}, usecase.ErrQuotaExceeded)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/generate", nil), rec)
+		c.Set("userID", "test-user")
+
+		err := quota.QuotaMiddleware(mockUsecase)(next)(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "0", rec.Header().Get(quota.HeaderRequestsRemaining))
+	})
+}
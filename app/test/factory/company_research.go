@@ -12,9 +12,11 @@ func CreateCompanyResearch(t *testing.T, db *gorm.DB) *model.CompanyResearch {
 	research := &model.CompanyResearch{
 		CompanyID:   "1234567890123",
 		CompanyName: "テスト株式会社",
-		Philosophy:  "テスト企業理念",
-		CareerPath:  "テストキャリアパス",
-		TalentNeeds: "テスト求める人材像",
+		Contents: map[model.ResearchSection]string{
+			model.ResearchSectionPhilosophy:  "テスト企業理念",
+			model.ResearchSectionCareerPath:  "テストキャリアパス",
+			model.ResearchSectionTalentNeeds: "テスト求める人材像",
+		},
 	}
 
 	err := db.Create(research).Error
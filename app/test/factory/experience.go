@@ -22,6 +22,7 @@ func CreateExperience1(t *testing.T, dbConn *gorm.DB) model.Experiences {
 		Skills:      "skills",
 		SelfPR:      "selfPR",
 		FutureGoals: "futureGoals",
+		IsDefault:   true,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -41,6 +42,7 @@ func CreateExperience2(t *testing.T, dbConn *gorm.DB) model.Experiences {
 		Skills:      "skills2",
 		SelfPR:      "selfPR2",
 		FutureGoals: "futureGoals2",
+		IsDefault:   true,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
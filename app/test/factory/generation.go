@@ -0,0 +1,37 @@
+package factory
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"es-api/app/internal/entity/model"
+)
+
+const (
+	DummyGenerationID1 = "223e4567-e89b-12d3-a456-426614174000"
+)
+
+func CreateGeneration1(t *testing.T, dbConn *gorm.DB) model.Generations {
+	generation := model.Generations{
+		ID:          DummyGenerationID1,
+		UserID:      DummyUserID1,
+		CompanyID:   "1234567890123",
+		CompanyName: "テスト株式会社",
+		Model:       string(model.GeminiFlashLite),
+		HTML:        "<textarea></textarea>",
+		Questions:   []string{"志望動機を教えてください。"},
+		Answers: []model.LLMGeneratedResponse{
+			{Question: "志望動機を教えてください。", Answer: "回答", Status: model.LLMAnswerStatusSucceeded},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := dbConn.Create(&generation).Error; err != nil {
+		t.Fatalf("Error creating test generation: %v", err)
+	}
+
+	return generation
+}
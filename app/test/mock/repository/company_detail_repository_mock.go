@@ -0,0 +1,26 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CompanyDetailRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *CompanyDetailRepositoryMock) FindByCorporateNumber(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error) {
+	args := m.Called(ctx, corporateNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CompanyDetails), args.Error(1)
+}
+
+func (m *CompanyDetailRepositoryMock) Upsert(ctx context.Context, details *model.CompanyDetails) error {
+	args := m.Called(ctx, details)
+	return args.Error(0)
+}
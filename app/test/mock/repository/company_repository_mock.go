@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CompanyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *CompanyRepositoryMock) Search(ctx context.Context, normalizedQuery string, limit int, offset int) ([]model.Companies, error) {
+	args := m.Called(ctx, normalizedQuery, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Companies), args.Error(1)
+}
+
+func (m *CompanyRepositoryMock) UpsertMany(ctx context.Context, companies []model.Companies) error {
+	args := m.Called(ctx, companies)
+	return args.Error(0)
+}
+
+func (m *CompanyRepositoryMock) FindSearchQuery(ctx context.Context, normalizedQuery string) (*model.CompanySearchQueries, error) {
+	args := m.Called(ctx, normalizedQuery)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CompanySearchQueries), args.Error(1)
+}
+
+func (m *CompanyRepositoryMock) SaveSearchQuery(ctx context.Context, normalizedQuery string) error {
+	args := m.Called(ctx, normalizedQuery)
+	return args.Error(0)
+}
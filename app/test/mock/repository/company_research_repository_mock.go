@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CompanyResearchRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *CompanyResearchRepositoryMock) FindByCompanyID(ctx context.Context, companyID string) (*model.CompanyResearch, error) {
+	args := m.Called(ctx, companyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CompanyResearch), args.Error(1)
+}
+
+func (m *CompanyResearchRepositoryMock) Create(ctx context.Context, research *model.CompanyResearch) error {
+	args := m.Called(ctx, research)
+	return args.Error(0)
+}
+
+func (m *CompanyResearchRepositoryMock) Upsert(ctx context.Context, research *model.CompanyResearch) error {
+	args := m.Called(ctx, research)
+	return args.Error(0)
+}
+
+func (m *CompanyResearchRepositoryMock) MarkSearched(ctx context.Context, companyID string, searchedAt time.Time) error {
+	args := m.Called(ctx, companyID, searchedAt)
+	return args.Error(0)
+}
+
+func (m *CompanyResearchRepositoryMock) DeleteByCompanyID(ctx context.Context, companyID string) error {
+	args := m.Called(ctx, companyID)
+	return args.Error(0)
+}
@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"es-api/app/internal/entity/model"
+)
+
+type ExperienceEntryRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ExperienceEntryRepositoryMock) ListEpisodes(ctx context.Context, profileID string) ([]model.ExperienceEpisodes, error) {
+	args := m.Called(ctx, profileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExperienceEpisodes), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) CreateEpisode(ctx context.Context, profileID string, input model.InputEpisode) (model.ExperienceEpisodes, error) {
+	args := m.Called(ctx, profileID, input)
+	return args.Get(0).(model.ExperienceEpisodes), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) UpdateEpisode(ctx context.Context, episodeID string, input model.InputEpisode) (model.ExperienceEpisodes, error) {
+	args := m.Called(ctx, episodeID, input)
+	return args.Get(0).(model.ExperienceEpisodes), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) DeleteEpisode(ctx context.Context, episodeID string) error {
+	args := m.Called(ctx, episodeID)
+	return args.Error(0)
+}
+
+func (m *ExperienceEntryRepositoryMock) ListSkills(ctx context.Context, profileID string) ([]model.ExperienceSkills, error) {
+	args := m.Called(ctx, profileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExperienceSkills), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) CreateSkill(ctx context.Context, profileID string, input model.InputSkill) (model.ExperienceSkills, error) {
+	args := m.Called(ctx, profileID, input)
+	return args.Get(0).(model.ExperienceSkills), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) UpdateSkill(ctx context.Context, skillID string, input model.InputSkill) (model.ExperienceSkills, error) {
+	args := m.Called(ctx, skillID, input)
+	return args.Get(0).(model.ExperienceSkills), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) DeleteSkill(ctx context.Context, skillID string) error {
+	args := m.Called(ctx, skillID)
+	return args.Error(0)
+}
+
+func (m *ExperienceEntryRepositoryMock) ListAchievements(ctx context.Context, profileID string) ([]model.ExperienceAchievements, error) {
+	args := m.Called(ctx, profileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExperienceAchievements), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) CreateAchievement(ctx context.Context, profileID string, input model.InputAchievement) (model.ExperienceAchievements, error) {
+	args := m.Called(ctx, profileID, input)
+	return args.Get(0).(model.ExperienceAchievements), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) UpdateAchievement(ctx context.Context, achievementID string, input model.InputAchievement) (model.ExperienceAchievements, error) {
+	args := m.Called(ctx, achievementID, input)
+	return args.Get(0).(model.ExperienceAchievements), args.Error(1)
+}
+
+func (m *ExperienceEntryRepositoryMock) DeleteAchievement(ctx context.Context, achievementID string) error {
+	args := m.Called(ctx, achievementID)
+	return args.Error(0)
+}
@@ -31,3 +31,36 @@ func (m *ExperienceRepositoryMock) PatchExperience(ctx context.Context, experien
 	args := m.Called(ctx, experience)
 	return args.Get(0).(model.Experiences), args.Error(1)
 }
+
+func (m *ExperienceRepositoryMock) ListExperiences(ctx context.Context) ([]model.Experiences, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Experiences), args.Error(1)
+}
+
+func (m *ExperienceRepositoryMock) GetExperienceByID(ctx context.Context, profileID string) (model.Experiences, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).(model.Experiences), args.Error(1)
+}
+
+func (m *ExperienceRepositoryMock) CreateExperience(ctx context.Context, experience model.InputExperience, isDefault bool) (model.Experiences, error) {
+	args := m.Called(ctx, experience, isDefault)
+	return args.Get(0).(model.Experiences), args.Error(1)
+}
+
+func (m *ExperienceRepositoryMock) UpdateExperience(ctx context.Context, profileID string, experience model.InputExperience) (model.Experiences, error) {
+	args := m.Called(ctx, profileID, experience)
+	return args.Get(0).(model.Experiences), args.Error(1)
+}
+
+func (m *ExperienceRepositoryMock) DeleteExperience(ctx context.Context, profileID string) error {
+	args := m.Called(ctx, profileID)
+	return args.Error(0)
+}
+
+func (m *ExperienceRepositoryMock) SetDefaultExperience(ctx context.Context, profileID string) (model.Experiences, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).(model.Experiences), args.Error(1)
+}
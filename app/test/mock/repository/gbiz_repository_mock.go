@@ -19,3 +19,11 @@ func (m *GBizInfoRepositoryMock) SearchCompanies(ctx context.Context, keyword st
 	}
 	return args.Get(0).([]model.CompanyBasicInfo), args.Error(1)
 }
+
+func (m *GBizInfoRepositoryMock) GetCompany(ctx context.Context, corporateNumber string) (*model.CompanyDetails, error) {
+	args := m.Called(ctx, corporateNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CompanyDetails), args.Error(1)
+}
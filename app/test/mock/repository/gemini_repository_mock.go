@@ -16,3 +16,11 @@ func (m *GeminiRepositoryMock) GetGeminiRequest(ctx context.Context, input model
 	args := m.Called(ctx, input)
 	return args.Get(0).(model.GeminiResponse), args.Error(1)
 }
+
+func (m *GeminiRepositoryMock) EmbedContents(ctx context.Context, embeddingModel string, texts []string) ([][]float32, error) {
+	args := m.Called(ctx, embeddingModel, texts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]float32), args.Error(1)
+}
@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type GenerationJobRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *GenerationJobRepositoryMock) Create(ctx context.Context, job *model.GenerationJobs) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *GenerationJobRepositoryMock) FindByID(ctx context.Context, id string) (*model.GenerationJobs, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.GenerationJobs), args.Error(1)
+}
+
+func (m *GenerationJobRepositoryMock) RequestCancel(ctx context.Context, id string) (*model.GenerationJobs, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.GenerationJobs), args.Error(1)
+}
+
+func (m *GenerationJobRepositoryMock) ClaimNext(ctx context.Context) (*model.GenerationJobs, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.GenerationJobs), args.Error(1)
+}
+
+func (m *GenerationJobRepositoryMock) Heartbeat(ctx context.Context, id string, attempt int) (bool, error) {
+	args := m.Called(ctx, id, attempt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *GenerationJobRepositoryMock) UpdateProgress(ctx context.Context, id string, attempt int, progress []model.GenerationJobQuestion) error {
+	args := m.Called(ctx, id, attempt, progress)
+	return args.Error(0)
+}
+
+func (m *GenerationJobRepositoryMock) Finish(ctx context.Context, id string, attempt int, status model.GenerationJobStatus, answers []model.LLMGeneratedResponse, errMsg string) error {
+	args := m.Called(ctx, id, attempt, status, answers, errMsg)
+	return args.Error(0)
+}
+
+func (m *GenerationJobRepositoryMock) RequeueStale(ctx context.Context, staleBefore time.Time, maxAttempts int) (int64, error) {
+	args := m.Called(ctx, staleBefore, maxAttempts)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type GenerationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *GenerationRepositoryMock) Create(ctx context.Context, generation *model.Generations) error {
+	args := m.Called(ctx, generation)
+	return args.Error(0)
+}
+
+func (m *GenerationRepositoryMock) ListByUserID(ctx context.Context, limit int, offset int) ([]model.Generations, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Generations), args.Error(1)
+}
+
+func (m *GenerationRepositoryMock) FindByID(ctx context.Context, id string) (*model.Generations, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Generations), args.Error(1)
+}
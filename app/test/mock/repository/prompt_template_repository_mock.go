@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PromptTemplateRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PromptTemplateRepositoryMock) ListByName(ctx context.Context, name model.PromptTemplateName) ([]model.PromptTemplates, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PromptTemplates), args.Error(1)
+}
+
+func (m *PromptTemplateRepositoryMock) FindActive(ctx context.Context, name model.PromptTemplateName) (*model.PromptTemplates, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PromptTemplates), args.Error(1)
+}
+
+func (m *PromptTemplateRepositoryMock) Create(ctx context.Context, template *model.PromptTemplates) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *PromptTemplateRepositoryMock) Activate(ctx context.Context, name model.PromptTemplateName, version int) (*model.PromptTemplates, error) {
+	args := m.Called(ctx, name, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PromptTemplates), args.Error(1)
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type QuestionCacheRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *QuestionCacheRepositoryMock) FindByHTMLHash(ctx context.Context, htmlHash string) (*model.QuestionCaches, error) {
+	args := m.Called(ctx, htmlHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.QuestionCaches), args.Error(1)
+}
+
+func (m *QuestionCacheRepositoryMock) FindLatestByPageURL(ctx context.Context, pageURL string) (*model.QuestionCaches, error) {
+	args := m.Called(ctx, pageURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.QuestionCaches), args.Error(1)
+}
+
+func (m *QuestionCacheRepositoryMock) Upsert(ctx context.Context, cache *model.QuestionCaches) error {
+	args := m.Called(ctx, cache)
+	return args.Error(0)
+}
@@ -0,0 +1,25 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SearchProviderMock struct {
+	mock.Mock
+}
+
+func (m *SearchProviderMock) Name() string {
+	return "mock"
+}
+
+func (m *SearchProviderMock) SearchWithAnswer(ctx context.Context, query string) (*model.SearchResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SearchResult), args.Error(1)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type UsageRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *UsageRepositoryMock) Create(ctx context.Context, usages []model.LLMUsages) error {
+	args := m.Called(ctx, usages)
+	return args.Error(0)
+}
+
+func (m *UsageRepositoryMock) SumByModel(ctx context.Context, since time.Time) ([]model.LLMUsageByModel, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LLMUsageByModel), args.Error(1)
+}
+
+func (m *UsageRepositoryMock) CountRequests(ctx context.Context, since time.Time) (int64, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).(int64), args.Error(1)
+}
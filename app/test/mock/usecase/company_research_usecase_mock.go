@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CompanyResearchUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *CompanyResearchUsecaseMock) GetResearch(ctx context.Context, companyID string) (*model.CompanyResearch, error) {
+	args := m.Called(ctx, companyID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.CompanyResearch), args.Error(1)
+}
+
+func (m *CompanyResearchUsecaseMock) RefreshResearch(ctx context.Context, companyID string, companyName string) (*model.CompanyResearch, error) {
+	args := m.Called(ctx, companyID, companyName)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.CompanyResearch), args.Error(1)
+}
+
+func (m *CompanyResearchUsecaseMock) DeleteResearch(ctx context.Context, companyID string) error {
+	args := m.Called(ctx, companyID)
+	return args.Error(0)
+}
@@ -0,0 +1,118 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ExperienceEntryUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *ExperienceEntryUsecaseMock) ListEpisodes(ctx context.Context, profileID string) ([]model.ExperienceEpisodes, error) {
+	args := m.Called(ctx, profileID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.ExperienceEpisodes), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) CreateEpisode(ctx context.Context, profileID string, input model.InputEpisode) (*model.ExperienceEpisodes, error) {
+	args := m.Called(ctx, profileID, input)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ExperienceEpisodes), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) UpdateEpisode(ctx context.Context, episodeID string, input model.InputEpisode) (*model.ExperienceEpisodes, error) {
+	args := m.Called(ctx, episodeID, input)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ExperienceEpisodes), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) DeleteEpisode(ctx context.Context, episodeID string) error {
+	args := m.Called(ctx, episodeID)
+	return args.Error(0)
+}
+
+func (m *ExperienceEntryUsecaseMock) ListSkills(ctx context.Context, profileID string) ([]model.ExperienceSkills, error) {
+	args := m.Called(ctx, profileID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.ExperienceSkills), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) CreateSkill(ctx context.Context, profileID string, input model.InputSkill) (*model.ExperienceSkills, error) {
+	args := m.Called(ctx, profileID, input)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ExperienceSkills), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) UpdateSkill(ctx context.Context, skillID string, input model.InputSkill) (*model.ExperienceSkills, error) {
+	args := m.Called(ctx, skillID, input)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ExperienceSkills), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) DeleteSkill(ctx context.Context, skillID string) error {
+	args := m.Called(ctx, skillID)
+	return args.Error(0)
+}
+
+func (m *ExperienceEntryUsecaseMock) ListAchievements(ctx context.Context, profileID string) ([]model.ExperienceAchievements, error) {
+	args := m.Called(ctx, profileID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.ExperienceAchievements), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) CreateAchievement(ctx context.Context, profileID string, input model.InputAchievement) (*model.ExperienceAchievements, error) {
+	args := m.Called(ctx, profileID, input)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ExperienceAchievements), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) UpdateAchievement(ctx context.Context, achievementID string, input model.InputAchievement) (*model.ExperienceAchievements, error) {
+	args := m.Called(ctx, achievementID, input)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ExperienceAchievements), args.Error(1)
+}
+
+func (m *ExperienceEntryUsecaseMock) DeleteAchievement(ctx context.Context, achievementID string) error {
+	args := m.Called(ctx, achievementID)
+	return args.Error(0)
+}
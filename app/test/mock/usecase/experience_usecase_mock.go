@@ -31,3 +31,48 @@ func (m *ExperienceUsecaseMock) PostExperience(ctx context.Context, inputExperie
 
 	return args.Get(0).(*model.Experiences), args.Error(1)
 }
+
+func (m *ExperienceUsecaseMock) CreateProfile(ctx context.Context, inputExperience model.InputExperience) (*model.Experiences, error) {
+	args := m.Called(ctx, inputExperience)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Experiences), args.Error(1)
+}
+
+func (m *ExperienceUsecaseMock) UpdateProfile(ctx context.Context, profileID string, inputExperience model.InputExperience) (*model.Experiences, error) {
+	args := m.Called(ctx, profileID, inputExperience)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Experiences), args.Error(1)
+}
+
+func (m *ExperienceUsecaseMock) SetDefaultProfile(ctx context.Context, profileID string) (*model.Experiences, error) {
+	args := m.Called(ctx, profileID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Experiences), args.Error(1)
+}
+
+func (m *ExperienceUsecaseMock) ListProfiles(ctx context.Context) ([]model.Experiences, error) {
+	args := m.Called(ctx)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.Experiences), args.Error(1)
+}
+
+func (m *ExperienceUsecaseMock) DeleteProfile(ctx context.Context, profileID string) error {
+	args := m.Called(ctx, profileID)
+	return args.Error(0)
+}
@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type GenerationJobUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *GenerationJobUsecaseMock) CreateJob(ctx context.Context, req model.LLMGenerateRequest) (*model.GenerationJobs, error) {
+	args := m.Called(ctx, req)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.GenerationJobs), args.Error(1)
+}
+
+func (m *GenerationJobUsecaseMock) GetJob(ctx context.Context, id string) (*model.GenerationJobs, error) {
+	args := m.Called(ctx, id)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.GenerationJobs), args.Error(1)
+}
+
+func (m *GenerationJobUsecaseMock) CancelJob(ctx context.Context, id string) (*model.GenerationJobs, error) {
+	args := m.Called(ctx, id)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.GenerationJobs), args.Error(1)
+}
@@ -0,0 +1,83 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LLMGenerateUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *LLMGenerateUsecaseMock) LLMGenerate(ctx context.Context, req model.LLMGenerateRequest) ([]model.LLMGeneratedResponse, error) {
+	args := m.Called(ctx, req)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.LLMGeneratedResponse), args.Error(1)
+}
+
+// LLMGenerateStream は登録されたイベントを順番にonEventへ渡す
+func (m *LLMGenerateUsecaseMock) LLMGenerateStream(ctx context.Context, req model.LLMGenerateRequest, onEvent func(model.LLMGenerateEvent)) error {
+	args := m.Called(ctx, req, onEvent)
+
+	if events, ok := args.Get(0).([]model.LLMGenerateEvent); ok {
+		for _, event := range events {
+			onEvent(event)
+		}
+	}
+
+	return args.Error(1)
+}
+
+func (m *LLMGenerateUsecaseMock) RefineAnswer(ctx context.Context, req model.LLMRefineRequest) (*model.LLMRefineResponse, error) {
+	args := m.Called(ctx, req)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.LLMRefineResponse), args.Error(1)
+}
+
+func (m *LLMGenerateUsecaseMock) ReviewAnswer(ctx context.Context, req model.LLMReviewRequest) (*model.LLMReviewResponse, error) {
+	args := m.Called(ctx, req)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.LLMReviewResponse), args.Error(1)
+}
+
+// LLMGenerateBackground は登録されたイベントを順番にonEventへ渡してから結果を返す
+func (m *LLMGenerateUsecaseMock) LLMGenerateBackground(ctx context.Context, req model.LLMGenerateRequest, onEvent func(model.LLMGenerateEvent)) ([]model.LLMGeneratedResponse, error) {
+	args := m.Called(ctx, req, onEvent)
+
+	if events, ok := args.Get(0).([]model.LLMGenerateEvent); ok {
+		for _, event := range events {
+			onEvent(event)
+		}
+	}
+
+	if args.Get(1) == nil {
+		return nil, args.Error(2)
+	}
+
+	return args.Get(1).([]model.LLMGeneratedResponse), args.Error(2)
+}
+
+func (m *LLMGenerateUsecaseMock) CachedQuestions(ctx context.Context, req model.LLMCachedQuestionsRequest) (*model.QuestionCaches, error) {
+	args := m.Called(ctx, req)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.QuestionCaches), args.Error(1)
+}
@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PromptTemplateUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *PromptTemplateUsecaseMock) ListVersions(ctx context.Context, name model.PromptTemplateName) ([]model.PromptTemplates, error) {
+	args := m.Called(ctx, name)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.PromptTemplates), args.Error(1)
+}
+
+func (m *PromptTemplateUsecaseMock) CreateVersion(ctx context.Context, name model.PromptTemplateName, req model.CreatePromptTemplateRequest) (*model.PromptTemplates, error) {
+	args := m.Called(ctx, name, req)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.PromptTemplates), args.Error(1)
+}
+
+func (m *PromptTemplateUsecaseMock) ActivateVersion(ctx context.Context, name model.PromptTemplateName, version int) (*model.PromptTemplates, error) {
+	args := m.Called(ctx, name, version)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.PromptTemplates), args.Error(1)
+}
+
+func (m *PromptTemplateUsecaseMock) Rollback(ctx context.Context, name model.PromptTemplateName) (*model.PromptTemplates, error) {
+	args := m.Called(ctx, name)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.PromptTemplates), args.Error(1)
+}
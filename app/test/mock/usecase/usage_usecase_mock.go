@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"context"
+
+	"es-api/app/internal/entity/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type UsageUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *UsageUsecaseMock) GetUsage(ctx context.Context) (*model.UsageSummary, error) {
+	args := m.Called(ctx)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UsageSummary), args.Error(1)
+}
+
+func (m *UsageUsecaseMock) CheckQuota(ctx context.Context) (*model.UsageSummary, error) {
+	args := m.Called(ctx)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UsageSummary), args.Error(1)
+}